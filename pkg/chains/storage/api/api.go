@@ -35,10 +35,24 @@ type StoreRequest[Input any, Output any] struct {
 
 // StoreResponse contains metadata for the result of the store operation.
 type StoreResponse struct {
-	// currently empty, but may contain data in the future.
-	// present to allow for backwards compatible changes to the Storer interface in the future.
+	// Reference is the fully-qualified reference the object was stored at, if the
+	// storage backend has one (e.g. the tag of an OCI signature or attestation manifest).
+	Reference string
+	// Digest is the digest of the uploaded manifest, if the storage backend has one.
+	Digest string
+	// AlreadyExists is set if an identical object was already stored and the
+	// upload was skipped.
+	AlreadyExists bool
+	// Repositories are the repositories the object was stored in, if the
+	// storage backend writes to several (e.g. mirror repositories).
+	Repositories []string
 }
 
 type Storer[Input, Output any] interface {
 	Store(context.Context, *StoreRequest[Input, Output]) (*StoreResponse, error)
 }
+
+// Retriever reads back the objects stored for an artifact by a Storer.
+type Retriever[Input, Output any] interface {
+	Retrieve(context.Context, Input) ([]Output, error)
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestWithAnnotations(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	ref := pushRandomImage(t, newTestRegistry(t, nil))
+	opts := []Option{
+		WithAnnotations(map[string]string{
+			"tekton.dev/pipelineRun": "build-1",
+			"example.com/team":       "platform",
+		}),
+		WithAnnotations(map[string]string{
+			"example.com/team":   "release",
+			"example.com/commit": "0123456789abcdef",
+		}),
+	}
+	want := map[string]string{
+		"tekton.dev/pipelineRun": "build-1",
+		"example.com/team":       "release",
+		"example.com/commit":     "0123456789abcdef",
+	}
+
+	as, err := NewAttestationStorer(opts[0], opts[1])
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	statement, payload := newTestStatement(t, ref, "https://example.com/predicate")
+	if _, err := as.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  statement,
+		Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+	}); err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+	ss, err := NewSimpleStorerFromConfig(opts[0], opts[1])
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	if _, err := ss.Store(ctx, &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+		Artifact: ref,
+		Payload:  simple.NewSimpleStruct(ref),
+		Bundle:   &signing.Bundle{Content: []byte("{}"), Signature: []byte("signature")},
+	}); err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+
+	se, err := ociremote.SignedEntity(ref)
+	if err != nil {
+		t.Fatalf("failed to get signed entity: %v", err)
+	}
+	atts, err := se.Attestations()
+	if err != nil {
+		t.Fatalf("failed to get attestations: %v", err)
+	}
+	sigs, err := se.Signatures()
+	if err != nil {
+		t.Fatalf("failed to get signatures: %v", err)
+	}
+	for what, s := range map[string]oci.Signatures{"attestation": atts, "signature": sigs} {
+		layers, err := s.Get()
+		if err != nil || len(layers) != 1 {
+			t.Fatalf("failed to get the %s: %d layers, %v", what, len(layers), err)
+		}
+		ann, err := layers[0].Annotations()
+		if err != nil {
+			t.Fatalf("failed to read %s annotations: %v", what, err)
+		}
+		for k, v := range want {
+			if ann[k] != v {
+				t.Errorf("%s annotation %s = %q, want %q", what, k, ann[k], v)
+			}
+		}
+		if what == "signature" && ann[static.SignatureAnnotationKey] == "" {
+			t.Errorf("signature annotation %s is missing", static.SignatureAnnotationKey)
+		}
+	}
+}
+
+func TestWithAnnotations_Reserved(t *testing.T) {
+	for _, key := range []string{
+		"",
+		static.SignatureAnnotationKey,
+		static.CertificateAnnotationKey,
+		splitGroupAnnotation,
+		predicateSchemaAnnotation,
+		builderIDAnnotation,
+	} {
+		if _, err := NewAttestationStorer(WithAnnotations(map[string]string{key: "value"})); err == nil {
+			t.Errorf("expected an error for annotation %q", key)
+		}
+	}
+}
@@ -15,17 +15,22 @@
 package oci
 
 import (
+	"bytes"
 	"context"
+	"maps"
 
 	"github.com/google/go-containerregistry/pkg/name"
-	"github.com/google/go-containerregistry/pkg/v1/remote"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	intoto "github.com/in-toto/attestation/go/v1"
 	"github.com/pkg/errors"
+	"github.com/sigstore/cosign/v2/pkg/oci"
 	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
 	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
 	"github.com/sigstore/cosign/v2/pkg/oci/static"
 	"github.com/sigstore/cosign/v2/pkg/types"
+	"github.com/sigstore/sigstore/pkg/signature"
 	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	"google.golang.org/protobuf/encoding/protojson"
 	"knative.dev/pkg/logging"
 )
 
@@ -35,58 +40,444 @@ var (
 
 // AttestationStorer stores in-toto Attestation payloads in OCI registries.
 type AttestationStorer struct {
-	// repo configures the repo where data should be stored.
-	// If empty, the repo is inferred from the Artifact.
-	repo *name.Repository
-	// remoteOpts are additional remote options (i.e. auth) to use for client operations.
-	remoteOpts []remote.Option
+	baseStorer
+	// equivalentSubjects must be recorded by the subjects of the stored
+	// Statement.
+	equivalentSubjects []*intoto.ResourceDescriptor
+	// validateSubject, if set, checks that a subject of the statement has
+	// the digest of the artifact.
+	validateSubject bool
+	// maintainLatestPointer, if set, points the <.att tag>.latest tag at the
+	// newest attestation on each store.
+	maintainLatestPointer bool
+	// canonicalizer, if set, is the serialization of statements expected by
+	// verifiers.
+	canonicalizer Canonicalizer
+	// deleteMigratedTags, if set, deletes the .att manifest once Migrate has
+	// written its attestations to referrers.
+	deleteMigratedTags bool
+	// subjectMatch controls when two subjects are considered the same.
+	subjectMatch SubjectMatchMode
+	// mirror, if set, receives the request after it is stored in the registry.
+	mirror *metadataMirror
+	// transparencyIndex, if set, records stored attestations in the repository's transparency index.
+	transparencyIndex *transparencyIndexer
+	// transparencyIndexSigner signs the tree heads of the transparency index.
+	transparencyIndexSigner signature.SignerVerifier
+	// payloadSplitSize, if positive, is the size above which payloads are split across layers.
+	payloadSplitSize int64
+	// predicateSchemas maps predicate types to the URL of their JSON schema.
+	predicateSchemas map[string]string
+	// parentAttestations are the attestation manifests that stored
+	// attestations are derived from.
+	parentAttestations []name.Digest
+	// longAnnotations, if set, shortens over-limit predicate type annotations.
+	longAnnotations *longAnnotations
+	// sortReferrers, if set, sorts the retrieved referrers newest first.
+	sortReferrers bool
+	// configBlob, if set, is the config of the attestation manifests.
+	configBlob *configBlob
+	// skipIfExists skips the upload if an identical attestation is already stored.
+	skipIfExists bool
+	// idempotencyKey, if set, skips the upload if an attestation was already
+	// stored with the same key.
+	idempotencyKey string
+	// fanoutToChildren also stores attestations of image indexes against
+	// each of their children.
+	fanoutToChildren bool
+	// hostStorers store the subjects on the registry hosts with a host policy.
+	hostStorers map[string]*AttestationStorer
+	// mirrorStorers store in the mirror repositories, in order.
+	mirrorStorers []*AttestationStorer
 }
 
 func NewAttestationStorer(opts ...AttestationStorerOption) (*AttestationStorer, error) {
 	s := &AttestationStorer{}
-	for _, o := range opts {
+	for i, o := range opts {
 		if err := o.applyAttestationStorer(s); err != nil {
-			return nil, err
+			return nil, errors.Wrapf(err, "applying option %d (%T)", i, o)
+		}
+	}
+	if err := s.checkLocalLayout(); err != nil {
+		return nil, err
+	}
+	if s.localLayout != "" && s.transparencyIndex != nil {
+		return nil, errors.New("the transparency index is not supported with a local OCI layout")
+	}
+	if s.transparencyIndex != nil {
+		if s.transparencyIndexSigner == nil {
+			return nil, errors.New("the transparency index requires a signer, set with WithTransparencyIndexSigner")
+		}
+		s.transparencyIndex.signer = s.transparencyIndexSigner
+	}
+	if s.localLayout != "" && s.maintainLatestPointer {
+		return nil, errors.New("the latest attestation pointer is not supported with a local OCI layout")
+	}
+	for host, p := range s.hostPolicies {
+		hostOpts := withoutHostPolicies(opts)
+		if s.storeLimiter != nil {
+			// Share the limit of the storer, unless the policy sets its own.
+			hostOpts = append(hostOpts, WithStoreLimiter(s.storeLimiter))
+		}
+		for _, o := range p.Options {
+			hostOpts = append(hostOpts, o)
+		}
+		hs, err := NewAttestationStorer(hostOpts...)
+		if err != nil {
+			return nil, errors.Wrapf(err, "applying host policy for %s", host)
+		}
+		if s.hostStorers == nil {
+			s.hostStorers = map[string]*AttestationStorer{}
 		}
+		s.hostStorers[host] = hs
+	}
+	for _, m := range s.mirrors {
+		mirrorOpts := mirrorOptions(opts, m)
+		if s.storeLimiter != nil {
+			mirrorOpts = append(mirrorOpts, WithStoreLimiter(s.storeLimiter))
+		}
+		ms, err := NewAttestationStorer(mirrorOpts...)
+		if err != nil {
+			return nil, errors.Wrapf(err, "applying mirror %s", m.Repository.String())
+		}
+		s.mirrorStorers = append(s.mirrorStorers, ms)
 	}
 	return s, nil
 }
 
-// Store saves the given statement.
+// Store saves the given statement. It is appended to the attestations already
+// attached to the artifact, whatever their predicate type: the .att manifest
+// is rewritten with the existing attestations and the new one. The DSSE
+// envelope of the bundle is stored as is, so an envelope signed with several
+// keys keeps all of its signatures in a single attestation.
 func (s *AttestationStorer) Store(ctx context.Context, req *api.StoreRequest[name.Digest, *intoto.Statement]) (*api.StoreResponse, error) {
+	if hs := s.hostStorer(req.Artifact); hs != nil {
+		return hs.Store(ctx, req)
+	}
+	if err := s.checkRequest(req); err != nil {
+		return nil, err
+	}
+	return s.runStore(ctx, req, func(ctx context.Context) (*api.StoreResponse, error) {
+		return s.store(ctx, req)
+	})
+}
+
+// checkRequest validates req before anything is written.
+func (s *AttestationStorer) checkRequest(req *api.StoreRequest[name.Digest, *intoto.Statement]) error {
+	if req.Bundle == nil {
+		return ErrMissingBundle
+	}
+	if err := checkArtifact(req.Artifact); err != nil {
+		return err
+	}
+	return s.checkPayloadSize(req.Artifact, len(req.Bundle.Signature))
+}
+
+// runStore runs store, which writes the attestation of req, in a span, through
+// the in-process dedup, the store timeout and the mirrors, recording its
+// outcome and queueing it for a retry if it failed.
+func (s *AttestationStorer) runStore(ctx context.Context, req *api.StoreRequest[name.Digest, *intoto.Statement], store func(context.Context) (*api.StoreResponse, error)) (*api.StoreResponse, error) {
+	ctx, span := startSpan(ctx, "oci.AttestationStorer.Store", append(artifactAttributes(req.Artifact, s.targetRepository(req.Artifact)), formatAttribute.String(attestationFormat))...)
+	start := s.now()
+	resp, err := s.dedupStore(ctx, func() dedupKey {
+		return newDedupKey(req.Artifact, req.Bundle.Signature)
+	}, func() (*api.StoreResponse, error) {
+		return s.withStoreTimeout(ctx, func(ctx context.Context) (*api.StoreResponse, error) {
+			return s.storeWithMirrors(ctx, req, store)
+		})
+	})
+	s.metrics.observeStore(s.since(start), err)
+	endSpan(span, err)
+	if s.retryQueue != nil && isRetryable(err) {
+		s.enqueueRetry(ctx, req)
+	}
+	return resp, err
+}
+
+func (s *AttestationStorer) store(ctx context.Context, req *api.StoreRequest[name.Digest, *intoto.Statement], signOpts ...mutate.SignOption) (*api.StoreResponse, error) {
+	repo := s.targetRepository(req.Artifact)
+	unlock, err := s.lockEntity(ctx, req.Artifact, repo)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	se, err := s.lookupEntity(ctx, req.Artifact, repo)
+	if err != nil {
+		return nil, err
+	}
+	return s.storeWithChildren(ctx, se, req, signOpts...)
+}
+
+// storeWithChildren attaches the attestation to se, the signed entity of the
+// artifact, and, as configured with WithFanoutToChildren, to the children of
+// se if it is an index.
+func (s *AttestationStorer) storeWithChildren(ctx context.Context, se oci.SignedEntity, req *api.StoreRequest[name.Digest, *intoto.Statement], signOpts ...mutate.SignOption) (*api.StoreResponse, error) {
+	resp, err := s.storeTo(ctx, se, req, signOpts...)
+	if err != nil || !s.fanoutToChildren {
+		return resp, err
+	}
+	// The entity shared by StoreBoth is memoized, which hides whether it is
+	// an index.
+	if m, ok := se.(*memoizedEntity); ok {
+		se = m.SignedEntity
+	}
+	if idx, ok := se.(oci.SignedImageIndex); ok {
+		if err := s.storeChildren(ctx, idx, req, signOpts...); err != nil {
+			return resp, err
+		}
+	}
+	return resp, nil
+}
+
+// storeTo attaches the attestation to se, the signed entity of the artifact
+// fetched from the target repository, and writes it.
+func (s *AttestationStorer) storeTo(ctx context.Context, se oci.SignedEntity, req *api.StoreRequest[name.Digest, *intoto.Statement], signOpts ...mutate.SignOption) (*api.StoreResponse, error) {
 	logger := logging.FromContext(ctx)
 
-	repo := req.Artifact.Repository
-	if s.repo != nil {
-		repo = *s.repo
+	if s.validateSubject {
+		if err := validateSubject(req); err != nil {
+			return nil, err
+		}
 	}
-	se, err := ociremote.SignedEntity(req.Artifact, ociremote.WithRemoteOptions(s.remoteOpts...))
-	var entityNotFoundError *ociremote.EntityNotFoundError
-	if errors.As(err, &entityNotFoundError) {
-		se = ociremote.SignedUnknown(req.Artifact)
-	} else if err != nil {
-		return nil, errors.Wrap(err, "getting signed image")
+	s.checkCanonicalPayload(ctx, req)
+	if len(s.equivalentSubjects) > 0 {
+		if err := s.checkEquivalentSubjects(req); err != nil {
+			return nil, err
+		}
+	}
+
+	repo := s.targetRepository(req.Artifact)
+	if s.skipIfExists {
+		if resp, ok := s.findExisting(ctx, se, req.Artifact, repo, req.Bundle.Signature); ok {
+			logger.Infof("Attestation for %s already exists, skipping upload", req.Artifact.String())
+			return resp, nil
+		}
+	}
+	if s.idempotencyKey != "" {
+		if resp, ok := s.findIdempotent(ctx, se, req.Artifact, repo); ok {
+			logger.Infof("Attestation for %s with idempotency key %q already exists, skipping upload", req.Artifact.String(), s.idempotencyKey)
+			return resp, nil
+		}
+	}
+
+	tag, err := ociremote.AttestationTag(req.Artifact, s.tagOptions(repo)...)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAttestationTag(se, tag); err != nil {
+		return nil, err
 	}
 
 	// Create the new attestation for this entity.
 	attOpts := []static.Option{static.WithLayerMediaType(types.DssePayloadType)}
 	if req.Bundle.Cert != nil {
-		attOpts = append(attOpts, static.WithCertChain(req.Bundle.Cert, req.Bundle.Chain))
+		cert, chain, err := normalizeCertChain(req.Bundle.Cert, req.Bundle.Chain)
+		if err != nil {
+			return nil, err
+		}
+		attOpts = append(attOpts, static.WithCertChain(cert, chain))
+	}
+	if s.rekorBundle != nil {
+		attOpts = append(attOpts, static.WithBundle(s.rekorBundle))
+	}
+	if s.rfc3161Timestamp != nil {
+		attOpts = append(attOpts, static.WithRFC3161Timestamp(s.rfc3161Timestamp))
 	}
-	att, err := static.NewAttestation(req.Bundle.Signature, attOpts...)
+	annotations := maps.Clone(s.annotations)
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if s.idempotencyKey != "" {
+		annotations[idempotencyKeyAnnotation] = s.idempotencyKey
+	}
+	if err := s.addLineageAnnotations(ctx, annotations); err != nil {
+		return nil, err
+	}
+	if len(s.predicateSchemas) > 0 {
+		if schema, ok := s.predicateSchemas[predicateTypeOf(req)]; ok {
+			annotations[predicateSchemaAnnotation] = schema
+		}
+	}
+	if pt := predicateTypeOf(req); pt != "" {
+		annotations[predicateTypeAnnotation] = s.longAnnotations.shorten(pt)
+	}
+	addSBOMAnnotations(annotations, req)
+	if id, ok := builderIDOf(req); ok {
+		annotations[builderIDAnnotation] = id
+	}
+	if s.recordPayloadDigests && req.Payload != nil {
+		if id, ok := identifyEnvelope(req.Bundle.Signature); ok {
+			unsigned, err := s.marshalStatement(req.Payload)
+			if err != nil {
+				return nil, errors.Wrap(err, "marshaling the statement")
+			}
+			addPayloadDigestAnnotations(annotations, unsigned, id.payloadDigest)
+		}
+	}
+	layers, err := newAttestationLayers(req.Bundle.Signature, s.payloadSplitSize, annotations, attOpts...)
 	if err != nil {
 		return nil, err
 	}
-	newImage, err := mutate.AttachAttestationToEntity(se, att)
+	newImage := se
+	for _, att := range layers {
+		if newImage, err = mutate.AttachAttestationToEntity(newImage, att, signOpts...); err != nil {
+			return nil, err
+		}
+	}
+	newImage = &memoizedEntity{SignedEntity: s.withConfigBlob(s.withCreationTime(newImage))}
+	if s.registryLimits != nil {
+		atts, err := newImage.Attestations()
+		if err != nil {
+			return nil, err
+		}
+		if err := s.checkRegistryLimits(ctx, repo, atts); err != nil {
+			return nil, errors.Wrapf(err, "validating attestations of %s", req.Artifact.String())
+		}
+	}
+
+	atts, err := newImage.Attestations()
 	if err != nil {
 		return nil, err
 	}
+	resp, err := newStoreResponse(tag, atts)
+	if err != nil {
+		return nil, err
+	}
+	if s.dryRun {
+		logger.Infof("Dry run: would upload attestation for %s to %s (%s)", req.Artifact.String(), resp.Reference, resp.Digest)
+		return resp, nil
+	}
 
 	// Publish the signatures associated with this entity
-	if err := ociremote.WriteAttestations(repo, newImage, ociremote.WithRemoteOptions(s.remoteOpts...)); err != nil {
+	if err := s.retryWrite(ctx, "attestations of "+req.Artifact.String(), func() error {
+		if s.localLayout != "" {
+			return writeLayout(s.localLayout, tag, atts)
+		}
+		return ociremote.WriteAttestations(repo, newImage, ociremote.WithRemoteOptions(s.remoteOptions(ctx)...), ociremote.WithPrefix(s.tagPrefix))
+	}); err != nil {
 		return nil, err
 	}
-	logger.Infof("Successfully uploaded attestation for %s", req.Artifact.String())
+	if s.maintainLatestPointer {
+		if err := s.writeLatestPointer(ctx, tag, layers); err != nil {
+			return nil, errors.Wrapf(err, "updating the latest attestation pointer of %s", req.Artifact.String())
+		}
+	}
+	if s.readBack != nil {
+		if err := s.verifyWritten(ctx, tag, layers); err != nil {
+			return nil, errors.Wrapf(err, "verifying attestations of %s", req.Artifact.String())
+		}
+	}
+	if s.verifyAnnotations {
+		for _, att := range layers {
+			if err := verifyAnnotations(tag, att, s.remoteOptions(ctx)...); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if s.transparencyIndex != nil {
+		d, _, err := v1.SHA256(bytes.NewReader(req.Bundle.Signature))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := s.transparencyIndex.append(repo, d.String(), s.remoteOptions(ctx)...); err != nil {
+			return nil, err
+		}
+	}
+	if s.sampleEvent() {
+		logger.Infof("Successfully uploaded attestation for %s", req.Artifact.String())
+	}
+
+	if s.mirror != nil {
+		if _, err := s.mirror.sink.Store(ctx, req); err != nil {
+			if s.mirror.policy == MirrorFailureFail {
+				return nil, errors.Wrap(err, "mirroring attestation")
+			}
+			logger.Warnf("Failed to mirror attestation for %s: %v", req.Artifact.String(), err)
+		}
+	}
+
+	return resp, nil
+}
+
+// enqueueRetry persists a failed store to the durable retry queue.
+func (s *AttestationStorer) enqueueRetry(ctx context.Context, req *api.StoreRequest[name.Digest, *intoto.Statement]) {
+	logger := logging.FromContext(ctx)
+	e := &retryEntry{
+		Kind:     retryKindAttestation,
+		Artifact: req.Artifact.String(),
+		Bundle:   req.Bundle,
+	}
+	if req.Payload != nil {
+		b, err := protojson.Marshal(req.Payload)
+		if err != nil {
+			logger.Errorf("Failed to encode attestation for %s for retry: %v", req.Artifact.String(), err)
+			return
+		}
+		e.Payload = b
+	}
+	if err := s.retryQueue.persist(e); err != nil {
+		logger.Errorf("Failed to persist attestation for %s for retry: %v", req.Artifact.String(), err)
+	}
+}
+
+// ReplayRetryQueue re-attempts the attestation stores persisted to the durable
+// retry queue, typically on controller startup. Entries are removed once they
+// have been stored successfully and kept for a later replay otherwise.
+func (s *AttestationStorer) ReplayRetryQueue(ctx context.Context) error {
+	if s.retryQueue == nil {
+		return nil
+	}
+	paths, entries, err := s.retryQueue.entries(retryKindAttestation)
+	if err != nil {
+		return err
+	}
+	var failed int
+	for i, e := range entries {
+		if err := s.replay(ctx, e); err != nil {
+			logging.FromContext(ctx).Errorf("Failed to replay attestation for %s: %v", e.Artifact, err)
+			failed++
+			continue
+		}
+		if err := s.retryQueue.remove(paths[i]); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return errors.Errorf("replaying %d of %d queued attestations failed", failed, len(entries))
+	}
+	return nil
+}
+
+func (s *AttestationStorer) replay(ctx context.Context, e *retryEntry) error {
+	artifact, err := e.artifact()
+	if err != nil {
+		return err
+	}
+	req := &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: artifact,
+		Bundle:   e.Bundle,
+	}
+	if len(e.Payload) > 0 {
+		req.Payload = &intoto.Statement{}
+		if err := protojson.Unmarshal(e.Payload, req.Payload); err != nil {
+			return errors.Wrap(err, "decoding queued attestation")
+		}
+	}
+	if req.Bundle == nil {
+		return ErrMissingBundle
+	}
+	storer := s
+	if hs := s.hostStorer(artifact); hs != nil {
+		storer = hs
+	}
+	_, err = storer.withStoreTimeout(ctx, func(ctx context.Context) (*api.StoreResponse, error) {
+		return storer.store(ctx, req, mutate.WithDupeDetector(replayDupeDetector{}))
+	})
+	return err
+}
 
-	return &api.StoreResponse{}, nil
+// hostStorer returns the storer derived from the host policy for the
+// artifact's registry, or nil if there is none.
+func (s *AttestationStorer) hostStorer(artifact name.Digest) *AttestationStorer {
+	return s.hostStorers[artifact.RegistryStr()]
 }
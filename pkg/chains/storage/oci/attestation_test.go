@@ -15,19 +15,34 @@
 package oci
 
 import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/random"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/google/go-containerregistry/pkg/v1/types"
 	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/secure-systems-lab/go-securesystemslib/dsse"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	ctypes "github.com/sigstore/cosign/v2/pkg/types"
 	"github.com/tektoncd/chains/pkg/chains/signing"
 	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	"google.golang.org/protobuf/encoding/protojson"
 	logtesting "knative.dev/pkg/logging/testing"
 )
 
@@ -109,3 +124,326 @@ func TestAttestationStorer_Store(t *testing.T) {
 		})
 	}
 }
+
+func TestAttestationStorer_EquivalentSubjects(t *testing.T) {
+	registryName := newTestRegistry(t, nil)
+	ref := pushRandomImage(t, registryName)
+
+	mirror := fmt.Sprintf("mirror.example.com/test/img@%s", ref.DigestStr())
+	retag := "mirror.example.com/test/other@sha256:bc4f7468f87486e3835b09098c74cd7f54db2cf697cbb9b824271b95a2d0871e"
+	tests := []struct {
+		name string
+		opts []AttestationStorerOption
+		want []string
+	}{
+		{
+			name: "default matches by digest",
+			want: []string{ref.String(), retag},
+		},
+		{
+			name: "digest",
+			opts: []AttestationStorerOption{WithSubjectMatchBy(SubjectMatchDigest)},
+			want: []string{ref.String(), retag},
+		},
+		{
+			name: "name and digest",
+			opts: []AttestationStorerOption{WithSubjectMatchBy(SubjectMatchNameAndDigest)},
+			want: []string{ref.String(), mirror, retag},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]AttestationStorerOption{
+				WithTargetRepository(ref.Repository),
+				WithEquivalentSubjects([]string{ref.String(), mirror, retag, mirror}),
+			}, tt.opts...)
+			storer, err := NewAttestationStorer(opts...)
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+
+			// The subtests store to the same artifact, so each statement is
+			// told apart by its predicate type.
+			statement := &intoto.Statement{
+				Type:          intoto.StatementTypeUri,
+				Subject:       []*intoto.ResourceDescriptor{subjectFromDigest(ref)},
+				PredicateType: tt.name,
+			}
+			merged := storer.MergeEquivalentSubjects(statement)
+			if len(statement.Subject) != 1 {
+				t.Errorf("MergeEquivalentSubjects() modified the statement, got %d subjects", len(statement.Subject))
+			}
+			payload, err := protojson.Marshal(merged)
+			if err != nil {
+				t.Fatalf("failed to marshal statement: %v", err)
+			}
+			ctx := logtesting.TestContextWithLogger(t)
+			if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+				Artifact: ref,
+				Payload:  merged,
+				Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+			}); err != nil {
+				t.Fatalf("error during Store(): %v", err)
+			}
+
+			statements, err := storer.Retrieve(ctx, ref)
+			if err != nil {
+				t.Fatalf("error during Retrieve(): %v", err)
+			}
+			var got []string
+			for _, stored := range statements {
+				if stored.GetPredicateType() != tt.name {
+					continue
+				}
+				for _, s := range stored.Subject {
+					got = append(got, fmt.Sprintf("%s@sha256:%s", s.GetName(), s.GetDigest()["sha256"]))
+				}
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected stored subjects (-want +got):\n%s", diff)
+			}
+
+			// Statements signed without the equivalent subjects are rejected.
+			_, unmerged := newTestStatement(t, ref, "https://example.com/other")
+			if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+				Artifact: ref,
+				Payload:  statement,
+				Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, unmerged)},
+			}); err == nil {
+				t.Error("expected an error storing a statement without the equivalent subjects")
+			}
+
+			// The merged statement of the request does not stand in for an
+			// envelope that cannot be decoded.
+			if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+				Artifact: ref,
+				Payload:  merged,
+				Bundle:   &signing.Bundle{Signature: []byte("not an envelope")},
+			}); err == nil {
+				t.Error("expected an error storing an envelope that cannot be decoded")
+			}
+		})
+	}
+}
+
+func TestAttestationStorer_InvalidEquivalentSubject(t *testing.T) {
+	if _, err := NewAttestationStorer(WithEquivalentSubjects([]string{"example.com/img:latest"})); err == nil {
+		t.Error("expected an error for a non-digest equivalent subject")
+	}
+}
+
+func TestAttestationStorer_InvalidSubjectMatchMode(t *testing.T) {
+	if _, err := NewAttestationStorer(WithSubjectMatchBy(SubjectMatchMode(42))); err == nil {
+		t.Error("expected an error for an unknown subject match mode")
+	}
+}
+
+func TestAttestationStorer_ArtifactManifestSubject(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	registryName := newTestRegistry(t, nil)
+
+	// Push an OCI artifact manifest, e.g. one holding a previous attestation.
+	manifest := []byte(`{"mediaType":"application/vnd.oci.artifact.manifest.v1+json","artifactType":"application/vnd.dsse.envelope.v1+json","blobs":[]}`)
+	h, _, err := v1.SHA256(bytes.NewReader(manifest))
+	if err != nil {
+		t.Fatalf("failed to hash manifest: %v", err)
+	}
+	ref, err := name.NewDigest(fmt.Sprintf("%s/test/artifact@%s", registryName, h))
+	if err != nil {
+		t.Fatalf("failed to parse digest: %v", err)
+	}
+	putReq, err := http.NewRequest(http.MethodPut, fmt.Sprintf("http://%s/v2/test/artifact/manifests/%s", registryName, h), bytes.NewReader(manifest))
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	putReq.Header.Set("Content-Type", "application/vnd.oci.artifact.manifest.v1+json")
+	resp, err := http.DefaultClient.Do(putReq)
+	if err != nil {
+		t.Fatalf("failed to push artifact manifest: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("failed to push artifact manifest: status %d", resp.StatusCode)
+	}
+
+	storer, err := NewAttestationStorer()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+			Artifact: ref,
+			Payload:  &intoto.Statement{},
+			Bundle:   &signing.Bundle{Signature: []byte(fmt.Sprintf("envelope-%d", i))},
+		}); err != nil {
+			t.Fatalf("error during Store(): %v", err)
+		}
+	}
+
+	att, err := remote.Image(ref.Context().Tag(strings.ReplaceAll(h.String(), ":", "-") + ".att"))
+	if err != nil {
+		t.Fatalf("failed to fetch attestations: %v", err)
+	}
+	layers, err := att.Layers()
+	if err != nil {
+		t.Fatalf("failed to get attestation layers: %v", err)
+	}
+	if len(layers) != 2 {
+		t.Errorf("got %d attestations, want 2", len(layers))
+	}
+}
+
+func TestAttestationStorer_TrailingData(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	ref := pushRandomImage(t, newTestRegistry(t, nil))
+	storer, err := NewAttestationStorer()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+
+	// Some signers sign the statement with a trailing newline, which is lost
+	// if the statement is marshaled again.
+	statement, payload := newTestStatement(t, ref, "https://example.com/predicate")
+	payload = append(payload, '\n')
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	digest := sha256.Sum256(dsse.PAE(ctypes.IntotoPayloadType, payload))
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatalf("failed to sign statement: %v", err)
+	}
+	envelope, err := json.Marshal(dsse.Envelope{
+		PayloadType: ctypes.IntotoPayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures:  []dsse.Signature{{Sig: base64.StdEncoding.EncodeToString(sig)}},
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal envelope: %v", err)
+	}
+	if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  statement,
+		Bundle:   &signing.Bundle{Signature: envelope},
+	}); err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+
+	se, err := ociremote.SignedEntity(ref)
+	if err != nil {
+		t.Fatalf("failed to get signed entity: %v", err)
+	}
+	atts, err := se.Attestations()
+	if err != nil {
+		t.Fatalf("failed to get attestations: %v", err)
+	}
+	layers, err := atts.Get()
+	if err != nil || len(layers) != 1 {
+		t.Fatalf("failed to get the attestation: %d attestations, %v", len(layers), err)
+	}
+	stored, err := layers[0].Payload()
+	if err != nil {
+		t.Fatalf("failed to read attestation: %v", err)
+	}
+	var got dsse.Envelope
+	if err := json.Unmarshal(stored, &got); err != nil {
+		t.Fatalf("failed to decode envelope: %v", err)
+	}
+	storedPayload, err := base64.StdEncoding.DecodeString(got.Payload)
+	if err != nil {
+		t.Fatalf("failed to decode envelope payload: %v", err)
+	}
+	if !bytes.Equal(storedPayload, payload) {
+		t.Errorf("stored payload = %q, want %q", storedPayload, payload)
+	}
+	storedSig, err := base64.StdEncoding.DecodeString(got.Signatures[0].Sig)
+	if err != nil {
+		t.Fatalf("failed to decode signature: %v", err)
+	}
+	storedDigest := sha256.Sum256(dsse.PAE(got.PayloadType, storedPayload))
+	if !ecdsa.VerifyASN1(&key.PublicKey, storedDigest[:], storedSig) {
+		t.Error("stored signature does not verify the stored payload")
+	}
+}
+
+func TestAttestationStorer_AppendsPredicateTypes(t *testing.T) {
+	ref := pushRandomImage(t, newTestRegistry(t, nil))
+	// Independent tools storing against the same image, one of them caching
+	// the signed entity across stores.
+	sbom, err := NewAttestationStorer()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	provenance, err := NewAttestationStorer(WithEntityCache(time.Minute))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	storeTestAttestations(t, provenance, ref, "https://slsa.dev/provenance/v1")
+	storeTestAttestations(t, sbom, ref, "https://spdx.dev/Document")
+	storeTestAttestations(t, provenance, ref, "https://example.com/vsa")
+
+	got := listedPredicateTypes(t, sbom, ref)
+	want := []string{"https://slsa.dev/provenance/v1", "https://spdx.dev/Document", "https://example.com/vsa"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected attestations (-want +got):\n%s", diff)
+	}
+}
+
+func TestAttestationStorer_MultipleSignatures(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	ref := pushRandomImage(t, newTestRegistry(t, nil))
+	storer, err := NewAttestationStorer()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+
+	// A dual-signed envelope, e.g. by a CI key and a KMS key.
+	statement, payload := newTestStatement(t, ref, "https://slsa.dev/provenance/v1")
+	want := dsse.Envelope{
+		PayloadType: ctypes.IntotoPayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures: []dsse.Signature{
+			{KeyID: "ci", Sig: base64.StdEncoding.EncodeToString([]byte("ci signature"))},
+			{KeyID: "kms", Sig: base64.StdEncoding.EncodeToString([]byte("kms signature"))},
+		},
+	}
+	envelope, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("failed to marshal envelope: %v", err)
+	}
+	if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  statement,
+		Bundle:   &signing.Bundle{Signature: envelope},
+	}); err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+
+	se, err := ociremote.SignedEntity(ref)
+	if err != nil {
+		t.Fatalf("failed to get signed entity: %v", err)
+	}
+	atts, err := se.Attestations()
+	if err != nil {
+		t.Fatalf("failed to get attestations: %v", err)
+	}
+	sigs, err := atts.Get()
+	if err != nil {
+		t.Fatalf("failed to get attestations: %v", err)
+	}
+	if len(sigs) != 1 {
+		t.Fatalf("got %d attestations, want 1", len(sigs))
+	}
+	stored, err := sigs[0].Payload()
+	if err != nil {
+		t.Fatalf("failed to read attestation: %v", err)
+	}
+	var got dsse.Envelope
+	if err := json.Unmarshal(stored, &got); err != nil {
+		t.Fatalf("failed to unmarshal envelope: %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected envelope (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/pkg/errors"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+)
+
+// batchWorkers is the number of requests of a batch stored concurrently.
+const batchWorkers = 4
+
+// errNilRequest is the error of the nil requests of a batch.
+var errNilRequest = errors.New("store request is nil")
+
+// BatchError reports the requests of a batch that failed to be stored.
+type BatchError struct {
+	// Failures are the failed requests, by increasing index.
+	Failures []*BatchItemError
+}
+
+// Error implements error.
+func (e *BatchError) Error() string {
+	msgs := make([]string, 0, len(e.Failures))
+	for _, f := range e.Failures {
+		msgs = append(msgs, f.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the errors of the failed requests, so that errors.Is and
+// errors.As match any of them.
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failures))
+	for _, f := range e.Failures {
+		errs = append(errs, f)
+	}
+	return errs
+}
+
+// FailedIndexes returns the indexes of the failed requests in the batch.
+func (e *BatchError) FailedIndexes() []int {
+	indexes := make([]int, 0, len(e.Failures))
+	for _, f := range e.Failures {
+		indexes = append(indexes, f.Index)
+	}
+	return indexes
+}
+
+// BatchItemError is the error of a request of a batch. It wraps the error of
+// the store, which keeps its classification, e.g. ErrUnauthorized or
+// *TransientError.
+type BatchItemError struct {
+	// Index is the index of the request in the batch.
+	Index int
+	// Artifact is the artifact of the request, or empty if the request was nil.
+	Artifact string
+	// Err is the error of the store.
+	Err error
+}
+
+// Error implements error.
+func (e *BatchItemError) Error() string {
+	if errors.Is(e.Err, errNilRequest) {
+		return fmt.Sprintf("request %d is nil", e.Index)
+	}
+	if e.Artifact == "" {
+		return fmt.Sprintf("request %d: %v", e.Index, e.Err)
+	}
+	return fmt.Sprintf("request %d (%s): %v", e.Index, e.Artifact, e.Err)
+}
+
+// Unwrap returns the error of the store.
+func (e *BatchItemError) Unwrap() error {
+	return e.Err
+}
+
+// StoreBatch stores the statements with a bounded number of concurrent
+// stores. The responses are in the order of reqs, with a nil response for
+// each failed request. If any request failed, the returned error is a
+// *BatchError identifying them, so that callers can retry only the failed
+// requests.
+func (s *AttestationStorer) StoreBatch(ctx context.Context, reqs []*api.StoreRequest[name.Digest, *intoto.Statement]) ([]*api.StoreResponse, error) {
+	return storeBatch(ctx, reqs, s.Store)
+}
+
+// StoreBatch stores the signatures with a bounded number of concurrent
+// stores. The responses are in the order of reqs, with a nil response for
+// each failed request. If any request failed, the returned error is a
+// *BatchError identifying them, so that callers can retry only the failed
+// requests.
+func (s *SimpleStorer) StoreBatch(ctx context.Context, reqs []*api.StoreRequest[name.Digest, simple.SimpleContainerImage]) ([]*api.StoreResponse, error) {
+	return storeBatch(ctx, reqs, s.Store)
+}
+
+// storeBatch stores each of reqs with store, running at most batchWorkers
+// stores at once. The requests for the same artifact, whose signatures or
+// attestations are stored in the same repository, would race on the
+// read-modify-write of the same manifest, so they are stored one after the
+// other, in order, by the same worker.
+func storeBatch[T any](ctx context.Context, reqs []*api.StoreRequest[name.Digest, T], store func(context.Context, *api.StoreRequest[name.Digest, T]) (*api.StoreResponse, error)) ([]*api.StoreResponse, error) {
+	resps := make([]*api.StoreResponse, len(reqs))
+	errs := make([]*BatchItemError, len(reqs))
+
+	var groups [][]int
+	byArtifact := map[string]int{}
+	for i, req := range reqs {
+		if req == nil {
+			groups = append(groups, []int{i})
+			continue
+		}
+		g, ok := byArtifact[req.Artifact.String()]
+		if !ok {
+			g = len(groups)
+			byArtifact[req.Artifact.String()] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], i)
+	}
+
+	queue := make(chan []int)
+	var wg sync.WaitGroup
+	for range min(batchWorkers, len(groups)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for group := range queue {
+				for _, i := range group {
+					if reqs[i] == nil {
+						errs[i] = &BatchItemError{Index: i, Err: errNilRequest}
+						continue
+					}
+					resp, err := store(ctx, reqs[i])
+					if err != nil {
+						errs[i] = &BatchItemError{Index: i, Artifact: reqs[i].Artifact.String(), Err: err}
+						continue
+					}
+					resps[i] = resp
+				}
+			}
+		}()
+	}
+	for _, group := range groups {
+		queue <- group
+	}
+	close(queue)
+	wg.Wait()
+	var failures []*BatchItemError
+	for _, err := range errs {
+		if err != nil {
+			failures = append(failures, err)
+		}
+	}
+	if len(failures) > 0 {
+		return resps, &BatchError{Failures: failures}
+	}
+	return resps, nil
+}
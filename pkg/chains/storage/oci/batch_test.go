@@ -0,0 +1,224 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestAttestationStorer_StoreBatch(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	registryName := newTestRegistry(t, nil)
+	const failed = 2
+	var refs []name.Digest
+	var reqs []*api.StoreRequest[name.Digest, *intoto.Statement]
+	for i := range 6 {
+		ref := pushRandomImage(t, registryName)
+		refs = append(refs, ref)
+		statement, payload := newTestStatement(t, ref, "https://slsa.dev/provenance/v1")
+		req := &api.StoreRequest[name.Digest, *intoto.Statement]{
+			Artifact: ref,
+			Payload:  statement,
+			Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+		}
+		if i == failed {
+			req.Bundle = nil
+		}
+		reqs = append(reqs, req)
+	}
+
+	storer, err := NewAttestationStorer()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	resps, err := storer.StoreBatch(ctx, reqs)
+	if !errors.Is(err, ErrMissingBundle) || !strings.Contains(err.Error(), "request 2 ") {
+		t.Errorf("StoreBatch() error = %v, want a missing bundle error for request %d", err, failed)
+	}
+	if len(resps) != len(reqs) {
+		t.Fatalf("StoreBatch() returned %d responses, want %d", len(resps), len(reqs))
+	}
+	for i, resp := range resps {
+		if i == failed {
+			if resp != nil {
+				t.Errorf("response %d = %+v, want nil", i, resp)
+			}
+			continue
+		}
+		tag, err := ociremote.AttestationTag(refs[i])
+		if err != nil {
+			t.Fatalf("failed to get attestation tag: %v", err)
+		}
+		if resp == nil || resp.Reference != tag.String() {
+			t.Errorf("response %d = %+v, want reference %s", i, resp, tag)
+		}
+	}
+}
+
+func TestStoreBatch_SameArtifact(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	registryName := newTestRegistry(t, nil)
+	refs := []name.Digest{pushRandomImage(t, registryName), pushRandomImage(t, registryName)}
+	var reqs []*api.StoreRequest[name.Digest, *intoto.Statement]
+	for i := range 6 {
+		ref := refs[i%len(refs)]
+		statement, payload := newTestStatement(t, ref, fmt.Sprintf("https://example.com/predicate/%d", i))
+		reqs = append(reqs, &api.StoreRequest[name.Digest, *intoto.Statement]{
+			Artifact: ref,
+			Payload:  statement,
+			Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+		})
+	}
+
+	storer, err := NewAttestationStorer()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	if _, err := storer.StoreBatch(ctx, reqs); err != nil {
+		t.Fatalf("error during StoreBatch(): %v", err)
+	}
+	// The requests for each artifact are stored in order and none is lost.
+	for i, ref := range refs {
+		var want []string
+		for j := i; j < len(reqs); j += len(refs) {
+			want = append(want, fmt.Sprintf("https://example.com/predicate/%d", j))
+		}
+		if diff := cmp.Diff(want, listedPredicateTypes(t, storer, ref)); diff != "" {
+			t.Errorf("unexpected attestations of %s (-want +got):\n%s", ref, diff)
+		}
+	}
+}
+
+func TestSimpleStorer_StoreBatch(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	registryName := newTestRegistry(t, nil)
+	var refs []name.Digest
+	var reqs []*api.StoreRequest[name.Digest, simple.SimpleContainerImage]
+	for range 3 {
+		ref := pushRandomImage(t, registryName)
+		refs = append(refs, ref)
+		reqs = append(reqs, &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+			Artifact: ref,
+			Payload:  simple.NewSimpleStruct(ref),
+			Bundle:   &signing.Bundle{Content: []byte("{}"), Signature: []byte("signature")},
+		})
+	}
+	reqs = append(reqs, nil)
+
+	storer, err := NewSimpleStorerFromConfig()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	resps, err := storer.StoreBatch(ctx, reqs)
+	if err == nil || !strings.Contains(err.Error(), "request 3 ") {
+		t.Errorf("StoreBatch() error = %v, want an error for request 3", err)
+	}
+	for i, ref := range refs {
+		tag, err := ociremote.SignatureTag(ref)
+		if err != nil {
+			t.Fatalf("failed to get signature tag: %v", err)
+		}
+		if resps[i] == nil || resps[i].Reference != tag.String() {
+			t.Errorf("response %d = %+v, want reference %s", i, resps[i], tag)
+		}
+	}
+	if resps[3] != nil {
+		t.Errorf("response 3 = %+v, want nil", resps[3])
+	}
+}
+
+func TestStoreBatch_BatchError(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	open := pushRandomImage(t, newTestRegistry(t, nil))
+	var authenticating atomic.Bool
+	protected := pushRandomImage(t, newTestRegistry(t, func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if authenticating.Load() && r.Method == http.MethodPut {
+				w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	}))
+	authenticating.Store(true)
+
+	newRequest := func(ref name.Digest) *api.StoreRequest[name.Digest, *intoto.Statement] {
+		statement, payload := newTestStatement(t, ref, "https://slsa.dev/provenance/v1")
+		return &api.StoreRequest[name.Digest, *intoto.Statement]{
+			Artifact: ref,
+			Payload:  statement,
+			Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+		}
+	}
+	reqs := []*api.StoreRequest[name.Digest, *intoto.Statement]{newRequest(open), newRequest(protected), nil, newRequest(open)}
+	storer, err := NewAttestationStorer()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	resps, err := storer.StoreBatch(ctx, reqs)
+
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("StoreBatch() error = %v, want a *BatchError", err)
+	}
+	if diff := cmp.Diff([]int{1, 2}, batchErr.FailedIndexes()); diff != "" {
+		t.Errorf("unexpected failed indexes (-want +got):\n%s", diff)
+	}
+	if !errors.Is(batchErr.Failures[0], ErrUnauthorized) {
+		t.Errorf("failure of request 1 = %v, want it to match ErrUnauthorized", batchErr.Failures[0])
+	}
+	if batchErr.Failures[0].Artifact != protected.String() {
+		t.Errorf("failure of request 1 is for %q, want %q", batchErr.Failures[0].Artifact, protected.String())
+	}
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Error("the batch error does not match the errors of its failures")
+	}
+	for _, i := range []int{0, 3} {
+		if resps[i] == nil {
+			t.Errorf("response %d is nil", i)
+		}
+	}
+
+	if _, err := storer.StoreBatch(ctx, reqs[:1]); err != nil {
+		t.Errorf("StoreBatch() error = %v, want nil", err)
+	}
+
+	// Requests without an artifact are not reported as nil.
+	_, err = storer.StoreBatch(ctx, []*api.StoreRequest[name.Digest, *intoto.Statement]{nil, {Bundle: &signing.Bundle{}}})
+	if !errors.As(err, &batchErr) || len(batchErr.Failures) != 2 {
+		t.Fatalf("StoreBatch() error = %v, want a *BatchError with 2 failures", err)
+	}
+	if got, want := batchErr.Failures[0].Error(), "request 0 is nil"; got != want {
+		t.Errorf("failure of request 0 = %q, want %q", got, want)
+	}
+	if got := batchErr.Failures[1]; !errors.Is(got, ErrInvalidArtifact) || strings.Contains(got.Error(), "is nil") {
+		t.Errorf("failure of request 1 = %q, want it to report ErrInvalidArtifact", got)
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+	stderrors "errors"
+	"sync"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/pkg/errors"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+)
+
+// StoreBoth stores the signature and the attestation of the same artifact
+// with a single lookup of its signed entity, writing them concurrently. The
+// lookup uses the options of the signature storer and is bounded by the longer
+// of the store timeouts, if both storers have one. If the storers target
+// different repositories, each looks the entity up on its own. Both requests
+// are validated before anything is fetched or written, and each store then
+// goes through the same span, dedup, timeout, mirrors and retry queue as
+// Store, the attestation being stored for the children of an index as
+// configured with WithFanoutToChildren.
+//
+// Both stores are always attempted. The response of each store that succeeded
+// is returned along with an error aggregating the failures, so that callers
+// learn the outcome of both.
+func StoreBoth(ctx context.Context, ss *SimpleStorer, as *AttestationStorer, sig *api.StoreRequest[name.Digest, simple.SimpleContainerImage], att *api.StoreRequest[name.Digest, *intoto.Statement]) (*api.StoreResponse, *api.StoreResponse, error) {
+	if sig.Artifact != att.Artifact {
+		return nil, nil, errors.Errorf("signature and attestation are for different artifacts: %s and %s", sig.Artifact.String(), att.Artifact.String())
+	}
+	if hs := ss.hostStorer(sig.Artifact); hs != nil {
+		ss = hs
+	}
+	if hs := as.hostStorer(att.Artifact); hs != nil {
+		as = hs
+	}
+	if err := ss.checkRequest(sig); err != nil {
+		return nil, nil, err
+	}
+	if err := as.checkRequest(att); err != nil {
+		return nil, nil, err
+	}
+
+	var sigEntity, attEntity oci.SignedEntity
+	if repo := ss.targetRepository(sig.Artifact); repo == as.targetRepository(att.Artifact) && ss.tagPrefix == as.tagPrefix {
+		// The shared entity fetches the existing signatures and attestations
+		// lazily with the context of the lookup, so the lookup is bounded by the
+		// longer of the store timeouts and its context outlives both stores.
+		lookupCtx := ctx
+		timeout := max(ss.storeTimeout, as.storeTimeout)
+		if ss.storeTimeout > 0 && as.storeTimeout > 0 {
+			var cancel context.CancelFunc
+			lookupCtx, cancel = context.WithTimeoutCause(ctx, timeout, errStoreTimeout)
+			defer cancel()
+		}
+		se, err := ss.signedEntity(lookupCtx, sig.Artifact, ss.tagOptions(repo)...)
+		if err != nil {
+			if errors.Is(context.Cause(lookupCtx), errStoreTimeout) {
+				err = &TimeoutError{Timeout: timeout, Err: err}
+			}
+			return nil, nil, err
+		}
+		// The entity is shared by both stores, so make sure the signatures and
+		// attestations already attached to it are fetched once, safely.
+		sigEntity = &memoizedEntity{SignedEntity: se}
+		attEntity = sigEntity
+	}
+
+	var (
+		wg               sync.WaitGroup
+		sigResp, attResp *api.StoreResponse
+		sigErr, attErr   error
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		sigResp, sigErr = ss.runStore(ctx, sig, func(ctx context.Context) (*api.StoreResponse, error) {
+			if sigEntity == nil {
+				return ss.store(ctx, sig)
+			}
+			unlock, err := ss.lockEntity(ctx, sig.Artifact, ss.targetRepository(sig.Artifact))
+			if err != nil {
+				return nil, err
+			}
+			defer unlock()
+			return ss.storeTo(ctx, sigEntity, sig)
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		attResp, attErr = as.runStore(ctx, att, func(ctx context.Context) (*api.StoreResponse, error) {
+			if attEntity == nil {
+				return as.store(ctx, att)
+			}
+			unlock, err := as.lockEntity(ctx, att.Artifact, as.targetRepository(att.Artifact))
+			if err != nil {
+				return nil, err
+			}
+			defer unlock()
+			return as.storeWithChildren(ctx, attEntity, att)
+		})
+	}()
+	wg.Wait()
+
+	var errs []error
+	if sigErr != nil {
+		errs = append(errs, errors.Wrap(sigErr, "storing signature"))
+	}
+	if attErr != nil {
+		errs = append(errs, errors.Wrap(attErr, "storing attestation"))
+	}
+	return sigResp, attResp, stderrors.Join(errs...)
+}
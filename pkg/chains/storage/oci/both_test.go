@@ -0,0 +1,275 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/pkg/errors"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestStoreBoth(t *testing.T) {
+	tests := []struct {
+		name string
+		// failTag is the suffix of the tag whose upload fails.
+		failTag     string
+		wantSig     bool
+		wantAtt     bool
+		wantErr     string
+		wantLookups int32
+	}{
+		{
+			name:        "both succeed",
+			wantSig:     true,
+			wantAtt:     true,
+			wantLookups: 1,
+		},
+		{
+			name:        "attestation fails",
+			failTag:     ".att",
+			wantSig:     true,
+			wantErr:     "storing attestation",
+			wantLookups: 1,
+		},
+		{
+			name:        "signature fails",
+			failTag:     ".sig",
+			wantAtt:     true,
+			wantErr:     "storing signature",
+			wantLookups: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lookups atomic.Int32
+			registryName := newTestRegistry(t, func(h http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.Method == http.MethodGet && strings.Contains(r.URL.Path, "/manifests/sha256:") {
+						lookups.Add(1)
+					}
+					if tt.failTag != "" && r.Method == http.MethodPut && strings.HasSuffix(r.URL.Path, tt.failTag) {
+						w.WriteHeader(http.StatusBadRequest)
+						return
+					}
+					h.ServeHTTP(w, r)
+				})
+			})
+			ref := pushRandomImage(t, registryName)
+			lookups.Store(0)
+
+			ss, err := NewSimpleStorerFromConfig(WithTargetRepository(ref.Repository))
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+			as, err := NewAttestationStorer(WithTargetRepository(ref.Repository))
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+			ss.remoteOpts = []remote.Option{remote.WithRetryBackoff(remote.Backoff{Steps: 1})}
+			as.remoteOpts = ss.remoteOpts
+
+			sigResp, attResp, err := StoreBoth(logtesting.TestContextWithLogger(t), ss, as,
+				&api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+					Artifact: ref,
+					Payload:  simple.NewSimpleStruct(ref),
+					Bundle:   &signing.Bundle{},
+				},
+				&api.StoreRequest[name.Digest, *intoto.Statement]{
+					Artifact: ref,
+					Payload:  &intoto.Statement{},
+					Bundle:   &signing.Bundle{},
+				})
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("StoreBoth() unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("StoreBoth() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+			if got := sigResp != nil; got != tt.wantSig {
+				t.Errorf("got signature response %v, want one: %t", sigResp, tt.wantSig)
+			}
+			if got := attResp != nil; got != tt.wantAtt {
+				t.Errorf("got attestation response %v, want one: %t", attResp, tt.wantAtt)
+			}
+			for _, resp := range []*api.StoreResponse{sigResp, attResp} {
+				if resp == nil {
+					continue
+				}
+				tag, err := name.NewTag(resp.Reference)
+				if err != nil {
+					t.Fatalf("failed to parse %s: %v", resp.Reference, err)
+				}
+				if _, err := remote.Head(tag); err != nil {
+					t.Errorf("failed to find %s: %v", resp.Reference, err)
+				}
+			}
+			if got := lookups.Load(); got != tt.wantLookups {
+				t.Errorf("got %d artifact lookups, want %d", got, tt.wantLookups)
+			}
+		})
+	}
+}
+
+func TestStoreBoth_DifferentArtifacts(t *testing.T) {
+	registryName := newTestRegistry(t, nil)
+	a, b := pushRandomImage(t, registryName), pushRandomImage(t, registryName)
+	ss, err := NewSimpleStorerFromConfig()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	as, err := NewAttestationStorer()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	_, _, err = StoreBoth(logtesting.TestContextWithLogger(t), ss, as,
+		&api.StoreRequest[name.Digest, simple.SimpleContainerImage]{Artifact: a, Bundle: &signing.Bundle{}},
+		&api.StoreRequest[name.Digest, *intoto.Statement]{Artifact: b, Bundle: &signing.Bundle{}})
+	if err == nil {
+		t.Error("expected an error storing for different artifacts")
+	}
+}
+
+func TestStoreBoth_MaxPayloadBytes(t *testing.T) {
+	ref := pushRandomImage(t, newTestRegistry(t, nil))
+	statement, payload := newTestStatement(t, ref, "https://example.com/predicate")
+	envelope := newTestEnvelope(t, payload)
+	content := []byte(strings.Repeat("c", 64))
+
+	for _, tt := range []struct {
+		name               string
+		sigLimit, attLimit int64
+	}{
+		{name: "signature too large", sigLimit: int64(len(content) - 1)},
+		{name: "attestation too large", attLimit: int64(len(envelope) - 1)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &countingTransport{inner: http.DefaultTransport}
+			ss, err := NewSimpleStorerFromConfig(WithTransport(transport), WithMaxPayloadBytes(tt.sigLimit))
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+			as, err := NewAttestationStorer(WithTransport(transport), WithMaxPayloadBytes(tt.attLimit))
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+			_, _, err = StoreBoth(logtesting.TestContextWithLogger(t), ss, as,
+				&api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+					Artifact: ref,
+					Payload:  simple.NewSimpleStruct(ref),
+					Bundle:   &signing.Bundle{Content: content, Signature: []byte("signature")},
+				},
+				&api.StoreRequest[name.Digest, *intoto.Statement]{
+					Artifact: ref,
+					Payload:  statement,
+					Bundle:   &signing.Bundle{Signature: envelope},
+				})
+			if !errors.Is(err, ErrPayloadTooLarge) {
+				t.Errorf("StoreBoth() = %v, want ErrPayloadTooLarge", err)
+			}
+			if n := transport.requests.Load(); n != 0 {
+				t.Errorf("%d requests sent for an oversized payload, want none", n)
+			}
+		})
+	}
+}
+
+func TestStoreBoth_Dedup(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	var puts atomic.Int32
+	ref := pushRandomImage(t, newTestRegistry(t, func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPut && strings.Contains(r.URL.Path, "/manifests/") {
+				puts.Add(1)
+			}
+			h.ServeHTTP(w, r)
+		})
+	}))
+	ss, err := NewSimpleStorerFromConfig(WithInProcessDedup(time.Minute))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	as, err := NewAttestationStorer(WithInProcessDedup(time.Minute))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	statement, payload := newTestStatement(t, ref, "https://example.com/predicate")
+	sig := &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+		Artifact: ref,
+		Payload:  simple.NewSimpleStruct(ref),
+		Bundle:   &signing.Bundle{Content: []byte("content"), Signature: []byte("signature")},
+	}
+	att := &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  statement,
+		Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+	}
+
+	if _, _, err := StoreBoth(ctx, ss, as, sig, att); err != nil {
+		t.Fatalf("StoreBoth() unexpected error: %v", err)
+	}
+	stored := puts.Load()
+	if _, _, err := StoreBoth(ctx, ss, as, sig, att); err != nil {
+		t.Fatalf("StoreBoth() unexpected error: %v", err)
+	}
+	if n := puts.Load(); n != stored {
+		t.Errorf("got %d manifest writes storing the same payloads again, want none", n-stored)
+	}
+	if got := countAttestationLayers(t, ref.Repository, ref); got != 1 {
+		t.Errorf("got %d attestation layers, want 1", got)
+	}
+}
+
+func TestStoreBoth_FanoutToChildren(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	ref, children := pushRandomIndex(t, newTestRegistry(t, nil), 2)
+	ss, err := NewSimpleStorerFromConfig()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	as, err := NewAttestationStorer(WithFanoutToChildren())
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	statement, payload := newTestStatement(t, ref, "https://slsa.dev/provenance/v1")
+	if _, _, err := StoreBoth(ctx, ss, as,
+		&api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+			Artifact: ref,
+			Payload:  simple.NewSimpleStruct(ref),
+			Bundle:   &signing.Bundle{Content: []byte("content"), Signature: []byte("signature")},
+		},
+		&api.StoreRequest[name.Digest, *intoto.Statement]{
+			Artifact: ref,
+			Payload:  statement,
+			Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+		}); err != nil {
+		t.Fatalf("StoreBoth() unexpected error: %v", err)
+	}
+	for _, d := range append([]name.Digest{ref}, children...) {
+		if got := countAttestationLayers(t, ref.Context(), d); got != 1 {
+			t.Errorf("attestation layers of %s = %d, want 1", d, got)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	slsav02 "github.com/in-toto/in-toto-golang/in_toto/slsa_provenance/v0.2"
+	slsav1 "github.com/in-toto/in-toto-golang/in_toto/slsa_provenance/v1"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// builderIDAnnotation holds the ID of the builder recorded in SLSA provenance
+// attestations, so that attestations can be filtered by builder without
+// downloading them.
+const builderIDAnnotation = "chains.tekton.dev/builder-id"
+
+// builderIDOf returns the builder ID recorded in the SLSA provenance of the
+// attestation to store. False is returned for other predicates and for
+// provenance without a builder ID.
+func builderIDOf(req *api.StoreRequest[name.Digest, *intoto.Statement]) (string, bool) {
+	statement := requestStatement(req)
+	var path []string
+	switch statement.GetPredicateType() {
+	case slsav02.PredicateSLSAProvenance:
+		path = []string{"builder", "id"}
+	case slsav1.PredicateSLSAProvenance:
+		path = []string{"runDetails", "builder", "id"}
+	default:
+		return "", false
+	}
+	v := structpb.NewStructValue(statement.GetPredicate())
+	for _, field := range path {
+		v = v.GetStructValue().GetFields()[field]
+	}
+	id := v.GetStringValue()
+	return id, id != ""
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestAttestationStorer_BuilderIDAnnotation(t *testing.T) {
+	const builderID = "https://tekton.dev/chains/v2"
+	tests := []struct {
+		name          string
+		predicateType string
+		predicate     map[string]any
+		want          string
+	}{
+		{
+			name:          "slsa v1",
+			predicateType: "https://slsa.dev/provenance/v1",
+			predicate: map[string]any{
+				"buildDefinition": map[string]any{"buildType": "https://tekton.dev/chains/v2/slsa"},
+				"runDetails":      map[string]any{"builder": map[string]any{"id": builderID}},
+			},
+			want: builderID,
+		},
+		{
+			name:          "slsa v0.2",
+			predicateType: "https://slsa.dev/provenance/v0.2",
+			predicate:     map[string]any{"builder": map[string]any{"id": builderID}},
+			want:          builderID,
+		},
+		{
+			name:          "slsa without builder",
+			predicateType: "https://slsa.dev/provenance/v1",
+			predicate:     map[string]any{"runDetails": map[string]any{}},
+		},
+		{
+			name:          "not slsa",
+			predicateType: "https://example.com/predicate",
+			predicate:     map[string]any{"builder": map[string]any{"id": builderID}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := logtesting.TestContextWithLogger(t)
+			ref := pushRandomImage(t, newTestRegistry(t, nil))
+			predicate, err := structpb.NewStruct(tc.predicate)
+			if err != nil {
+				t.Fatalf("failed to build predicate: %v", err)
+			}
+			statement := &intoto.Statement{
+				Type:          intoto.StatementTypeUri,
+				Subject:       []*intoto.ResourceDescriptor{subjectFromDigest(ref)},
+				PredicateType: tc.predicateType,
+				Predicate:     predicate,
+			}
+			payload, err := protojson.Marshal(statement)
+			if err != nil {
+				t.Fatalf("failed to marshal statement: %v", err)
+			}
+			storer, err := NewAttestationStorer()
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+			if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+				Artifact: ref,
+				Payload:  statement,
+				Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+			}); err != nil {
+				t.Fatalf("error during Store(): %v", err)
+			}
+
+			se, err := ociremote.SignedEntity(ref)
+			if err != nil {
+				t.Fatalf("failed to get signed entity: %v", err)
+			}
+			atts, err := se.Attestations()
+			if err != nil {
+				t.Fatalf("failed to get attestations: %v", err)
+			}
+			layers, err := atts.Get()
+			if err != nil || len(layers) != 1 {
+				t.Fatalf("failed to get the attestation: %d layers, %v", len(layers), err)
+			}
+			ann, err := layers[0].Annotations()
+			if err != nil {
+				t.Fatalf("failed to read annotations: %v", err)
+			}
+			got, ok := ann[builderIDAnnotation]
+			if got != tc.want || ok != (tc.want != "") {
+				t.Errorf("annotation %s = %q (set: %v), want %q", builderIDAnnotation, got, ok, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"encoding/pem"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// newTLSTestRegistry starts an in-memory registry served with a self-signed
+// certificate and returns an image pushed to it along with the PEM encoded
+// certificate.
+func newTLSTestRegistry(t *testing.T) (name.Digest, []byte) {
+	t.Helper()
+	s := httptest.NewTLSServer(registry.New())
+	t.Cleanup(s.Close)
+
+	img, err := random.Image(1024, 2)
+	if err != nil {
+		t.Fatalf("failed to create random image: %v", err)
+	}
+	imgDigest, err := img.Digest()
+	if err != nil {
+		t.Fatalf("failed to get image digest: %v", err)
+	}
+	ref, err := name.NewDigest(fmt.Sprintf("%s/test/img@%s", strings.TrimPrefix(s.URL, "https://"), imgDigest))
+	if err != nil {
+		t.Fatalf("failed to parse digest: %v", err)
+	}
+	if err := remote.Write(ref, img, remote.WithTransport(s.Client().Transport)); err != nil {
+		t.Fatalf("failed to write image to mock registry: %v", err)
+	}
+	return ref, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw})
+}
+
+func TestWithCABundle(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	ref, caPEM := newTLSTestRegistry(t)
+	req := &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  &intoto.Statement{},
+		Bundle:   &signing.Bundle{},
+	}
+
+	t.Run("untrusted", func(t *testing.T) {
+		storer, err := NewAttestationStorer()
+		if err != nil {
+			t.Fatalf("failed to create storer: %v", err)
+		}
+		if _, err := storer.Store(ctx, req); err == nil {
+			t.Error("expected the store to fail without the CA bundle")
+		}
+	})
+
+	t.Run("trusted", func(t *testing.T) {
+		storer, err := NewAttestationStorer(WithCABundle(caPEM))
+		if err != nil {
+			t.Fatalf("failed to create storer: %v", err)
+		}
+		if _, err := storer.Store(ctx, req); err != nil {
+			t.Errorf("error during Store(): %v", err)
+		}
+	})
+
+	for _, tc := range []struct {
+		name string
+		pem  []byte
+	}{{
+		name: "empty",
+	}, {
+		name: "garbage",
+		pem:  []byte("not a certificate"),
+	}, {
+		name: "trailing garbage",
+		pem:  append(append([]byte{}, caPEM...), "garbage"...),
+	}, {
+		name: "invalid certificate",
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("garbage")}),
+	}, {
+		name: "private key",
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("garbage")}),
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewSimpleStorerFromConfig(WithCABundle(tc.pem)); err == nil {
+				t.Error("expected an error for a malformed CA bundle")
+			}
+		})
+	}
+
+	t.Run("custom transport", func(t *testing.T) {
+		if _, err := NewAttestationStorer(WithTransport(&countingTransport{}), WithCABundle(caPEM)); err == nil {
+			t.Error("expected an error for a transport that is not an *http.Transport")
+		}
+	})
+}
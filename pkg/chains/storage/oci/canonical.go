@@ -0,0 +1,130 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/cyberphone/json-canonicalization/go/src/webpki.org/jsoncanonicalizer"
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/pkg/errors"
+	"github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	"google.golang.org/protobuf/encoding/protojson"
+	"knative.dev/pkg/logging"
+)
+
+// Canonicalizer serializes in-toto statements to the exact bytes a verifier
+// expects to have been signed.
+type Canonicalizer interface {
+	Canonicalize(statement *intoto.Statement) ([]byte, error)
+}
+
+// JCSCanonicalizer serializes statements in the RFC 8785 JSON Canonicalization
+// Scheme: object keys sorted, no insignificant whitespace and numbers in their
+// shortest ECMAScript form.
+type JCSCanonicalizer struct{}
+
+var _ Canonicalizer = JCSCanonicalizer{}
+
+// Canonicalize implements Canonicalizer.
+func (JCSCanonicalizer) Canonicalize(statement *intoto.Statement) ([]byte, error) {
+	b, err := protojson.Marshal(statement)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshaling the statement")
+	}
+	return jsoncanonicalizer.Transform(b)
+}
+
+// CosignCanonicalizer serializes statements like cosign attest does: the
+// _type, predicateType, subject and predicate fields in that order, subjects
+// as their name and digest, predicate keys sorted, HTML characters escaped
+// and no insignificant whitespace. Subjects with other fields cannot be
+// serialized.
+type CosignCanonicalizer struct{}
+
+var _ Canonicalizer = CosignCanonicalizer{}
+
+// cosignStatement mirrors the field order of the statements signed by cosign.
+type cosignStatement struct {
+	Type          string          `json:"_type"`
+	PredicateType string          `json:"predicateType"`
+	Subject       []cosignSubject `json:"subject"`
+	Predicate     any             `json:"predicate"`
+}
+
+type cosignSubject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Canonicalize implements Canonicalizer.
+func (CosignCanonicalizer) Canonicalize(statement *intoto.Statement) ([]byte, error) {
+	cs := cosignStatement{
+		Type:          statement.GetType(),
+		PredicateType: statement.GetPredicateType(),
+		Subject:       make([]cosignSubject, 0, len(statement.GetSubject())),
+	}
+	for _, s := range statement.GetSubject() {
+		if s.GetUri() != "" || len(s.GetContent()) > 0 || s.GetDownloadLocation() != "" || s.GetMediaType() != "" || s.GetAnnotations() != nil {
+			return nil, errors.Errorf("subject %q has fields other than its name and digest", s.GetName())
+		}
+		cs.Subject = append(cs.Subject, cosignSubject{Name: s.GetName(), Digest: s.GetDigest()})
+	}
+	if p := statement.GetPredicate(); p != nil {
+		cs.Predicate = p.AsMap()
+	}
+	return json.Marshal(cs)
+}
+
+// marshalStatement returns the bytes of the statement as serialized by the
+// canonicalizer configured with WithCanonicalizer, or by protojson.
+func (s *AttestationStorer) marshalStatement(statement *intoto.Statement) ([]byte, error) {
+	if s.canonicalizer == nil {
+		return protojson.Marshal(statement)
+	}
+	return s.canonicalizer.Canonicalize(statement)
+}
+
+// checkCanonicalPayload warns when the payload signed in the envelope of req
+// is not the statement serialized by the configured canonicalizer, as the
+// verifiers requiring that serialization would then reject the signature.
+// The envelope is stored as is either way.
+func (s *AttestationStorer) checkCanonicalPayload(ctx context.Context, req *api.StoreRequest[name.Digest, *intoto.Statement]) {
+	if s.canonicalizer == nil || req.Payload == nil {
+		return
+	}
+	logger := logging.FromContext(ctx)
+	envelope := dsse.Envelope{}
+	if err := json.Unmarshal(req.Bundle.Signature, &envelope); err != nil {
+		return
+	}
+	signed, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		return
+	}
+	want, err := s.canonicalizer.Canonicalize(req.Payload)
+	if err != nil {
+		logger.Warnf("Failed to canonicalize the attestation for %s with %T: %v", req.Artifact.String(), s.canonicalizer, err)
+		return
+	}
+	if !bytes.Equal(signed, want) {
+		logger.Warnf("The signed payload of the attestation for %s is not serialized as required by %T, verifiers expecting it will reject the signature", req.Artifact.String(), s.canonicalizer)
+	}
+}
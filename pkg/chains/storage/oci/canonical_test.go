@@ -0,0 +1,134 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	"google.golang.org/protobuf/types/known/structpb"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func newCanonicalTestStatement(t *testing.T) *intoto.Statement {
+	t.Helper()
+	predicate, err := structpb.NewStruct(map[string]any{
+		"b": 1,
+		"a": "<&>",
+		"c": 1e21,
+	})
+	if err != nil {
+		t.Fatalf("failed to build predicate: %v", err)
+	}
+	return &intoto.Statement{
+		Type:          intoto.StatementTypeUri,
+		Subject:       []*intoto.ResourceDescriptor{{Name: "img", Digest: map[string]string{"sha256": "abc"}}},
+		PredicateType: "https://example.com/predicate",
+		Predicate:     predicate,
+	}
+}
+
+func TestCanonicalizers(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		canonicalizer Canonicalizer
+		want          string
+	}{{
+		name:          "jcs",
+		canonicalizer: JCSCanonicalizer{},
+		want:          `{"_type":"https://in-toto.io/Statement/v1","predicate":{"a":"<&>","b":1,"c":1e+21},"predicateType":"https://example.com/predicate","subject":[{"digest":{"sha256":"abc"},"name":"img"}]}`,
+	}, {
+		name:          "cosign",
+		canonicalizer: CosignCanonicalizer{},
+		want:          `{"_type":"https://in-toto.io/Statement/v1","predicateType":"https://example.com/predicate","subject":[{"name":"img","digest":{"sha256":"abc"}}],"predicate":{"a":"\u003c\u0026\u003e","b":1,"c":1e+21}}`,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.canonicalizer.Canonicalize(newCanonicalTestStatement(t))
+			if err != nil {
+				t.Fatalf("Canonicalize() = %v", err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("Canonicalize() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCosignCanonicalizer_UnsupportedSubject(t *testing.T) {
+	statement := newCanonicalTestStatement(t)
+	statement.Subject[0].Uri = "https://example.com/img"
+	if _, err := (CosignCanonicalizer{}).Canonicalize(statement); err == nil {
+		t.Error("expected an error for a subject with a URI")
+	}
+}
+
+func TestWithCanonicalizer(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		canonical bool
+	}{{
+		name:      "signed canonical",
+		canonical: true,
+	}, {
+		name: "signed other serialization",
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := logtesting.TestContextWithLogger(t)
+			ref := pushRandomImage(t, newTestRegistry(t, nil))
+			storer, err := NewAttestationStorer(WithCanonicalizer(JCSCanonicalizer{}), WithRecordPayloadDigests(true))
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+			statement, payload := newTestStatement(t, ref, "https://example.com/predicate")
+			canonical, err := (JCSCanonicalizer{}).Canonicalize(statement)
+			if err != nil {
+				t.Fatalf("Canonicalize() = %v", err)
+			}
+			signed := indentJSON(t, payload)
+			if tc.canonical {
+				signed = canonical
+			}
+			if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+				Artifact: ref,
+				Payload:  statement,
+				Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, signed)},
+			}); err != nil {
+				t.Fatalf("error during Store(): %v", err)
+			}
+
+			se, err := ociremote.SignedEntity(ref)
+			if err != nil {
+				t.Fatalf("failed to get signed entity: %v", err)
+			}
+			atts, err := se.Attestations()
+			if err != nil {
+				t.Fatalf("failed to get attestations: %v", err)
+			}
+			if tc.canonical {
+				signed = nil
+			}
+			// The unsigned payload is recorded in the canonical serialization.
+			checkPayloadDigests(t, atts, canonical, signed)
+		})
+	}
+
+	if _, err := NewAttestationStorer(WithCanonicalizer(nil)); err == nil {
+		t.Error("expected an error for a nil canonicalizer")
+	}
+}
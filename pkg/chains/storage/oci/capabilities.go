@@ -0,0 +1,164 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/pkg/errors"
+)
+
+// probeDigest is the digest of the artifact the capability probes refer to.
+// No content hashes to it, so the probes never read or delete anything.
+const probeDigest = "sha256:0000000000000000000000000000000000000000000000000000000000000000"
+
+// Capabilities describes the features of a registry that the storage formats
+// depend on.
+type Capabilities struct {
+	// Referrers is whether the registry serves the OCI referrers API, rather
+	// than relying on the referrers tag schema.
+	Referrers bool
+	// ManifestDeletion is whether the registry allows manifests to be deleted.
+	ManifestDeletion bool
+	// BundleMediaType is whether the registry can hold sigstore protobuf
+	// bundles. Bundles are stored as referrers, so it reports the same as
+	// Referrers; registries are not probed with a bundle write.
+	BundleMediaType bool
+}
+
+// capabilityCache caches the capabilities of each registry host.
+type capabilityCache struct {
+	mu     sync.Mutex
+	byHost map[string]Capabilities
+}
+
+// Capabilities probes the registry of repo for the features the storage
+// formats depend on. The probes are lightweight reads and a deletion of a
+// manifest that does not exist, and their results are cached per registry
+// host for the lifetime of the storer. Registries may not serve the referrers
+// API for repositories that do not exist, so repo should exist. It errors for a
+// local OCI layout.
+func (s *AttestationStorer) Capabilities(ctx context.Context, repo name.Repository) (Capabilities, error) {
+	if hs := s.hostStorers[repo.RegistryStr()]; hs != nil {
+		return hs.Capabilities(ctx, repo)
+	}
+	return s.capabilities(ctx, repo)
+}
+
+// Capabilities probes the registry of repo for the features the storage
+// formats depend on. The probes are lightweight reads and a deletion of a
+// manifest that does not exist, and their results are cached per registry
+// host for the lifetime of the storer. Registries may not serve the referrers
+// API for repositories that do not exist, so repo should exist. It errors for a
+// local OCI layout.
+func (s *SimpleStorer) Capabilities(ctx context.Context, repo name.Repository) (Capabilities, error) {
+	if hs := s.hostStorers[repo.RegistryStr()]; hs != nil {
+		return hs.Capabilities(ctx, repo)
+	}
+	return s.capabilities(ctx, repo)
+}
+
+func (b *baseStorer) capabilities(ctx context.Context, repo name.Repository) (Capabilities, error) {
+	if b.localLayout != "" {
+		return Capabilities{}, errors.New("registry capabilities are not available with a local OCI layout")
+	}
+	host := repo.RegistryStr()
+	b.capabilityCache.mu.Lock()
+	caps, ok := b.capabilityCache.byHost[host]
+	b.capabilityCache.mu.Unlock()
+	if ok {
+		return caps, nil
+	}
+
+	referrers, err := b.probeReferrers(ctx, repo)
+	if err != nil {
+		return Capabilities{}, errors.Wrapf(err, "probing the referrers API of %s", host)
+	}
+	deletion, err := b.probeManifestDeletion(ctx, repo)
+	if err != nil {
+		return Capabilities{}, errors.Wrapf(err, "probing manifest deletion on %s", host)
+	}
+	caps = Capabilities{
+		Referrers:        referrers,
+		ManifestDeletion: deletion,
+		BundleMediaType:  referrers,
+	}
+
+	b.capabilityCache.mu.Lock()
+	if b.capabilityCache.byHost == nil {
+		b.capabilityCache.byHost = map[string]Capabilities{}
+	}
+	b.capabilityCache.byHost[host] = caps
+	b.capabilityCache.mu.Unlock()
+	return caps, nil
+}
+
+// probeReferrers reports whether the registry of repo serves the referrers
+// API. go-containerregistry falls back to the referrers tag schema when it
+// does not, so the answer is read from the response to the API request.
+func (b *baseStorer) probeReferrers(ctx context.Context, repo name.Repository) (bool, error) {
+	rt := &referrersProbe{inner: b.roundTripper()}
+	opts := append(b.remoteOptions(ctx), remote.WithTransport(rt))
+	if _, err := remote.Referrers(repo.Digest(probeDigest), opts...); err != nil {
+		var terr *transport.Error
+		if !errors.As(err, &terr) || terr.StatusCode != http.StatusNotFound {
+			return false, err
+		}
+	}
+	return rt.supported, nil
+}
+
+// referrersProbe records whether the registry answered a request to the
+// referrers API with an image index.
+type referrersProbe struct {
+	inner     http.RoundTripper
+	supported bool
+}
+
+// RoundTrip implements http.RoundTripper.
+func (p *referrersProbe) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := p.inner.RoundTrip(req)
+	if err == nil && strings.Contains(req.URL.Path, "/referrers/") && resp.StatusCode == http.StatusOK &&
+		strings.HasPrefix(resp.Header.Get("Content-Type"), string(types.OCIImageIndex)) {
+		p.supported = true
+	}
+	return resp, err
+}
+
+// probeManifestDeletion reports whether the registry of repo allows manifests
+// to be deleted, by deleting a manifest that does not exist. Registries that
+// allow deletions answer that it is unknown.
+func (b *baseStorer) probeManifestDeletion(ctx context.Context, repo name.Repository) (bool, error) {
+	err := remote.Delete(repo.Digest(probeDigest), b.remoteOptions(ctx)...)
+	var terr *transport.Error
+	switch {
+	case err == nil:
+		return true, nil
+	case !errors.As(err, &terr):
+		return false, err
+	case terr.StatusCode == http.StatusNotFound:
+		return true, nil
+	case terr.StatusCode == http.StatusMethodNotAllowed || hasErrorCode(terr, transport.UnsupportedErrorCode):
+		return false, nil
+	}
+	return false, err
+}
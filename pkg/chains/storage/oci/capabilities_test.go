@@ -0,0 +1,114 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestCapabilities(t *testing.T) {
+	rejectDeletes := func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodDelete {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	}
+
+	for name, tc := range map[string]struct {
+		artifact func(t *testing.T) name.Digest
+		want     Capabilities
+	}{
+		"referrers": {
+			artifact: newReferrersTestRegistry,
+			want:     Capabilities{Referrers: true, ManifestDeletion: true, BundleMediaType: true},
+		},
+		"tag schema": {
+			artifact: func(t *testing.T) name.Digest { return pushRandomImage(t, newTestRegistry(t, nil)) },
+			want:     Capabilities{ManifestDeletion: true},
+		},
+		"no deletion": {
+			artifact: func(t *testing.T) name.Digest { return pushRandomImage(t, newTestRegistry(t, rejectDeletes)) },
+			want:     Capabilities{},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := logtesting.TestContextWithLogger(t)
+			storer, err := NewAttestationStorer()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := storer.Capabilities(ctx, tc.artifact(t).Repository)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("Capabilities() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCapabilities_Cached(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	repo := pushRandomImage(t, newTestRegistry(t, nil)).Repository
+	transport := &countingTransport{inner: http.DefaultTransport}
+	storer, err := NewSimpleStorerFromConfig(WithTransport(transport))
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := storer.Capabilities(ctx, repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	probes := transport.requests.Load()
+	if probes == 0 {
+		t.Fatal("expected the registry to be probed")
+	}
+	other, err := name.NewRepository(repo.RegistryStr() + "/other/img")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := storer.Capabilities(ctx, other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second != first {
+		t.Errorf("Capabilities() = %+v, want %+v", second, first)
+	}
+	if n := transport.requests.Load(); n != probes {
+		t.Errorf("got %d requests after probing the same host again, want %d", n, probes)
+	}
+}
+
+func TestCapabilities_LocalLayout(t *testing.T) {
+	repo, err := name.NewRepository("registry.example.com/test/img")
+	if err != nil {
+		t.Fatal(err)
+	}
+	storer, err := NewAttestationStorer(WithLocalLayout(t.TempDir()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := storer.Capabilities(logtesting.TestContextWithLogger(t), repo); err == nil {
+		t.Error("expected an error for a local OCI layout")
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+
+	"github.com/pkg/errors"
+)
+
+const pemCertificateType = "CERTIFICATE"
+
+// normalizeCertChain converts the certificate and chain into the PEM encoding
+// cosign expects, accepting either PEM or DER encoded input.
+func normalizeCertChain(cert, chain []byte) ([]byte, []byte, error) {
+	cert, err := certificatesToPEM(cert)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "normalizing certificate")
+	}
+	chain, err = certificatesToPEM(chain)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "normalizing certificate chain")
+	}
+	return cert, chain, nil
+}
+
+// certificatesToPEM returns the given PEM or DER encoded certificates as PEM.
+// Empty input is returned unchanged.
+func certificatesToPEM(b []byte) ([]byte, error) {
+	if len(bytes.TrimSpace(b)) == 0 {
+		return b, nil
+	}
+	if block, _ := pem.Decode(b); block != nil {
+		if err := validatePEMCertificates(b); err != nil {
+			return nil, err
+		}
+		return b, nil
+	}
+
+	certs, err := x509.ParseCertificates(b)
+	if err != nil {
+		return nil, errors.Wrap(err, "input is neither PEM nor DER encoded certificates")
+	}
+	var out bytes.Buffer
+	for _, c := range certs {
+		if err := pem.Encode(&out, &pem.Block{Type: pemCertificateType, Bytes: c.Raw}); err != nil {
+			return nil, err
+		}
+	}
+	return out.Bytes(), nil
+}
+
+func validatePEMCertificates(b []byte) error {
+	for rest := b; len(bytes.TrimSpace(rest)) > 0; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return errors.New("trailing data after PEM certificates")
+		}
+		if block.Type != pemCertificateType {
+			return errors.Errorf("unexpected PEM block type %q", block.Type)
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return errors.Wrap(err, "parsing PEM certificate")
+		}
+	}
+	return nil
+}
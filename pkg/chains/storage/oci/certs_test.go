@@ -0,0 +1,211 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// newTestCertDER returns a DER encoded self-signed certificate.
+func newTestCertDER(t *testing.T, commonName string) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	return der
+}
+
+func toPEM(der ...[]byte) []byte {
+	var out bytes.Buffer
+	for _, d := range der {
+		_ = pem.Encode(&out, &pem.Block{Type: "CERTIFICATE", Bytes: d})
+	}
+	return out.Bytes()
+}
+
+func TestCertificatesToPEM(t *testing.T) {
+	leaf := newTestCertDER(t, "leaf")
+	root := newTestCertDER(t, "root")
+
+	tests := []struct {
+		name    string
+		in      []byte
+		want    []byte
+		wantErr bool
+	}{
+		{
+			name: "empty",
+			in:   []byte{},
+			want: []byte{},
+		},
+		{
+			name: "pem",
+			in:   toPEM(leaf),
+			want: toPEM(leaf),
+		},
+		{
+			name: "pem chain",
+			in:   toPEM(leaf, root),
+			want: toPEM(leaf, root),
+		},
+		{
+			name: "der",
+			in:   leaf,
+			want: toPEM(leaf),
+		},
+		{
+			name: "der chain",
+			in:   append(append([]byte{}, leaf...), root...),
+			want: toPEM(leaf, root),
+		},
+		{
+			name:    "garbage",
+			in:      []byte("not a certificate"),
+			wantErr: true,
+		},
+		{
+			name:    "pem with non-certificate block",
+			in:      pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("key")}),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := certificatesToPEM(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("certificatesToPEM() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("certificatesToPEM() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStore_CertEncodings(t *testing.T) {
+	leaf := newTestCertDER(t, "leaf")
+	root := newTestCertDER(t, "root")
+
+	tests := []struct {
+		name  string
+		cert  []byte
+		chain []byte
+	}{
+		{
+			name:  "pem",
+			cert:  toPEM(leaf),
+			chain: toPEM(root),
+		},
+		{
+			name:  "der",
+			cert:  leaf,
+			chain: root,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := logtesting.TestContextWithLogger(t)
+			registryName := newTestRegistry(t, nil)
+			ref := pushRandomImage(t, registryName)
+			bundle := &signing.Bundle{Cert: tt.cert, Chain: tt.chain}
+
+			attStorer, err := NewAttestationStorer(WithTargetRepository(ref.Repository))
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+			if _, err := attStorer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+				Artifact: ref,
+				Payload:  &intoto.Statement{},
+				Bundle:   bundle,
+			}); err != nil {
+				t.Fatalf("error during attestation Store(): %v", err)
+			}
+
+			sigStorer, err := NewSimpleStorerFromConfig(WithTargetRepository(ref.Repository))
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+			if _, err := sigStorer.Store(ctx, &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+				Artifact: ref,
+				Payload:  simple.NewSimpleStruct(ref),
+				Bundle:   bundle,
+			}); err != nil {
+				t.Fatalf("error during signature Store(): %v", err)
+			}
+
+			se, err := ociremote.SignedEntity(ref)
+			if err != nil {
+				t.Fatalf("failed to get signed entity: %v", err)
+			}
+			atts, err := se.Attestations()
+			if err != nil {
+				t.Fatalf("failed to get attestations: %v", err)
+			}
+			sigs, err := se.Signatures()
+			if err != nil {
+				t.Fatalf("failed to get signatures: %v", err)
+			}
+			for _, l := range []oci.Signatures{atts, sigs} {
+				got, err := l.Get()
+				if err != nil || len(got) != 1 {
+					t.Fatalf("expected one stored object, got %d (err: %v)", len(got), err)
+				}
+				c, err := got[0].Cert()
+				if err != nil {
+					t.Fatalf("failed to read stored certificate: %v", err)
+				}
+				if !bytes.Equal(c.Raw, leaf) {
+					t.Error("stored certificate does not match the input certificate")
+				}
+				chain, err := got[0].Chain()
+				if err != nil {
+					t.Fatalf("failed to read stored chain: %v", err)
+				}
+				if len(chain) != 1 || !bytes.Equal(chain[0].Raw, root) {
+					t.Error("stored chain does not match the input chain")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"net/http"
+
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrUnauthorized is matched by errors of registry requests rejected for
+	// missing or insufficient credentials.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrRepositoryNotFound is matched by errors of registry requests for a
+	// repository that does not exist.
+	ErrRepositoryNotFound = errors.New("repository not found")
+)
+
+// TransientError wraps the error of a registry request that may succeed if
+// retried, e.g. a 5xx or 429 response or a connection reset.
+type TransientError struct {
+	Err error
+}
+
+// Error implements error.
+func (e *TransientError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the error of the failed request.
+func (e *TransientError) Unwrap() error {
+	return e.Err
+}
+
+// classifiedError matches a sentinel error while keeping the error it
+// classifies in its chain.
+type classifiedError struct {
+	sentinel error
+	err      error
+}
+
+// Error implements error.
+func (e *classifiedError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the sentinel and the classified error.
+func (e *classifiedError) Unwrap() []error {
+	return []error{e.sentinel, e.err}
+}
+
+// classifyError makes the error of a registry request match ErrUnauthorized,
+// ErrRepositoryNotFound or *TransientError, as applicable.
+func classifyError(err error) error {
+	var transient *TransientError
+	if err == nil || errors.Is(err, ErrUnauthorized) || errors.Is(err, ErrRepositoryNotFound) || errors.As(err, &transient) {
+		return err
+	}
+	var terr *transport.Error
+	if errors.As(err, &terr) {
+		switch {
+		case terr.StatusCode == http.StatusUnauthorized || terr.StatusCode == http.StatusForbidden:
+			return &classifiedError{sentinel: ErrUnauthorized, err: err}
+		case hasErrorCode(terr, transport.NameUnknownErrorCode):
+			return &classifiedError{sentinel: ErrRepositoryNotFound, err: err}
+		}
+	}
+	if isTransientError(err) {
+		return &TransientError{Err: err}
+	}
+	return err
+}
+
+// hasErrorCode reports whether the registry returned the error code.
+func hasErrorCode(terr *transport.Error, code transport.ErrorCode) bool {
+	for _, d := range terr.Errors {
+		if d.Code == code {
+			return true
+		}
+	}
+	return false
+}
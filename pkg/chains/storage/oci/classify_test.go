@@ -0,0 +1,128 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestStoreErrorClassification(t *testing.T) {
+	tests := []struct {
+		name string
+		// failReads also fails the lookup of the signed entity.
+		failReads bool
+		status    int
+		body      string
+		check     func(error) bool
+	}{
+		{
+			name:   "unauthorized write",
+			status: http.StatusUnauthorized,
+			body:   `{"errors":[{"code":"UNAUTHORIZED","message":"authentication required"}]}`,
+			check:  func(err error) bool { return errors.Is(err, ErrUnauthorized) },
+		},
+		{
+			name:   "forbidden write",
+			status: http.StatusForbidden,
+			body:   `{"errors":[{"code":"DENIED","message":"requested access to the resource is denied"}]}`,
+			check:  func(err error) bool { return errors.Is(err, ErrUnauthorized) },
+		},
+		{
+			name:   "unknown repository",
+			status: http.StatusNotFound,
+			body:   `{"errors":[{"code":"NAME_UNKNOWN","message":"repository name not known to registry"}]}`,
+			check:  func(err error) bool { return errors.Is(err, ErrRepositoryNotFound) },
+		},
+		{
+			name:   "unavailable registry",
+			status: http.StatusServiceUnavailable,
+			check: func(err error) bool {
+				var transient *TransientError
+				return errors.As(err, &transient)
+			},
+		},
+		{
+			name:      "unauthorized lookup",
+			failReads: true,
+			status:    http.StatusForbidden,
+			body:      `{"errors":[{"code":"DENIED","message":"requested access to the resource is denied"}]}`,
+			check:     func(err error) bool { return errors.Is(err, ErrUnauthorized) },
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := logtesting.TestContextWithLogger(t)
+			var failing atomic.Bool
+			registryName := newTestRegistry(t, func(h http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					isWrite := r.Method != http.MethodGet && r.Method != http.MethodHead
+					if failing.Load() && r.URL.Path != "/v2/" && (isWrite || tc.failReads) {
+						w.Header().Set("Content-Type", "application/json")
+						w.WriteHeader(tc.status)
+						_, _ = w.Write([]byte(tc.body))
+						return
+					}
+					h.ServeHTTP(w, r)
+				})
+			})
+			ref := pushRandomImage(t, registryName)
+			failing.Store(true)
+
+			// Do not let the registry client retry the failures itself.
+			noRetries := WithRemoteOptions(remote.WithRetryBackoff(remote.Backoff{Steps: 1}))
+			as, err := NewAttestationStorer(noRetries)
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+			_, err = as.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+				Artifact: ref,
+				Payload:  &intoto.Statement{},
+				Bundle:   &signing.Bundle{Signature: []byte("envelope")},
+			})
+			if err == nil || !tc.check(err) {
+				t.Errorf("attestation Store() error = %v, not classified as expected", err)
+			}
+			ss, err := NewSimpleStorerFromConfig(noRetries)
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+			_, err = ss.Store(ctx, &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+				Artifact: ref,
+				Payload:  simple.NewSimpleStruct(ref),
+				Bundle:   &signing.Bundle{Content: []byte("{}"), Signature: []byte("signature")},
+			})
+			if err == nil || !tc.check(err) {
+				t.Errorf("signature Store() error = %v, not classified as expected", err)
+			}
+			// The registry error is still available to callers.
+			var terr *transport.Error
+			if !errors.As(err, &terr) || terr.StatusCode != tc.status {
+				t.Errorf("Store() error = %v, want a %d registry error", err, tc.status)
+			}
+		})
+	}
+}
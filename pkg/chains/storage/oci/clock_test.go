@@ -0,0 +1,114 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// fakeClock is a clock whose time only changes when stepped.
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+func (c *fakeClock) step(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+func TestWithClock_Migrate(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	ref := newReferrersTestRegistry(t)
+	fixed := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	storer, err := NewAttestationStorer(WithClock(&fakeClock{t: fixed}), WithDeleteMigratedTags())
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	storeTestAttestations(t, storer, ref, "https://example.com/predicate")
+	if _, err := storer.Migrate(ctx, ref); err != nil {
+		t.Fatalf("error during Migrate(): %v", err)
+	}
+
+	infos, err := storer.List(ctx, ref)
+	if err != nil {
+		t.Fatalf("error during List(): %v", err)
+	}
+	if len(infos) != 1 {
+		t.Fatalf("got %d attestations, want 1", len(infos))
+	}
+	if !infos[0].Created.Equal(fixed) {
+		t.Errorf("got creation time %s, want %s", infos[0].Created, fixed)
+	}
+}
+
+func TestWithClock_Dedup(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	ref := pushRandomImage(t, newTestRegistry(t, nil))
+	clock := &fakeClock{t: time.Now()}
+	// The clock applies to the dedup cache even when configured after it.
+	storer, err := NewAttestationStorer(WithInProcessDedup(time.Minute), WithClock(clock))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	statement, payload := newTestStatement(t, ref, "https://example.com/predicate")
+	req := &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  statement,
+		Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+	}
+
+	for i, want := range []int{1, 1} {
+		if _, err := storer.Store(ctx, req); err != nil {
+			t.Fatalf("error during Store() %d: %v", i, err)
+		}
+		if got := countAttestationLayers(t, ref.Repository, ref); got != want {
+			t.Errorf("got %d attestation layers after store %d, want %d", got, i, want)
+		}
+	}
+
+	clock.step(time.Minute)
+	if _, err := storer.Store(ctx, req); err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+	if got := countAttestationLayers(t, ref.Repository, ref); got != 2 {
+		t.Errorf("got %d attestation layers, want 2", got)
+	}
+}
+
+func TestWithClock_Nil(t *testing.T) {
+	if _, err := NewSimpleStorerFromConfig(WithClock(nil)); err == nil {
+		t.Error("expected an error for a nil clock")
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/types"
+)
+
+// TagCollisionError is returned when the .att tag of an artifact points at a
+// manifest that does not hold attestations, e.g. an unrelated image, which
+// storing would overwrite.
+type TagCollisionError struct {
+	// Tag is the .att tag of the artifact.
+	Tag string
+	// MediaType is the media type of the offending layer.
+	MediaType string
+}
+
+// Error implements error.
+func (e *TagCollisionError) Error() string {
+	return fmt.Sprintf("tag %s already holds a layer of media type %s, not attestations", e.Tag, e.MediaType)
+}
+
+// checkAttestationTag verifies that the existing attestations of se, read
+// from the .att tag of the artifact, only hold attestation layers.
+func checkAttestationTag(se oci.SignedEntity, tag name.Tag) error {
+	atts, err := se.Attestations()
+	if err != nil {
+		return errors.Wrap(err, "getting existing attestations")
+	}
+	m, err := atts.Manifest()
+	if err != nil {
+		return errors.Wrap(err, "getting existing attestations")
+	}
+	for _, l := range m.Layers {
+		if l.MediaType != types.DssePayloadType && l.MediaType != splitChunkMediaType {
+			return &TagCollisionError{Tag: tag.String(), MediaType: string(l.MediaType)}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	intoto "github.com/in-toto/attestation/go/v1"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestAttestationStorer_TagCollision(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	ref := pushRandomImage(t, newTestRegistry(t, nil))
+	tag, err := ociremote.AttestationTag(ref)
+	if err != nil {
+		t.Fatalf("failed to get attestation tag: %v", err)
+	}
+	// An unrelated image pushed to the .att tag of the artifact.
+	unrelated, err := random.Image(1024, 1)
+	if err != nil {
+		t.Fatalf("failed to create random image: %v", err)
+	}
+	if err := remote.Write(tag, unrelated); err != nil {
+		t.Fatalf("failed to write image to mock registry: %v", err)
+	}
+	want, err := unrelated.Digest()
+	if err != nil {
+		t.Fatalf("failed to get image digest: %v", err)
+	}
+
+	storer, err := NewAttestationStorer()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	statement, payload := newTestStatement(t, ref, "https://slsa.dev/provenance/v1")
+	_, err = storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  statement,
+		Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+	})
+	var collision *TagCollisionError
+	if !errors.As(err, &collision) || collision.Tag != tag.String() {
+		t.Fatalf("Store() error = %v, want a tag collision on %s", err, tag)
+	}
+
+	desc, err := remote.Head(tag)
+	if err != nil {
+		t.Fatalf("failed to fetch %s: %v", tag, err)
+	}
+	if desc.Digest != want {
+		t.Errorf("%s was overwritten: got %s, want %s", tag, desc.Digest, want)
+	}
+}
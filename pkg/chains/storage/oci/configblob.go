@@ -0,0 +1,129 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/pkg/errors"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+)
+
+// configBlob is the config of the attestation manifests, configured with
+// WithConfigBlob.
+type configBlob struct {
+	data      []byte
+	mediaType types.MediaType
+}
+
+func newConfigBlob(data []byte, mediaType string) (*configBlob, error) {
+	if len(data) == 0 {
+		return nil, errors.New("config blob must not be empty")
+	}
+	if _, _, err := mime.ParseMediaType(mediaType); err != nil {
+		return nil, errors.Wrapf(err, "invalid config media type %q", mediaType)
+	}
+	return &configBlob{data: data, mediaType: types.MediaType(mediaType)}, nil
+}
+
+// withConfigBlob applies the configured config blob to the attestation
+// manifest of the entity.
+func (s *AttestationStorer) withConfigBlob(se oci.SignedEntity) oci.SignedEntity {
+	if s.configBlob == nil {
+		return se
+	}
+	return &configBlobEntity{SignedEntity: se, config: s.configBlob}
+}
+
+// configBlobEntity replaces the config of the attestation manifest of the
+// wrapped entity.
+type configBlobEntity struct {
+	oci.SignedEntity
+	config *configBlob
+}
+
+// Attestations implements oci.SignedEntity
+func (e *configBlobEntity) Attestations() (oci.Signatures, error) {
+	atts, err := e.SignedEntity.Attestations()
+	if err != nil {
+		return nil, err
+	}
+	return &configBlobSignatures{Signatures: atts, config: e.config}, nil
+}
+
+// configBlobSignatures overrides the config of the wrapped signatures image.
+type configBlobSignatures struct {
+	oci.Signatures
+	config *configBlob
+}
+
+// RawConfigFile implements v1.Image
+func (s *configBlobSignatures) RawConfigFile() ([]byte, error) {
+	return s.config.data, nil
+}
+
+// ConfigFile implements v1.Image
+func (s *configBlobSignatures) ConfigFile() (*v1.ConfigFile, error) {
+	return v1.ParseConfigFile(bytes.NewReader(s.config.data))
+}
+
+// ConfigName implements v1.Image
+func (s *configBlobSignatures) ConfigName() (v1.Hash, error) {
+	h, _, err := v1.SHA256(bytes.NewReader(s.config.data))
+	return h, err
+}
+
+// Manifest implements v1.Image
+func (s *configBlobSignatures) Manifest() (*v1.Manifest, error) {
+	m, err := s.Signatures.Manifest()
+	if err != nil {
+		return nil, err
+	}
+	m = m.DeepCopy()
+	h, size, err := v1.SHA256(bytes.NewReader(s.config.data))
+	if err != nil {
+		return nil, err
+	}
+	m.Config = v1.Descriptor{
+		MediaType: s.config.mediaType,
+		Size:      size,
+		Digest:    h,
+	}
+	return m, nil
+}
+
+// RawManifest implements v1.Image
+func (s *configBlobSignatures) RawManifest() ([]byte, error) {
+	m, err := s.Manifest()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(m)
+}
+
+// Digest implements v1.Image
+func (s *configBlobSignatures) Digest() (v1.Hash, error) {
+	return partial.Digest(s)
+}
+
+// Size implements v1.Image
+func (s *configBlobSignatures) Size() (int64, error) {
+	return partial.Size(s)
+}
@@ -0,0 +1,118 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	intoto "github.com/in-toto/attestation/go/v1"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestWithConfigBlob(t *testing.T) {
+	const mediaType = "application/vnd.example.attestation.config.v1+json"
+	blob := []byte(`{"pipeline":"build","team":"example"}`)
+	ctx := logtesting.TestContextWithLogger(t)
+	ref := pushRandomImage(t, newTestRegistry(t, nil))
+
+	storer, err := NewAttestationStorer(WithConfigBlob(blob, mediaType), WithCreationTime(time.Unix(0, 0)))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	statement, payload := newTestStatement(t, ref, "https://example.com/predicate")
+	resp, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  statement,
+		Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+	})
+	if err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+
+	tag, err := ociremote.AttestationTag(ref)
+	if err != nil {
+		t.Fatalf("failed to get attestation tag: %v", err)
+	}
+	img, err := remote.Image(tag)
+	if err != nil {
+		t.Fatalf("failed to fetch attestations: %v", err)
+	}
+	m, err := img.Manifest()
+	if err != nil {
+		t.Fatalf("failed to read manifest: %v", err)
+	}
+	wantDigest, wantSize, err := v1.SHA256(bytes.NewReader(blob))
+	if err != nil {
+		t.Fatalf("failed to hash config blob: %v", err)
+	}
+	if m.Config.MediaType != types.MediaType(mediaType) || m.Config.Digest != wantDigest || m.Config.Size != wantSize {
+		t.Errorf("config = %+v, want %s %s of size %d", m.Config, mediaType, wantDigest, wantSize)
+	}
+	raw, err := img.RawConfigFile()
+	if err != nil {
+		t.Fatalf("failed to read config blob: %v", err)
+	}
+	if !bytes.Equal(raw, blob) {
+		t.Errorf("config blob = %s, want %s", raw, blob)
+	}
+	d, err := img.Digest()
+	if err != nil {
+		t.Fatalf("failed to get manifest digest: %v", err)
+	}
+	if resp.Digest != d.String() {
+		t.Errorf("StoreResponse.Digest = %s, want %s", resp.Digest, d)
+	}
+
+	got, err := storer.Retrieve(ctx, ref)
+	if err != nil {
+		t.Fatalf("error during Retrieve(): %v", err)
+	}
+	if len(got) != 1 || got[0].GetPredicateType() != statement.GetPredicateType() {
+		t.Errorf("Retrieve() = %v, want the stored statement", got)
+	}
+}
+
+func TestWithConfigBlob_Invalid(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		data      []byte
+		mediaType string
+	}{{
+		name:      "empty blob",
+		mediaType: "application/json",
+	}, {
+		name: "empty media type",
+		data: []byte("{}"),
+	}, {
+		name:      "malformed media type",
+		data:      []byte("{}"),
+		mediaType: "not a media type",
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewAttestationStorer(WithConfigBlob(tc.data, tc.mediaType)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
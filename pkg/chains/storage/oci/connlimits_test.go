@@ -0,0 +1,71 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+func TestWithConnectionLimits(t *testing.T) {
+	base := &http.Transport{MaxIdleConns: 7}
+	s, err := NewAttestationStorer(WithTransport(base), WithMaxIdleConnsPerHost(4), WithMaxConnsPerHost(16))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	got, ok := s.transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("transport is a %T, want an *http.Transport", s.transport)
+	}
+	if got.MaxIdleConnsPerHost != 4 {
+		t.Errorf("MaxIdleConnsPerHost = %d, want 4", got.MaxIdleConnsPerHost)
+	}
+	if got.MaxConnsPerHost != 16 {
+		t.Errorf("MaxConnsPerHost = %d, want 16", got.MaxConnsPerHost)
+	}
+	if got.MaxIdleConns != 7 {
+		t.Errorf("MaxIdleConns = %d, want the configured transport's 7", got.MaxIdleConns)
+	}
+	if base.MaxIdleConnsPerHost != 0 || base.MaxConnsPerHost != 0 {
+		t.Error("the configured transport was modified")
+	}
+
+	ss, err := NewSimpleStorerFromConfig(WithMaxConnsPerHost(2))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	if got := ss.transport.(*http.Transport); got.MaxConnsPerHost != 2 {
+		t.Errorf("MaxConnsPerHost = %d, want 2", got.MaxConnsPerHost)
+	}
+	if remote.DefaultTransport.(*http.Transport).MaxConnsPerHost == 2 {
+		t.Error("the default transport was modified")
+	}
+}
+
+func TestWithConnectionLimits_Invalid(t *testing.T) {
+	for name, opts := range map[string][]AttestationStorerOption{
+		"zero idle":        {WithMaxIdleConnsPerHost(0)},
+		"negative conns":   {WithMaxConnsPerHost(-1)},
+		"custom transport": {WithTransport(&countingTransport{}), WithMaxConnsPerHost(1)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewAttestationStorer(opts...); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/pkg/errors"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+)
+
+// CopyResult is the outcome of copying a signature, attestation or referrer
+// manifest.
+type CopyResult struct {
+	// Source is the reference the manifest was copied from.
+	Source string
+	// Destination is the reference the manifest was copied to.
+	Destination string
+	// Digest is the digest of the manifest, if it could be fetched.
+	Digest string
+	// Err is the error copying the manifest, if any.
+	Err error
+}
+
+// Copy copies the signatures and attestations of the artifact src to the
+// repository dst, for the same digest, e.g. when promoting an image: its .sig
+// and .att manifests and its referrers. The manifests are copied byte for
+// byte, so their signatures, certificate chains and formats are preserved.
+// srcOpts and dstOpts are the remote options, e.g. the authentication, used
+// for the source and destination repositories.
+//
+// Every manifest is attempted even if others fail. The result of each is
+// returned along with an error aggregating the failures, so that callers can
+// retry only the failed copies.
+func Copy(ctx context.Context, src name.Digest, dst name.Repository, srcOpts, dstOpts []remote.Option) ([]CopyResult, error) {
+	srcOpts = append(append([]remote.Option{}, srcOpts...), remote.WithContext(ctx))
+	dstOpts = append(append([]remote.Option{}, dstOpts...), remote.WithContext(ctx))
+
+	var results []CopyResult
+	for _, tagFor := range []func(name.Reference, ...ociremote.Option) (name.Tag, error){ociremote.SignatureTag, ociremote.AttestationTag} {
+		tag, err := tagFor(src)
+		if err != nil {
+			return nil, err
+		}
+		if r, ok := copyManifest(tag, dst.Tag(tag.TagStr()), srcOpts, dstOpts); ok {
+			results = append(results, r)
+		}
+	}
+
+	idx, err := ociremote.Referrers(src, "", ociremote.WithRemoteOptions(srcOpts...))
+	var terr *transport.Error
+	switch {
+	case errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound:
+	case err != nil:
+		results = append(results, CopyResult{
+			Source: src.String(),
+			Err:    errors.Wrapf(err, "listing referrers of %s", src.String()),
+		})
+	default:
+		for _, desc := range idx.Manifests {
+			from := src.Context().Digest(desc.Digest.String())
+			if r, ok := copyManifest(from, dst.Digest(desc.Digest.String()), srcOpts, dstOpts); ok {
+				results = append(results, r)
+			}
+		}
+	}
+
+	var errs []error
+	for _, r := range results {
+		if r.Err != nil {
+			errs = append(errs, r.Err)
+		}
+	}
+	return results, stderrors.Join(errs...)
+}
+
+// copyManifest copies the manifest at from, along with its blobs, to to. It
+// reports false if there is no manifest at from.
+func copyManifest(from, to name.Reference, srcOpts, dstOpts []remote.Option) (CopyResult, bool) {
+	r := CopyResult{Source: from.String(), Destination: to.String()}
+	desc, err := remote.Get(from, srcOpts...)
+	var terr *transport.Error
+	if errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound {
+		return r, false
+	} else if err != nil {
+		r.Err = errors.Wrapf(err, "fetching %s", from.String())
+		return r, true
+	}
+	r.Digest = desc.Digest.String()
+
+	if desc.MediaType.IsIndex() {
+		idx, err := desc.ImageIndex()
+		if err == nil {
+			err = remote.WriteIndex(to, idx, dstOpts...)
+		}
+		if err != nil {
+			r.Err = errors.Wrapf(err, "copying %s to %s", from.String(), to.String())
+		}
+		return r, true
+	}
+	img, err := desc.Image()
+	if err == nil {
+		err = remote.Write(to, img, dstOpts...)
+	}
+	if err != nil {
+		r.Err = errors.Wrapf(err, "copying %s to %s", from.String(), to.String())
+	}
+	return r, true
+}
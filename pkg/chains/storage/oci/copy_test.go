@@ -0,0 +1,140 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// newAuthenticatedTestRegistry starts an in-memory registry supporting the
+// referrers API that requires the credentials of auth, and returns its name.
+func newAuthenticatedTestRegistry(t *testing.T, auth *authn.Basic) string {
+	t.Helper()
+	h := registry.New(registry.WithReferrersSupport(true))
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != auth.Username || pass != auth.Password {
+			w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, r)
+	}))
+	t.Cleanup(s.Close)
+	return strings.TrimPrefix(s.URL, "http://")
+}
+
+func TestCopy(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	src := newReferrersTestRegistry(t)
+	as, err := NewAttestationStorer()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	storeTestAttestations(t, as, src, "https://example.com/predicate")
+	ss, err := NewSimpleStorerFromConfig()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	if _, err := ss.Store(ctx, &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+		Artifact: src,
+		Payload:  simple.NewSimpleStruct(src),
+		Bundle:   &signing.Bundle{Content: []byte("{}"), Signature: []byte("signature")},
+	}); err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+	writeTestReferrers(t, src)
+
+	auth := &authn.Basic{Username: "user", Password: "pass"}
+	dst, err := name.NewRepository(newAuthenticatedTestRegistry(t, auth) + "/prod/img")
+	if err != nil {
+		t.Fatalf("failed to parse repository: %v", err)
+	}
+	dstOpts := []remote.Option{remote.WithAuth(auth)}
+	img, err := remote.Image(src)
+	if err != nil {
+		t.Fatalf("failed to fetch image: %v", err)
+	}
+	if err := remote.Write(dst.Digest(src.DigestStr()), img, dstOpts...); err != nil {
+		t.Fatalf("failed to promote image: %v", err)
+	}
+
+	results, err := Copy(ctx, src, dst, nil, dstOpts)
+	if err != nil {
+		t.Fatalf("error during Copy(): %v", err)
+	}
+	// The .sig and .att manifests and both referrers.
+	if len(results) != 4 {
+		t.Fatalf("Copy() returned %d results, want 4: %+v", len(results), results)
+	}
+	for _, r := range results {
+		ref, err := name.ParseReference(r.Destination)
+		if err != nil {
+			t.Fatalf("failed to parse destination %q: %v", r.Destination, err)
+		}
+		desc, err := remote.Head(ref, dstOpts...)
+		if err != nil {
+			t.Errorf("%s was not copied: %v", r.Source, err)
+			continue
+		}
+		if got := desc.Digest.String(); got != r.Digest {
+			t.Errorf("%s copied with digest %s, want %s", r.Source, got, r.Digest)
+		}
+	}
+
+	// The attestations are read back from the destination, referrers included.
+	dstStorer, err := NewAttestationStorer(WithAuthenticator(auth))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	if got := listedPredicateTypes(t, dstStorer, dst.Digest(src.DigestStr())); len(got) != 3 {
+		t.Errorf("listed %v at the destination, want 3 attestations", got)
+	}
+}
+
+func TestCopy_Partial(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	src := newReferrersTestRegistry(t)
+	as, err := NewAttestationStorer()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	storeTestAttestations(t, as, src, "https://example.com/predicate")
+	dst, err := name.NewRepository(newAuthenticatedTestRegistry(t, &authn.Basic{Username: "user", Password: "pass"}) + "/prod/img")
+	if err != nil {
+		t.Fatalf("failed to parse repository: %v", err)
+	}
+
+	// Without credentials for the destination, the .att manifest, the only
+	// one of the source, cannot be copied.
+	results, err := Copy(ctx, src, dst, nil, nil)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(results) != 1 || results[0].Err == nil || results[0].Digest == "" {
+		t.Errorf("unexpected results: %+v", results)
+	}
+}
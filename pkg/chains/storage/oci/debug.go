@@ -0,0 +1,126 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"knative.dev/pkg/logging"
+)
+
+// redacted replaces the secrets in logged requests and responses.
+const redacted = "REDACTED"
+
+// debugRequestHeaders and debugResponseHeaders are the headers logged by the
+// debug transport. Authorization is logged with its credentials redacted.
+var (
+	debugRequestHeaders  = []string{"Accept", "Content-Type", "Content-Length", "User-Agent", "Authorization"}
+	debugResponseHeaders = []string{
+		"Content-Type", "Content-Length", "Docker-Content-Digest", "Docker-Distribution-Api-Version",
+		"Location", "Www-Authenticate", "Retry-After", "Ratelimit-Limit", "Ratelimit-Remaining",
+		"Oci-Subject", "Oci-Filters-Applied",
+	}
+)
+
+// debugQueryParameters are the query parameters logged as is. The values of
+// the others, such as the signatures of presigned blob URLs, are redacted.
+var debugQueryParameters = map[string]bool{
+	"service":      true,
+	"scope":        true,
+	"n":            true,
+	"last":         true,
+	"digest":       true,
+	"mount":        true,
+	"from":         true,
+	"artifactType": true,
+}
+
+// debugTransport logs the registry requests and their responses with the
+// logger of the request context.
+type debugTransport struct {
+	inner http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	logger := logging.FromContext(req.Context())
+	start := time.Now()
+	resp, err := t.inner.RoundTrip(req)
+	fields := []interface{}{
+		"method", req.Method,
+		"url", redactURL(req.URL),
+		"requestHeaders", debugHeaders(req.Header, debugRequestHeaders),
+		"duration", time.Since(start),
+	}
+	if err != nil {
+		logger.Infow("Registry request failed", append(fields, "error", err.Error())...)
+		return nil, err
+	}
+	logger.Infow("Registry request", append(fields,
+		"status", resp.StatusCode,
+		"responseHeaders", debugHeaders(resp.Header, debugResponseHeaders),
+	)...)
+	return resp, nil
+}
+
+// debugHeaders returns the values of the named headers in h, with the
+// credentials of the Authorization header and the query parameters of the
+// Location header redacted.
+func debugHeaders(h http.Header, names []string) map[string]string {
+	out := map[string]string{}
+	for _, n := range names {
+		v := h.Get(n)
+		if v == "" {
+			continue
+		}
+		switch n {
+		case "Authorization":
+			// Only the scheme, e.g. Basic or Bearer, is logged.
+			scheme, _, _ := strings.Cut(v, " ")
+			v = scheme + " " + redacted
+		case "Location":
+			if u, err := url.Parse(v); err == nil {
+				v = redactURL(u)
+			} else {
+				v = redacted
+			}
+		}
+		out[n] = v
+	}
+	return out
+}
+
+// redactURL returns u without user info and with the values of the query
+// parameters that may hold secrets redacted.
+func redactURL(u *url.URL) string {
+	c := *u
+	c.User = nil
+	if c.RawQuery != "" {
+		q := c.Query()
+		for k, vs := range q {
+			if debugQueryParameters[k] {
+				continue
+			}
+			for i := range vs {
+				vs[i] = redacted
+			}
+		}
+		c.RawQuery = q.Encode()
+	}
+	return c.String()
+}
@@ -0,0 +1,153 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	"knative.dev/pkg/logging"
+)
+
+// dedupCache suppresses the stores of payloads already stored, or being
+// stored, for the same artifact by this process within a short time,
+// configured with WithInProcessDedup. Unlike WithSkipIfExists, it does not
+// depend on the registry serving the earlier writes yet.
+type dedupCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[dedupKey]*dedupEntry
+	// nextSweep is when the expired entries are next removed.
+	nextSweep time.Time
+}
+
+// dedupKey identifies a payload stored for an artifact.
+type dedupKey struct {
+	artifact string
+	payload  [sha256.Size]byte
+}
+
+// dedupEntry is a store in flight, or completed at expires minus the ttl.
+type dedupEntry struct {
+	// done is closed once the store completed.
+	done chan struct{}
+	// resp is the response of the store, nil if it failed.
+	resp    *api.StoreResponse
+	expires time.Time
+}
+
+func newDedupCache(ttl time.Duration) *dedupCache {
+	return &dedupCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[dedupKey]*dedupEntry{},
+	}
+}
+
+// newDedupKey identifies the payload made of parts, e.g. the content and the
+// signature of a signature, stored for artifact.
+func newDedupKey(artifact name.Digest, parts ...[]byte) dedupKey {
+	h := sha256.New()
+	for _, p := range parts {
+		// Hash the parts separately so that their boundaries are significant.
+		d := sha256.Sum256(p)
+		h.Write(d[:])
+	}
+	key := dedupKey{artifact: artifact.String()}
+	h.Sum(key.payload[:0])
+	return key
+}
+
+// do calls store unless the same payload was stored for the artifact within
+// the ttl, in which case the response of that store is returned. Concurrent
+// stores of the same payload wait for the first one, and store again only if
+// it failed.
+func (c *dedupCache) do(ctx context.Context, key dedupKey, store func() (*api.StoreResponse, error)) (*api.StoreResponse, error) {
+	for {
+		c.mu.Lock()
+		now := c.now()
+		e, ok := c.entries[key]
+		if ok && e.resp != nil && !now.Before(e.expires) {
+			delete(c.entries, key)
+			ok = false
+		}
+		if !ok {
+			c.sweep(now)
+			e = &dedupEntry{done: make(chan struct{})}
+			c.entries[key] = e
+			c.mu.Unlock()
+			return c.complete(key, e, store)
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-e.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if e.resp != nil {
+			logging.FromContext(ctx).Infof("Skipping the store of %s, the same payload was stored at %s", key.artifact, e.resp.Reference)
+			resp := *e.resp
+			return &resp, nil
+		}
+	}
+}
+
+// sweep removes the completed stores whose ttl elapsed, at most once per ttl.
+// c.mu must be held.
+func (c *dedupCache) sweep(now time.Time) {
+	if now.Before(c.nextSweep) {
+		return
+	}
+	for k, e := range c.entries {
+		if e.resp != nil && !now.Before(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.nextSweep = now.Add(c.ttl)
+}
+
+// complete runs store for the entry e just added for key, recording its
+// response, or dropping the entry if it failed or panicked, so that the
+// stores waiting for it store again.
+func (c *dedupCache) complete(key dedupKey, e *dedupEntry, store func() (*api.StoreResponse, error)) (resp *api.StoreResponse, err error) {
+	defer func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if err != nil || resp == nil {
+			delete(c.entries, key)
+		} else {
+			e.resp = resp
+			e.expires = c.now().Add(c.ttl)
+		}
+		close(e.done)
+	}()
+	return store()
+}
+
+// dedupStore calls store through the cache configured with
+// WithInProcessDedup, if any.
+func (b *baseStorer) dedupStore(ctx context.Context, key func() dedupKey, store func() (*api.StoreResponse, error)) (*api.StoreResponse, error) {
+	if b.dedup == nil {
+		return store()
+	}
+	return b.dedup.do(ctx, key(), store)
+}
@@ -0,0 +1,197 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestWithInProcessDedup(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	ref := pushRandomImage(t, newTestRegistry(t, nil))
+	storer, err := NewAttestationStorer(WithInProcessDedup(time.Minute))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	now := time.Now()
+	storer.dedup.now = func() time.Time { return now }
+	newRequest := func(predicateType string) *api.StoreRequest[name.Digest, *intoto.Statement] {
+		statement, payload := newTestStatement(t, ref, predicateType)
+		return &api.StoreRequest[name.Digest, *intoto.Statement]{
+			Artifact: ref,
+			Payload:  statement,
+			Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+		}
+	}
+	req := newRequest("https://example.com/predicate")
+
+	// Concurrent stores of the same payload write it once.
+	var wg sync.WaitGroup
+	resps := make([]*api.StoreResponse, 4)
+	for i := range resps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := storer.Store(ctx, req)
+			if err != nil {
+				t.Errorf("error during Store(): %v", err)
+			}
+			resps[i] = resp
+		}()
+	}
+	wg.Wait()
+	if got := countAttestationLayers(t, ref.Repository, ref); got != 1 {
+		t.Errorf("got %d attestation layers, want 1", got)
+	}
+	for _, resp := range resps[1:] {
+		if diff := cmp.Diff(resps[0], resp); diff != "" {
+			t.Errorf("unexpected response of a skipped store (-first +got):\n%s", diff)
+		}
+	}
+
+	// Other payloads are stored.
+	if _, err := storer.Store(ctx, newRequest("https://example.com/other")); err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+	if got := countAttestationLayers(t, ref.Repository, ref); got != 2 {
+		t.Errorf("got %d attestation layers, want 2", got)
+	}
+
+	// The same payload is stored again once the ttl elapsed.
+	now = now.Add(time.Minute)
+	if _, err := storer.Store(ctx, req); err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+	if got := countAttestationLayers(t, ref.Repository, ref); got != 3 {
+		t.Errorf("got %d attestation layers, want 3", got)
+	}
+}
+
+func TestWithInProcessDedup_Failure(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	var rejecting atomic.Bool
+	ref := pushRandomImage(t, newTestRegistry(t, func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if rejecting.Load() && r.Method == http.MethodPut && strings.Contains(r.URL.Path, "/manifests/") {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	}))
+	storer, err := NewSimpleStorerFromConfig(WithInProcessDedup(time.Minute))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	req := &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+		Artifact: ref,
+		Payload:  simple.NewSimpleStruct(ref),
+		Bundle:   &signing.Bundle{Content: []byte("{}"), Signature: []byte("signature")},
+	}
+
+	// Failed stores are not remembered.
+	rejecting.Store(true)
+	if _, err := storer.Store(ctx, req); err == nil {
+		t.Fatal("expected an error")
+	}
+	rejecting.Store(false)
+	for range 2 {
+		if _, err := storer.Store(ctx, req); err != nil {
+			t.Fatalf("error during Store(): %v", err)
+		}
+	}
+	img, err := remote.Image(ref.Context().Tag(strings.ReplaceAll(ref.DigestStr(), ":", "-") + ".sig"))
+	if err != nil {
+		t.Fatalf("failed to fetch signatures: %v", err)
+	}
+	if layers, err := img.Layers(); err != nil || len(layers) != 1 {
+		t.Errorf("got %d signature layers, %v, want 1", len(layers), err)
+	}
+}
+
+func TestDedupCache_Sweep(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	clock := &fakeClock{t: time.Now()}
+	c := newDedupCache(time.Minute)
+	c.now = clock.Now
+	store := func() (*api.StoreResponse, error) {
+		return &api.StoreResponse{Reference: "example.com/img:tag"}, nil
+	}
+	for i := range 3 {
+		if _, err := c.do(ctx, dedupKey{artifact: fmt.Sprint(i)}, store); err != nil {
+			t.Fatal(err)
+		}
+	}
+	clock.step(time.Minute)
+	if _, err := c.do(ctx, dedupKey{artifact: "new"}, store); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.entries) != 1 {
+		t.Errorf("got %d entries after the others expired, want 1", len(c.entries))
+	}
+}
+
+func TestDedupCache_Panic(t *testing.T) {
+	ctx, cancel := context.WithTimeout(logtesting.TestContextWithLogger(t), 10*time.Second)
+	defer cancel()
+	c := newDedupCache(time.Minute)
+	key := dedupKey{artifact: "artifact"}
+
+	started, release := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer func() { _ = recover() }()
+		_, _ = c.do(ctx, key, func() (*api.StoreResponse, error) {
+			close(started)
+			<-release
+			panic("store panicked")
+		})
+	}()
+	<-started
+
+	// The stores waiting for the one that panicked store again.
+	errc := make(chan error, 1)
+	go func() {
+		_, err := c.do(ctx, key, func() (*api.StoreResponse, error) {
+			return &api.StoreResponse{Reference: "example.com/img:tag"}, nil
+		})
+		errc <- err
+	}()
+	close(release)
+	if err := <-errc; err != nil {
+		t.Errorf("do() = %v, want the store to run after the panic", err)
+	}
+}
+
+func TestWithInProcessDedup_Invalid(t *testing.T) {
+	if _, err := NewAttestationStorer(WithInProcessDedup(0)); err == nil {
+		t.Error("expected an error for a zero ttl")
+	}
+}
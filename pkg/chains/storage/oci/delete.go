@@ -0,0 +1,328 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/match"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/pkg/errors"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/empty"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"knative.dev/pkg/logging"
+)
+
+// ErrDeleteUnsupported is matched by errors of registries refusing to delete
+// manifests.
+var ErrDeleteUnsupported = errors.New("registry does not support deleting manifests")
+
+// Delete removes the attestations stored for the given artifact: its .att
+// manifest and its in-toto and sigstore bundle referrers. Deleting
+// attestations that do not exist is not an error. Errors of registries that
+// do not support deleting manifests match ErrDeleteUnsupported.
+func (s *AttestationStorer) Delete(ctx context.Context, artifact name.Digest) error {
+	return s.delete(ctx, artifact, "")
+}
+
+// DeleteByPredicateType removes the attestations of the given predicate type
+// stored for the artifact, like Delete. The .att manifest is rewritten without
+// them, and deleted if no other attestations remain. Referrers holding
+// attestations of other predicate types as well are not deleted and reported
+// as an error.
+func (s *AttestationStorer) DeleteByPredicateType(ctx context.Context, artifact name.Digest, predicateType string) error {
+	if predicateType == "" {
+		return errors.New("predicate type must not be empty")
+	}
+	return s.delete(ctx, artifact, predicateType)
+}
+
+// delete removes the attestations of predicateType, or all of them if empty.
+func (s *AttestationStorer) delete(ctx context.Context, artifact name.Digest, predicateType string) error {
+	if hs := s.hostStorer(artifact); hs != nil {
+		return hs.delete(ctx, artifact, predicateType)
+	}
+	repo := s.targetRepository(artifact)
+	// The .att manifest is rewritten like on stores, which must not interleave.
+	unlock, err := s.lockEntity(ctx, artifact, repo)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	se, err := s.storedEntity(ctx, artifact, repo)
+	if err != nil {
+		return err
+	}
+	atts, err := se.Attestations()
+	if err != nil {
+		return errors.Wrap(err, "getting attestations")
+	}
+	tag, err := ociremote.AttestationTag(artifact, s.tagOptions(repo)...)
+	if err != nil {
+		return err
+	}
+	if err := s.deleteTagged(ctx, tag, atts, predicateType); err != nil {
+		return errors.Wrapf(err, "deleting attestations of %s", artifact.String())
+	}
+	if s.localLayout != "" {
+		return nil
+	}
+	if err := s.deleteReferrers(ctx, repo.Digest(artifact.DigestStr()), predicateType); err != nil {
+		return errors.Wrapf(err, "deleting attestation referrers of %s", artifact.String())
+	}
+	return nil
+}
+
+// deleteTagged removes the attestations of predicateType, or all of them if
+// empty, from the .att manifest atts.
+func (s *AttestationStorer) deleteTagged(ctx context.Context, tag name.Tag, atts oci.Signatures, predicateType string) error {
+	sigs, err := atts.Get()
+	if err != nil {
+		return errors.Wrap(err, "getting attestations")
+	}
+	if len(sigs) == 0 {
+		return nil
+	}
+	var kept []oci.Signature
+	if predicateType != "" {
+		types, err := layerPredicateTypes(sigs)
+		if err != nil {
+			return err
+		}
+		for i, sig := range sigs {
+			if types[i] != predicateType {
+				kept = append(kept, sig)
+			}
+		}
+		if len(kept) == len(sigs) {
+			return nil
+		}
+	}
+	if len(kept) == 0 {
+		return s.deleteAttached(ctx, tag, atts)
+	}
+
+	rewritten, err := mutate.AppendSignatures(empty.Signatures(), false, kept...)
+	if err != nil {
+		return err
+	}
+	if s.dryRun {
+		logging.FromContext(ctx).Infof("Dry run: would rewrite %s without the %s attestations", tag.String(), predicateType)
+		return nil
+	}
+	if s.localLayout != "" {
+		return writeLayout(s.localLayout, tag, rewritten)
+	}
+	return s.retryWrite(ctx, tag.String(), func() error {
+		return remote.Write(tag, rewritten, s.remoteOptions(ctx)...)
+	})
+}
+
+// deleteReferrers deletes the referrers of the artifact holding attestations
+// of predicateType, or any attestations if empty.
+func (s *AttestationStorer) deleteReferrers(ctx context.Context, d name.Digest, predicateType string) error {
+	idx, err := ociremote.Referrers(d, "", ociremote.WithRemoteOptions(s.remoteOptions(ctx)...))
+	var terr *transport.Error
+	if errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "listing referrers")
+	}
+
+	for _, desc := range idx.Manifests {
+		ref := d.Context().Digest(desc.Digest.String())
+		img, err := remote.Image(ref, s.remoteOptions(ctx)...)
+		if err != nil {
+			return errors.Wrapf(err, "fetching referrer %s", desc.Digest)
+		}
+		layers, err := img.Layers()
+		if err != nil {
+			return errors.Wrapf(err, "fetching referrer %s", desc.Digest)
+		}
+		statements, matched := 0, 0
+		for _, l := range layers {
+			statement, err := statementFromLayer(l)
+			if err != nil {
+				return errors.Wrapf(err, "reading referrer %s", desc.Digest)
+			}
+			if statement == nil {
+				continue
+			}
+			statements++
+			if predicateType == "" || statement.GetPredicateType() == predicateType {
+				matched++
+			}
+		}
+		switch {
+		case matched == 0:
+			continue
+		case matched < statements:
+			return errors.Errorf("referrer %s also holds attestations of other predicate types than %s", desc.Digest, predicateType)
+		}
+		if err := s.deleteManifest(ctx, ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// layerPredicateTypes returns the predicate type of the statement held by
+// each of the attestation layers, or an empty string for layers that cannot
+// be decoded. The chunks of split payloads all get the predicate type of the
+// reassembled payload. The predicate type annotation is not used, as it may
+// have been shortened.
+func layerPredicateTypes(sigs []oci.Signature) ([]string, error) {
+	types := make([]string, len(sigs))
+	groups := map[string][]int{}
+	for i, sig := range sigs {
+		ann, err := sig.Annotations()
+		if err != nil {
+			return nil, errors.Wrap(err, "reading attestation annotations")
+		}
+		if group, ok := ann[splitGroupAnnotation]; ok {
+			groups[group] = append(groups[group], i)
+			continue
+		}
+		payload, err := sig.Payload()
+		if err != nil {
+			return nil, errors.Wrap(err, "reading attestation")
+		}
+		if statement, err := statementFromEnvelope(payload); err == nil {
+			types[i] = statement.GetPredicateType()
+		}
+	}
+	for _, indexes := range groups {
+		chunks := make([]oci.Signature, 0, len(indexes))
+		for _, i := range indexes {
+			chunks = append(chunks, sigs[i])
+		}
+		payloads, err := reassemblePayloads(chunks)
+		if err != nil {
+			return nil, err
+		}
+		statement, err := statementFromEnvelope(payloads[0])
+		if err != nil {
+			continue
+		}
+		for _, i := range indexes {
+			types[i] = statement.GetPredicateType()
+		}
+	}
+	return types, nil
+}
+
+// Delete removes the signatures stored for the given artifact, i.e. its .sig
+// manifest. Deleting signatures that do not exist is not an error. Errors of
+// registries that do not support deleting manifests match
+// ErrDeleteUnsupported.
+func (s *SimpleStorer) Delete(ctx context.Context, artifact name.Digest) error {
+	if hs := s.hostStorer(artifact); hs != nil {
+		return hs.Delete(ctx, artifact)
+	}
+	repo := s.targetRepository(artifact)
+	unlock, err := s.lockEntity(ctx, artifact, repo)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	se, err := s.storedEntity(ctx, artifact, repo)
+	if err != nil {
+		return err
+	}
+	sigs, err := se.Signatures()
+	if err != nil {
+		return errors.Wrap(err, "getting signatures")
+	}
+	tag, err := ociremote.SignatureTag(artifact, s.tagOptions(repo)...)
+	if err != nil {
+		return err
+	}
+	if err := s.deleteAttached(ctx, tag, sigs); err != nil {
+		return errors.Wrapf(err, "deleting signatures of %s", artifact.String())
+	}
+	return nil
+}
+
+// deleteAttached deletes the signature or attestation manifest sigs, found at
+// tag, if it holds any layers.
+func (b *baseStorer) deleteAttached(ctx context.Context, tag name.Tag, sigs oci.Signatures) error {
+	layers, err := sigs.Get()
+	if err != nil || len(layers) == 0 {
+		return err
+	}
+	if b.dryRun {
+		logging.FromContext(ctx).Infof("Dry run: would delete %s", tag.String())
+		return nil
+	}
+	if b.localLayout != "" {
+		return removeFromLayout(b.localLayout, tag)
+	}
+	d, err := sigs.Digest()
+	if err != nil {
+		return err
+	}
+	// Not all registries delete the tags of deleted manifests, nor support
+	// deleting tags, so the tag is deleted first when possible.
+	if err := b.deleteManifest(ctx, tag); err != nil && !errors.Is(err, ErrDeleteUnsupported) {
+		return err
+	}
+	return b.deleteManifest(ctx, tag.Context().Digest(d.String()))
+}
+
+// deleteManifest deletes the manifest at ref. Manifests that do not exist are
+// ignored.
+func (b *baseStorer) deleteManifest(ctx context.Context, ref name.Reference) error {
+	if b.dryRun {
+		logging.FromContext(ctx).Infof("Dry run: would delete %s", ref.String())
+		return nil
+	}
+	return b.retryWrite(ctx, "deletion of "+ref.String(), func() error {
+		err := remote.Delete(ref, b.remoteOptions(ctx)...)
+		var terr *transport.Error
+		switch {
+		case !errors.As(err, &terr):
+			return err
+		case terr.StatusCode == http.StatusNotFound:
+			return nil
+		case terr.StatusCode == http.StatusMethodNotAllowed || hasErrorCode(terr, transport.UnsupportedErrorCode):
+			return &classifiedError{sentinel: ErrDeleteUnsupported, err: err}
+		}
+		return err
+	})
+}
+
+// removeFromLayout removes the manifest named tag from the layout at path.
+func removeFromLayout(path string, tag name.Tag) error {
+	layoutMu.Lock()
+	defer layoutMu.Unlock()
+
+	p, err := layout.FromPath(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return errors.Wrapf(err, "opening OCI layout %s", path)
+	}
+	if err := p.RemoveDescriptors(match.Annotation(refNameAnnotation, tag.Name())); err != nil {
+		return errors.Wrapf(err, "removing %s from OCI layout %s", tag.Name(), path)
+	}
+	return nil
+}
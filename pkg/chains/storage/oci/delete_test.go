@@ -0,0 +1,314 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/pkg/errors"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// newReferrersTestRegistry starts an in-memory registry supporting the
+// referrers API and returns an image pushed to it.
+func newReferrersTestRegistry(t *testing.T) name.Digest {
+	t.Helper()
+	s := httptest.NewServer(registry.New(registry.WithReferrersSupport(true)))
+	t.Cleanup(s.Close)
+	return pushRandomImage(t, strings.TrimPrefix(s.URL, "http://"))
+}
+
+// storeTestAttestations stores an attestation of each predicate type for ref.
+func storeTestAttestations(t *testing.T, storer *AttestationStorer, ref name.Digest, predicateTypes ...string) {
+	t.Helper()
+	ctx := logtesting.TestContextWithLogger(t)
+	for _, pt := range predicateTypes {
+		statement, payload := newTestStatement(t, ref, pt)
+		if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+			Artifact: ref,
+			Payload:  statement,
+			Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+		}); err != nil {
+			t.Fatalf("error during Store(): %v", err)
+		}
+	}
+}
+
+// listedPredicateTypes returns the predicate types of the attestations listed for ref.
+func listedPredicateTypes(t *testing.T, storer *AttestationStorer, ref name.Digest) []string {
+	t.Helper()
+	infos, err := storer.List(logtesting.TestContextWithLogger(t), ref)
+	if err != nil {
+		t.Fatalf("error during List(): %v", err)
+	}
+	var types []string
+	for _, info := range infos {
+		types = append(types, info.PredicateType)
+	}
+	return types
+}
+
+func TestAttestationStorer_Delete(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	ref := newReferrersTestRegistry(t)
+	storer, err := NewAttestationStorer()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	storeTestAttestations(t, storer, ref, "https://example.com/first", "https://example.com/second")
+	writeTestReferrers(t, ref)
+
+	for i := 0; i < 2; i++ {
+		if err := storer.Delete(ctx, ref); err != nil {
+			t.Fatalf("Delete() #%d = %v", i+1, err)
+		}
+		if got := listedPredicateTypes(t, storer, ref); len(got) != 0 {
+			t.Errorf("attestations left after Delete() #%d: %v", i+1, got)
+		}
+	}
+}
+
+func TestAttestationStorer_DeleteByPredicateType(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+
+	t.Run("tagged", func(t *testing.T) {
+		ref := newReferrersTestRegistry(t)
+		storer, err := NewAttestationStorer()
+		if err != nil {
+			t.Fatalf("failed to create storer: %v", err)
+		}
+		storeTestAttestations(t, storer, ref, "https://example.com/first", "https://example.com/second", "https://example.com/first")
+		if err := storer.DeleteByPredicateType(ctx, ref, "https://example.com/first"); err != nil {
+			t.Fatalf("DeleteByPredicateType() = %v", err)
+		}
+		if got := listedPredicateTypes(t, storer, ref); len(got) != 1 || got[0] != "https://example.com/second" {
+			t.Errorf("attestations left = %v, want only the second", got)
+		}
+		if err := storer.DeleteByPredicateType(ctx, ref, "https://example.com/second"); err != nil {
+			t.Fatalf("DeleteByPredicateType() = %v", err)
+		}
+		if got := listedPredicateTypes(t, storer, ref); len(got) != 0 {
+			t.Errorf("attestations left = %v, want none", got)
+		}
+		if err := storer.DeleteByPredicateType(ctx, ref, "https://example.com/second"); err != nil {
+			t.Errorf("DeleteByPredicateType() of deleted attestations = %v", err)
+		}
+	})
+
+	t.Run("referrers", func(t *testing.T) {
+		ref := newReferrersTestRegistry(t)
+		storer, err := NewAttestationStorer()
+		if err != nil {
+			t.Fatalf("failed to create storer: %v", err)
+		}
+		writeTestReferrers(t, ref)
+		if err := storer.DeleteByPredicateType(ctx, ref, "https://example.com/bundle"); err != nil {
+			t.Fatalf("DeleteByPredicateType() = %v", err)
+		}
+		if got := listedPredicateTypes(t, storer, ref); len(got) != 1 || got[0] != "https://example.com/referrer" {
+			t.Errorf("attestations left = %v, want only the referrer", got)
+		}
+	})
+
+	t.Run("mixed referrer", func(t *testing.T) {
+		ref := newReferrersTestRegistry(t)
+		storer, err := NewAttestationStorer()
+		if err != nil {
+			t.Fatalf("failed to create storer: %v", err)
+		}
+		// The referrer written by cosign also holds the tagged attestation.
+		storeTestAttestations(t, storer, ref, "https://example.com/tagged")
+		writeTestReferrers(t, ref)
+		if err := storer.DeleteByPredicateType(ctx, ref, "https://example.com/referrer"); err == nil {
+			t.Error("expected an error for a referrer holding other predicate types")
+		}
+	})
+
+	storer, err := NewAttestationStorer()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	if err := storer.DeleteByPredicateType(ctx, newReferrersTestRegistry(t), ""); err == nil {
+		t.Error("expected an error for an empty predicate type")
+	}
+}
+
+func TestAttestationStorer_DeleteDuringStore(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+
+	// While racing, the first write of the .att manifest is held back, so
+	// unsynchronized operations both rewrite the manifest from the state read
+	// before it.
+	var (
+		mu      sync.Mutex
+		delayed bool
+	)
+	registryName := newTestRegistry(t, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if (r.Method == http.MethodPut || r.Method == http.MethodDelete) && strings.HasSuffix(r.URL.Path, ".att") {
+				mu.Lock()
+				delay := !delayed
+				delayed = true
+				mu.Unlock()
+				if delay {
+					time.Sleep(100 * time.Millisecond)
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+	ref := pushRandomImage(t, registryName)
+	storer, err := NewAttestationStorer()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	want := []string{"https://example.com/base"}
+	storeTestAttestations(t, storer, ref, want[0])
+
+	const removed = "https://example.com/removed"
+	for i := range 3 {
+		storeTestAttestations(t, storer, ref, removed)
+		kept := fmt.Sprintf("https://example.com/kept-%d", i)
+		statement, payload := newTestStatement(t, ref, kept)
+
+		mu.Lock()
+		delayed = false
+		mu.Unlock()
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+				Artifact: ref,
+				Payload:  statement,
+				Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+			}); err != nil {
+				t.Errorf("error during Store(): %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if err := storer.DeleteByPredicateType(ctx, ref, removed); err != nil {
+				t.Errorf("DeleteByPredicateType() = %v", err)
+			}
+		}()
+		wg.Wait()
+
+		// Whichever runs first, the stored attestation is kept and the
+		// deleted one is not brought back.
+		want = append(want, kept)
+		got := listedPredicateTypes(t, storer, ref)
+		slices.Sort(got)
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("unexpected attestations after round %d (-want +got):\n%s", i, diff)
+		}
+	}
+}
+
+func TestSimpleStorer_Delete(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	ref := pushRandomImage(t, newTestRegistry(t, nil))
+	storer, err := NewSimpleStorerFromConfig()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+		Artifact: ref,
+		Payload:  simple.NewSimpleStruct(ref),
+		Bundle:   &signing.Bundle{Content: []byte("{}"), Signature: []byte("signature")},
+	}); err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := storer.Delete(ctx, ref); err != nil {
+			t.Fatalf("Delete() #%d = %v", i+1, err)
+		}
+		se, err := ociremote.SignedEntity(ref)
+		if err != nil {
+			t.Fatalf("failed to get signed entity: %v", err)
+		}
+		sigs, err := se.Signatures()
+		if err != nil {
+			t.Fatalf("failed to get signatures: %v", err)
+		}
+		if layers, err := sigs.Get(); err != nil || len(layers) != 0 {
+			t.Errorf("found %d signatures (%v) after Delete() #%d, want none", len(layers), err, i+1)
+		}
+	}
+}
+
+func TestDelete_Unsupported(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	ref := pushRandomImage(t, newTestRegistry(t, func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodDelete {
+				http.Error(w, `{"errors":[{"code":"UNSUPPORTED","message":"deletes are disabled"}]}`, http.StatusMethodNotAllowed)
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	}))
+	storer, err := NewAttestationStorer()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	storeTestAttestations(t, storer, ref, "https://example.com/predicate")
+	if err := storer.Delete(ctx, ref); !errors.Is(err, ErrDeleteUnsupported) {
+		t.Errorf("Delete() = %v, want %v", err, ErrDeleteUnsupported)
+	}
+}
+
+func TestDelete_LocalLayout(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	ref, err := name.NewDigest("registry.invalid/test/img@sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855")
+	if err != nil {
+		t.Fatalf("failed to parse digest: %v", err)
+	}
+	storer, err := NewAttestationStorer(WithLocalLayout(filepath.Join(t.TempDir(), "layout")))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	storeTestAttestations(t, storer, ref, "https://example.com/first", "https://example.com/second")
+	if err := storer.DeleteByPredicateType(ctx, ref, "https://example.com/first"); err != nil {
+		t.Fatalf("DeleteByPredicateType() = %v", err)
+	}
+	if got := listedPredicateTypes(t, storer, ref); len(got) != 1 || got[0] != "https://example.com/second" {
+		t.Errorf("attestations left = %v, want only the second", got)
+	}
+	if err := storer.Delete(ctx, ref); err != nil {
+		t.Fatalf("Delete() = %v", err)
+	}
+	var notFound *NotFoundError
+	if _, err := storer.Retrieve(ctx, ref); !errors.As(err, &notFound) {
+		t.Errorf("Retrieve() after Delete() = %v, want a *NotFoundError", err)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestWithDryRun(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	var readOnly atomic.Bool
+	var writes atomic.Int32
+	registryName := newTestRegistry(t, func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Nothing may be written without push access.
+			if readOnly.Load() && r.Method != http.MethodGet && r.Method != http.MethodHead {
+				writes.Add(1)
+				http.Error(w, "denied", http.StatusUnauthorized)
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	})
+	ref := pushRandomImage(t, registryName)
+	statement, payload := newTestStatement(t, ref, "https://slsa.dev/provenance/v1")
+	attReq := &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  statement,
+		Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+	}
+	sigReq := &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+		Artifact: ref,
+		Payload:  simple.NewSimpleStruct(ref),
+		Bundle:   &signing.Bundle{Content: []byte("{}"), Signature: []byte("signature")},
+	}
+	store := func(dryRun bool) (att, sig *api.StoreResponse) {
+		t.Helper()
+		asOpts := []AttestationStorerOption{WithCreationTime(time.Unix(0, 0))}
+		ssOpts := []SimpleStorerOption{WithCreationTime(time.Unix(0, 0))}
+		if dryRun {
+			asOpts = append(asOpts, WithDryRun())
+			ssOpts = append(ssOpts, WithDryRun())
+		}
+		as, err := NewAttestationStorer(asOpts...)
+		if err != nil {
+			t.Fatalf("failed to create storer: %v", err)
+		}
+		if att, err = as.Store(ctx, attReq); err != nil {
+			t.Fatalf("error during attestation Store(): %v", err)
+		}
+		ss, err := NewSimpleStorerFromConfig(ssOpts...)
+		if err != nil {
+			t.Fatalf("failed to create storer: %v", err)
+		}
+		if sig, err = ss.Store(ctx, sigReq); err != nil {
+			t.Fatalf("error during signature Store(): %v", err)
+		}
+		return att, sig
+	}
+
+	readOnly.Store(true)
+	dryAtt, drySig := store(true)
+	if n := writes.Load(); n != 0 {
+		t.Errorf("dry run sent %d write requests, want none", n)
+	}
+	readOnly.Store(false)
+	att, sig := store(false)
+
+	if dryAtt.Reference != att.Reference || dryAtt.Digest != att.Digest {
+		t.Errorf("dry run attestation StoreResponse = %+v, want %+v", dryAtt, att)
+	}
+	if drySig.Reference != sig.Reference || drySig.Digest != sig.Digest {
+		t.Errorf("dry run signature StoreResponse = %+v, want %+v", drySig, sig)
+	}
+}
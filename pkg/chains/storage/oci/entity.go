@@ -0,0 +1,113 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"sync"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+)
+
+// createdAtEntity sets the created timestamp of the signature and attestation
+// manifests of the wrapped entity.
+type createdAtEntity struct {
+	oci.SignedEntity
+	created time.Time
+	// history, if set, also sets the timestamps of the history entries.
+	history bool
+}
+
+// Signatures implements oci.SignedEntity
+func (e *createdAtEntity) Signatures() (oci.Signatures, error) {
+	sigs, err := e.SignedEntity.Signatures()
+	if err != nil {
+		return nil, err
+	}
+	return withCreatedAt(sigs, e.created, e.history)
+}
+
+// Attestations implements oci.SignedEntity
+func (e *createdAtEntity) Attestations() (oci.Signatures, error) {
+	atts, err := e.SignedEntity.Attestations()
+	if err != nil {
+		return nil, err
+	}
+	return withCreatedAt(atts, e.created, e.history)
+}
+
+// createdAtSignatures overrides the config of the wrapped signatures image.
+type createdAtSignatures struct {
+	v1.Image
+	base oci.Signatures
+}
+
+// Get implements oci.Signatures
+func (s *createdAtSignatures) Get() ([]oci.Signature, error) {
+	return s.base.Get()
+}
+
+func withCreatedAt(sigs oci.Signatures, t time.Time, history bool) (oci.Signatures, error) {
+	cf, err := sigs.ConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	cfg := cf.DeepCopy()
+	cfg.Created = v1.Time{Time: t}
+	if history {
+		for i := range cfg.History {
+			cfg.History[i].Created = v1.Time{Time: t}
+		}
+	}
+	img, err := mutate.ConfigFile(sigs, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &createdAtSignatures{Image: img, base: sigs}, nil
+}
+
+// memoizedEntity returns the same signatures and attestations from every call,
+// so that what is written is also what is described in the StoreResponse.
+// Entities returned by the cosign mutate package otherwise re-read the
+// existing signatures from the registry on each call.
+type memoizedEntity struct {
+	oci.SignedEntity
+
+	sigsOnce sync.Once
+	sigs     oci.Signatures
+	sigsErr  error
+
+	attsOnce sync.Once
+	atts     oci.Signatures
+	attsErr  error
+}
+
+// Signatures implements oci.SignedEntity
+func (e *memoizedEntity) Signatures() (oci.Signatures, error) {
+	e.sigsOnce.Do(func() {
+		e.sigs, e.sigsErr = e.SignedEntity.Signatures()
+	})
+	return e.sigs, e.sigsErr
+}
+
+// Attestations implements oci.SignedEntity
+func (e *memoizedEntity) Attestations() (oci.Signatures, error) {
+	e.attsOnce.Do(func() {
+		e.atts, e.attsErr = e.SignedEntity.Attestations()
+	})
+	return e.atts, e.attsErr
+}
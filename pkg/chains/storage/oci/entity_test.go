@@ -0,0 +1,180 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// storeAt stores the same attestation and signature for img in a fresh
+// registry and returns the resulting .att and .sig manifests.
+func storeAt(t *testing.T, img v1.Image, opts ...Option) (v1.Image, v1.Image) {
+	t.Helper()
+	ctx := logtesting.TestContextWithLogger(t)
+	registryName := newTestRegistry(t, nil)
+	d, err := img.Digest()
+	if err != nil {
+		t.Fatalf("failed to get image digest: %v", err)
+	}
+	ref, err := name.NewDigest(fmt.Sprintf("%s/test/img@%s", registryName, d))
+	if err != nil {
+		t.Fatalf("failed to parse digest: %v", err)
+	}
+	if err := remote.Write(ref, img); err != nil {
+		t.Fatalf("failed to write image to mock registry: %v", err)
+	}
+
+	var attOpts []AttestationStorerOption
+	var sigOpts []SimpleStorerOption
+	for _, o := range opts {
+		attOpts = append(attOpts, o)
+		sigOpts = append(sigOpts, o)
+	}
+	attStorer, err := NewAttestationStorer(attOpts...)
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	if _, err := attStorer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  &intoto.Statement{},
+		Bundle:   &signing.Bundle{Signature: []byte("envelope")},
+	}); err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+	sigStorer, err := NewSimpleStorerFromConfig(sigOpts...)
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	if _, err := sigStorer.Store(ctx, &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+		Artifact: ref,
+		Payload:  simple.NewSimpleStruct(ref),
+		Bundle:   &signing.Bundle{Content: []byte("payload"), Signature: []byte("signature")},
+	}); err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+
+	tag := strings.ReplaceAll(d.String(), ":", "-")
+	att, err := remote.Image(ref.Context().Tag(tag + ".att"))
+	if err != nil {
+		t.Fatalf("failed to fetch attestations: %v", err)
+	}
+	sig, err := remote.Image(ref.Context().Tag(tag + ".sig"))
+	if err != nil {
+		t.Fatalf("failed to fetch signatures: %v", err)
+	}
+	return att, sig
+}
+
+func manifestDigest(t *testing.T, img v1.Image) v1.Hash {
+	t.Helper()
+	d, err := img.Digest()
+	if err != nil {
+		t.Fatalf("failed to get manifest digest: %v", err)
+	}
+	return d
+}
+
+func TestWithCreationTime(t *testing.T) {
+	img, err := random.Image(1024, 1)
+	if err != nil {
+		t.Fatalf("failed to create random image: %v", err)
+	}
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	att1, sig1 := storeAt(t, img, WithCreationTime(created))
+	att2, sig2 := storeAt(t, img, WithCreationTime(created))
+	if d1, d2 := manifestDigest(t, att1), manifestDigest(t, att2); d1 != d2 {
+		t.Errorf("attestation manifests differ for identical inputs: %s != %s", d1, d2)
+	}
+	if d1, d2 := manifestDigest(t, sig1), manifestDigest(t, sig2); d1 != d2 {
+		t.Errorf("signature manifests differ for identical inputs: %s != %s", d1, d2)
+	}
+
+	for _, m := range []v1.Image{att1, sig1} {
+		cfg, err := m.ConfigFile()
+		if err != nil {
+			t.Fatalf("failed to get config: %v", err)
+		}
+		if !cfg.Created.Time.Equal(created) {
+			t.Errorf("got created %s, want %s", cfg.Created.Time, created)
+		}
+	}
+
+	epoch, _ := storeAt(t, img, WithCreationTime(time.Unix(0, 0)))
+	if manifestDigest(t, epoch) == manifestDigest(t, att1) {
+		t.Error("expected a different creation time to produce a different manifest")
+	}
+}
+
+func layerDigests(t *testing.T, img v1.Image) []v1.Hash {
+	t.Helper()
+	layers, err := img.Layers()
+	if err != nil {
+		t.Fatalf("failed to get layers: %v", err)
+	}
+	var digests []v1.Hash
+	for _, l := range layers {
+		d, err := l.Digest()
+		if err != nil {
+			t.Fatalf("failed to get layer digest: %v", err)
+		}
+		digests = append(digests, d)
+	}
+	return digests
+}
+
+func TestWithReproducible(t *testing.T) {
+	img, err := random.Image(1024, 1)
+	if err != nil {
+		t.Fatalf("failed to create random image: %v", err)
+	}
+
+	att1, sig1 := storeAt(t, img, WithReproducible(true))
+	att2, sig2 := storeAt(t, img, WithReproducible(true))
+	for what, pair := range map[string][2]v1.Image{"attestation": {att1, att2}, "signature": {sig1, sig2}} {
+		if d1, d2 := manifestDigest(t, pair[0]), manifestDigest(t, pair[1]); d1 != d2 {
+			t.Errorf("%s manifests differ for identical inputs: %s != %s", what, d1, d2)
+		}
+		if l1, l2 := layerDigests(t, pair[0]), layerDigests(t, pair[1]); !slices.Equal(l1, l2) {
+			t.Errorf("%s layers differ for identical inputs: %v != %v", what, l1, l2)
+		}
+		cfg, err := pair[0].ConfigFile()
+		if err != nil {
+			t.Fatalf("failed to get config: %v", err)
+		}
+		if !cfg.Created.Time.Equal(time.Unix(0, 0)) {
+			t.Errorf("%s created = %s, want the epoch", what, cfg.Created.Time)
+		}
+		for i, h := range cfg.History {
+			if !h.Created.Time.Equal(time.Unix(0, 0)) {
+				t.Errorf("%s history %d created = %s, want the epoch", what, i, h.Created.Time)
+			}
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+)
+
+// entityCache memoizes for a short time, configured with WithEntityCache,
+// the lookups of the artifacts whose signatures and attestations are stored.
+// Only the outcome of the lookup is kept, not the entity: the entity is
+// rebuilt for each store, bound to its context, and reads the attached
+// signatures and attestations afresh, so that stores never attach to a stale
+// snapshot of them.
+type entityCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[entityCacheKey]time.Time
+	// nextSweep is when the expired entries are next removed.
+	nextSweep time.Time
+}
+
+// entityCacheKey identifies the entity of an artifact whose signatures and
+// attestations are stored in repo.
+type entityCacheKey struct {
+	artifact string
+	repo     string
+}
+
+func newEntityCache(ttl time.Duration) *entityCache {
+	return &entityCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[entityCacheKey]time.Time{},
+	}
+}
+
+// get reports whether the artifact of key was looked up within the ttl.
+func (c *entityCache) get(key entityCacheKey) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expires, ok := c.entries[key]
+	return ok && c.now().Before(expires)
+}
+
+// put records the lookup of the artifact of key, removing the expired entries
+// at most once per ttl.
+func (c *entityCache) put(key entityCacheKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if !now.Before(c.nextSweep) {
+		for k, expires := range c.entries {
+			if !now.Before(expires) {
+				delete(c.entries, k)
+			}
+		}
+		c.nextSweep = now.Add(c.ttl)
+	}
+	c.entries[key] = now.Add(c.ttl)
+}
+
+// keyedMutex serializes the stores of each artifact and repository, whose
+// read-modify-write of the .sig and .att manifests would otherwise drop the
+// signatures or attestations of one another. The zero value is ready to use.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[entityCacheKey]*keyedLock
+}
+
+type keyedLock struct {
+	// held holds a value while the lock is held.
+	held chan struct{}
+	// refs counts the holders and waiters of the lock.
+	refs int
+}
+
+// lock locks the mutex of key and returns the function unlocking it. It
+// returns the context error if ctx is done before the mutex is locked.
+func (m *keyedMutex) lock(ctx context.Context, key entityCacheKey) (func(), error) {
+	m.mu.Lock()
+	if m.locks == nil {
+		m.locks = map[entityCacheKey]*keyedLock{}
+	}
+	l, ok := m.locks[key]
+	if !ok {
+		l = &keyedLock{held: make(chan struct{}, 1)}
+		m.locks[key] = l
+	}
+	l.refs++
+	m.mu.Unlock()
+
+	release := func() {
+		m.mu.Lock()
+		if l.refs--; l.refs == 0 {
+			delete(m.locks, key)
+		}
+		m.mu.Unlock()
+	}
+	select {
+	case l.held <- struct{}{}:
+		return func() {
+			<-l.held
+			release()
+		}, nil
+	case <-ctx.Done():
+		release()
+		return nil, ctx.Err()
+	}
+}
+
+// lockEntity serializes the stores to the signatures and attestations of the
+// artifact stored in repo, returning the function ending the critical section.
+func (b *baseStorer) lockEntity(ctx context.Context, artifact name.Digest, repo name.Repository) (func(), error) {
+	return b.entityLocks.lock(ctx, entityCacheKey{artifact: artifact.String(), repo: repo.String()})
+}
+
+// lookupEntity returns the signed entity of the artifact to attach signatures
+// or attestations to, stored in repo. With WithEntityCache, the artifact is
+// fetched once per ttl: within it, the stores of the artifact attach to an
+// entity that only reads the signatures and attestations. Image indexes are
+// always fetched, as their children are read from the index.
+func (b *baseStorer) lookupEntity(ctx context.Context, artifact name.Digest, repo name.Repository) (se oci.SignedEntity, err error) {
+	ctx, span := startSpan(ctx, "oci.lookupEntity", artifactAttributes(artifact, repo)...)
+	defer func() { endSpan(span, err) }()
+
+	if b.localLayout != "" {
+		return &layoutEntity{path: b.localLayout, artifact: artifact, tagOpts: b.tagOptions(repo)}, nil
+	}
+	if b.entityCache == nil {
+		return b.signedEntity(ctx, artifact, b.tagOptions(repo)...)
+	}
+	key := entityCacheKey{artifact: artifact.String(), repo: repo.String()}
+	if b.entityCache.get(key) {
+		return ociremote.SignedUnknown(b.artifactReference(artifact), b.entityOptions(ctx, b.tagOptions(repo)...)...), nil
+	}
+	se, err = b.signedEntity(ctx, artifact, b.tagOptions(repo)...)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := se.(oci.SignedImageIndex); !ok {
+		b.entityCache.put(key)
+	}
+	return se, nil
+}
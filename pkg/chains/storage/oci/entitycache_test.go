@@ -0,0 +1,149 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestWithEntityCache(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	var fetches atomic.Int32
+	var digest atomic.Value
+	digest.Store("")
+	registryName := newTestRegistry(t, func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if d := digest.Load().(string); d != "" && strings.HasSuffix(r.URL.Path, "/manifests/"+d) {
+				fetches.Add(1)
+			}
+			h.ServeHTTP(w, r)
+		})
+	})
+	ref := pushRandomImage(t, registryName)
+	digest.Store(ref.DigestStr())
+
+	clock := &fakeClock{t: time.Now()}
+	storer, err := NewAttestationStorer(WithEntityCache(time.Minute), WithClock(clock))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	stored := 0
+	store := func() {
+		t.Helper()
+		statement, payload := newTestStatement(t, ref, fmt.Sprintf("https://example.com/predicate/%d", stored))
+		stored++
+		if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+			Artifact: ref,
+			Payload:  statement,
+			Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+		}); err != nil {
+			t.Fatalf("error during Store(): %v", err)
+		}
+	}
+	want := func(wantFetches int32) {
+		t.Helper()
+		if got := fetches.Load(); got != wantFetches {
+			t.Errorf("got %d fetches of the artifact, want %d", got, wantFetches)
+		}
+		// Every store attaches to the attestations written by the previous ones.
+		if got := countAttestationLayers(t, ref.Repository, ref); got != stored {
+			t.Errorf("got %d attestations, want %d", got, stored)
+		}
+	}
+
+	// Sequential stores fetch the artifact once.
+	for range 3 {
+		store()
+	}
+	want(1)
+
+	// Concurrent stores are serialized and none is lost.
+	var wg sync.WaitGroup
+	for i := range 5 {
+		wg.Add(1)
+		statement, payload := newTestStatement(t, ref, fmt.Sprintf("https://example.com/concurrent/%d", i))
+		go func() {
+			defer wg.Done()
+			if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+				Artifact: ref,
+				Payload:  statement,
+				Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+			}); err != nil {
+				t.Errorf("error during Store(): %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	stored += 5
+	want(1)
+
+	// The artifact is fetched again once the ttl elapsed.
+	clock.step(time.Minute)
+	store()
+	want(2)
+
+	if _, err := NewAttestationStorer(WithEntityCache(0)); err == nil {
+		t.Error("expected an error for a non-positive ttl")
+	}
+}
+
+func TestEntityCache_Sweep(t *testing.T) {
+	clock := &fakeClock{t: time.Now()}
+	c := newEntityCache(time.Minute)
+	c.now = clock.Now
+	for i := range 3 {
+		c.put(entityCacheKey{artifact: fmt.Sprint(i)})
+	}
+	clock.step(time.Minute)
+	c.put(entityCacheKey{artifact: "new"})
+	if len(c.entries) != 1 {
+		t.Errorf("got %d entries after the others expired, want 1", len(c.entries))
+	}
+	if !c.get(entityCacheKey{artifact: "new"}) || c.get(entityCacheKey{artifact: "0"}) {
+		t.Error("expected only the new entry to be cached")
+	}
+}
+
+func TestKeyedMutex_Cancel(t *testing.T) {
+	var m keyedMutex
+	key := entityCacheKey{artifact: "artifact"}
+	unlock, err := m.lock(context.Background(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := m.lock(ctx, key); !errors.Is(err, context.Canceled) {
+		t.Errorf("lock() = %v, want context.Canceled", err)
+	}
+	unlock()
+	if len(m.locks) != 0 {
+		t.Errorf("got %d locks after unlocking, want 0", len(m.locks))
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	"knative.dev/pkg/logging"
+)
+
+// envelopeIdentity identifies the content of a DSSE envelope independently of
+// its signatures.
+type envelopeIdentity struct {
+	payloadDigest [sha256.Size]byte
+	predicateType string
+}
+
+func identifyEnvelope(b []byte) (envelopeIdentity, bool) {
+	envelope := dsse.Envelope{}
+	if err := json.Unmarshal(b, &envelope); err != nil {
+		return envelopeIdentity{}, false
+	}
+	payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		return envelopeIdentity{}, false
+	}
+	id := envelopeIdentity{payloadDigest: sha256.Sum256(payload)}
+	if statement, err := decodeStatement(payload); err == nil {
+		id.predicateType = statement.GetPredicateType()
+	}
+	return id, true
+}
+
+// findExisting looks for an attestation attached to se with the same DSSE
+// payload and predicate type as envelope. Errors while reading the existing
+// attestations are logged and reported as not found, so that the store falls
+// through to a normal write.
+func (s *AttestationStorer) findExisting(ctx context.Context, se oci.SignedEntity, artifact name.Digest, repo name.Repository, envelope []byte) (*api.StoreResponse, bool) {
+	logger := logging.FromContext(ctx)
+	want, ok := identifyEnvelope(envelope)
+	if !ok {
+		return nil, false
+	}
+	atts, err := se.Attestations()
+	if err != nil {
+		logger.Warnf("Failed to read existing attestations for %s, storing anyway: %v", artifact.String(), err)
+		return nil, false
+	}
+	existing, err := atts.Get()
+	if err != nil {
+		logger.Warnf("Failed to read existing attestations for %s, storing anyway: %v", artifact.String(), err)
+		return nil, false
+	}
+	payloads, err := reassemblePayloads(existing)
+	if err != nil {
+		logger.Warnf("Failed to read existing attestations for %s, storing anyway: %v", artifact.String(), err)
+		return nil, false
+	}
+	for _, payload := range payloads {
+		if got, ok := identifyEnvelope(payload); !ok || got != want {
+			continue
+		}
+		resp, err := s.existingResponse(artifact, repo, atts)
+		if err != nil {
+			logger.Warnf("Failed to describe existing attestations for %s, storing anyway: %v", artifact.String(), err)
+			return nil, false
+		}
+		return resp, true
+	}
+	return nil, false
+}
+
+// existingResponse describes the existing attestations of the artifact, for
+// stores that are skipped.
+func (s *AttestationStorer) existingResponse(artifact name.Digest, repo name.Repository, atts oci.Signatures) (*api.StoreResponse, error) {
+	tag, err := ociremote.AttestationTag(artifact, s.tagOptions(repo)...)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := newStoreResponse(tag, atts)
+	if err != nil {
+		return nil, err
+	}
+	resp.AlreadyExists = true
+	return resp, nil
+}
@@ -0,0 +1,184 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/sigstore/cosign/v2/pkg/types"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	"google.golang.org/protobuf/encoding/protojson"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func newSignedEnvelope(t *testing.T, ref name.Digest, predicateType, sig string) []byte {
+	t.Helper()
+	payload, err := protojson.Marshal(&intoto.Statement{
+		Type:          intoto.StatementTypeUri,
+		Subject:       []*intoto.ResourceDescriptor{subjectFromDigest(ref)},
+		PredicateType: predicateType,
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal statement: %v", err)
+	}
+	b, err := json.Marshal(dsse.Envelope{
+		PayloadType: types.IntotoPayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures:  []dsse.Signature{{Sig: sig}},
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal envelope: %v", err)
+	}
+	return b
+}
+
+func countAttestationLayers(t *testing.T, repo name.Repository, ref name.Digest) int {
+	t.Helper()
+	img, err := remote.Image(repo.Tag(strings.ReplaceAll(ref.DigestStr(), ":", "-") + ".att"))
+	if err != nil {
+		t.Fatalf("failed to fetch attestations: %v", err)
+	}
+	layers, err := img.Layers()
+	if err != nil {
+		t.Fatalf("failed to get attestation layers: %v", err)
+	}
+	return len(layers)
+}
+
+func TestWithSkipIfExists(t *testing.T) {
+	const provenance = "https://slsa.dev/provenance/v1"
+	tests := []struct {
+		name       string
+		opts       []AttestationStorerOption
+		second     func(ref name.Digest) []byte
+		wantExists bool
+		wantLayers int
+	}{
+		{
+			name:       "identical attestation is skipped",
+			opts:       []AttestationStorerOption{WithSkipIfExists()},
+			second:     func(ref name.Digest) []byte { return newSignedEnvelope(t, ref, provenance, "c2ln") },
+			wantExists: true,
+			wantLayers: 1,
+		},
+		{
+			name:       "same payload with another signature is skipped",
+			opts:       []AttestationStorerOption{WithSkipIfExists()},
+			second:     func(ref name.Digest) []byte { return newSignedEnvelope(t, ref, provenance, "b3RoZXI=") },
+			wantExists: true,
+			wantLayers: 1,
+		},
+		{
+			name:       "different predicate type is stored",
+			opts:       []AttestationStorerOption{WithSkipIfExists()},
+			second:     func(ref name.Digest) []byte { return newSignedEnvelope(t, ref, "https://example.com/vsa", "c2ln") },
+			wantLayers: 2,
+		},
+		{
+			name:       "disabled",
+			second:     func(ref name.Digest) []byte { return newSignedEnvelope(t, ref, provenance, "c2ln") },
+			wantLayers: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := logtesting.TestContextWithLogger(t)
+			registryName := newTestRegistry(t, nil)
+			ref := pushRandomImage(t, registryName)
+			repo, err := name.NewRepository(registryName + "/attestations")
+			if err != nil {
+				t.Fatalf("failed to parse repository: %v", err)
+			}
+
+			storer, err := NewAttestationStorer(append([]AttestationStorerOption{WithTargetRepository(repo)}, tt.opts...)...)
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+			store := func(envelope []byte) *api.StoreResponse {
+				resp, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+					Artifact: ref,
+					Payload:  &intoto.Statement{},
+					Bundle:   &signing.Bundle{Signature: envelope},
+				})
+				if err != nil {
+					t.Fatalf("error during Store(): %v", err)
+				}
+				return resp
+			}
+
+			first := store(newSignedEnvelope(t, ref, provenance, "c2ln"))
+			if first.AlreadyExists {
+				t.Error("first store reported an existing attestation")
+			}
+			second := store(tt.second(ref))
+			if second.AlreadyExists != tt.wantExists {
+				t.Errorf("got AlreadyExists %v, want %v", second.AlreadyExists, tt.wantExists)
+			}
+			if tt.wantExists && second.Digest != first.Digest {
+				t.Errorf("got digest %s for the existing attestation, want %s", second.Digest, first.Digest)
+			}
+			if got := countAttestationLayers(t, repo, ref); got != tt.wantLayers {
+				t.Errorf("got %d attestations, want %d", got, tt.wantLayers)
+			}
+		})
+	}
+}
+
+func TestWithSkipIfExists_FetchErrorFallsThrough(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	var failed atomic.Bool
+	registryName := newTestRegistry(t, func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Fail the first read of the existing attestations.
+			if r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, ".att") && failed.CompareAndSwap(false, true) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	})
+	ref := pushRandomImage(t, registryName)
+
+	storer, err := NewAttestationStorer(WithSkipIfExists())
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	storer.remoteOpts = []remote.Option{remote.WithRetryBackoff(remote.Backoff{Steps: 1}), remote.WithRetryStatusCodes()}
+
+	resp, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  &intoto.Statement{},
+		Bundle:   &signing.Bundle{Signature: newSignedEnvelope(t, ref, "https://slsa.dev/provenance/v1", "c2ln")},
+	})
+	if err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+	if resp.AlreadyExists {
+		t.Error("got AlreadyExists after a failed lookup")
+	}
+	if got := countAttestationLayers(t, ref.Repository, ref); got != 1 {
+		t.Errorf("got %d attestations, want 1", got)
+	}
+}
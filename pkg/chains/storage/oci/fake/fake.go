@@ -0,0 +1,137 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fake provides in-memory storers standing in for the OCI storers in
+// tests, recording the requests they are given.
+package fake
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"sync"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	"github.com/tektoncd/chains/pkg/chains/storage/oci"
+)
+
+var (
+	_ api.Storer[name.Digest, *intoto.Statement]           = &AttestationStorer{}
+	_ api.Storer[name.Digest, simple.SimpleContainerImage] = &SimpleStorer{}
+)
+
+// Storer is an in-memory api.Storer for OCI artifacts that records the
+// requests it stores. It is safe for concurrent use.
+type Storer[Output any] struct {
+	mu sync.Mutex
+	// stored holds the stored requests by artifact.
+	stored map[string][]*api.StoreRequest[name.Digest, Output]
+	// err, if set, is returned by every store.
+	err error
+	// artifactErrs are returned by the stores of specific artifacts.
+	artifactErrs map[string]error
+}
+
+// AttestationStorer stands in for oci.AttestationStorer.
+type AttestationStorer = Storer[*intoto.Statement]
+
+// SimpleStorer stands in for oci.SimpleStorer.
+type SimpleStorer = Storer[simple.SimpleContainerImage]
+
+// NewAttestationStorer returns an empty fake attestation storer.
+func NewAttestationStorer() *AttestationStorer {
+	return &AttestationStorer{}
+}
+
+// NewSimpleStorer returns an empty fake signature storer.
+func NewSimpleStorer() *SimpleStorer {
+	return &SimpleStorer{}
+}
+
+// Store records req, unless an error was injected for it. The response
+// references the artifact, with the digest of the signature of the bundle.
+// Like the OCI storers, it returns oci.ErrMissingBundle for requests without a
+// bundle.
+func (s *Storer[Output]) Store(_ context.Context, req *api.StoreRequest[name.Digest, Output]) (*api.StoreResponse, error) {
+	if req.Bundle == nil {
+		return nil, oci.ErrMissingBundle
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := req.Artifact.String()
+	if err, ok := s.artifactErrs[key]; ok {
+		return nil, err
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	if s.stored == nil {
+		s.stored = map[string][]*api.StoreRequest[name.Digest, Output]{}
+	}
+	s.stored[key] = append(s.stored[key], req)
+
+	sum := sha256.Sum256(req.Bundle.Signature)
+	return &api.StoreResponse{
+		Reference: key,
+		Digest:    "sha256:" + hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+// Stored returns the stored requests, in the order they were stored, keyed
+// by the full reference of their artifact.
+func (s *Storer[Output]) Stored() map[string][]*api.StoreRequest[name.Digest, Output] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := make(map[string][]*api.StoreRequest[name.Digest, Output], len(s.stored))
+	for k, reqs := range s.stored {
+		stored[k] = slices.Clone(reqs)
+	}
+	return stored
+}
+
+// SetError makes every subsequent store fail with err, or succeed again if
+// err is nil. Errors set with SetArtifactError take precedence.
+func (s *Storer[Output]) SetError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+// SetArtifactError makes subsequent stores of the artifact fail with err, or
+// succeed again if err is nil.
+func (s *Storer[Output]) SetArtifactError(artifact name.Digest, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		delete(s.artifactErrs, artifact.String())
+		return
+	}
+	if s.artifactErrs == nil {
+		s.artifactErrs = map[string]error{}
+	}
+	s.artifactErrs[artifact.String()] = err
+}
+
+// Reset drops the stored requests and the injected errors.
+func (s *Storer[Output]) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.stored)
+	s.err = nil
+	clear(s.artifactErrs)
+}
@@ -0,0 +1,120 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	"github.com/tektoncd/chains/pkg/chains/storage/oci"
+)
+
+func testDigest(t *testing.T, i int) name.Digest {
+	t.Helper()
+	d, err := name.NewDigest(fmt.Sprintf("registry.example.com/test/img@sha256:%064x", i))
+	if err != nil {
+		t.Fatalf("failed to parse digest: %v", err)
+	}
+	return d
+}
+
+func TestAttestationStorer(t *testing.T) {
+	ctx := context.Background()
+	s := NewAttestationStorer()
+	refs := []name.Digest{testDigest(t, 0), testDigest(t, 1)}
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := &api.StoreRequest[name.Digest, *intoto.Statement]{
+				Artifact: refs[i%2],
+				Payload:  &intoto.Statement{PredicateType: fmt.Sprint(i)},
+				Bundle:   &signing.Bundle{Signature: []byte("envelope")},
+			}
+			if _, err := s.Store(ctx, req); err != nil {
+				t.Errorf("error during Store(): %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	stored := s.Stored()
+	if len(stored) != 2 {
+		t.Fatalf("Stored() has %d artifacts, want 2", len(stored))
+	}
+	for _, ref := range refs {
+		if got := len(stored[ref.String()]); got != 5 {
+			t.Errorf("Stored()[%s] has %d requests, want 5", ref, got)
+		}
+	}
+
+	s.Reset()
+	if got := s.Stored(); len(got) != 0 {
+		t.Errorf("Stored() after Reset() = %v, want nothing", got)
+	}
+}
+
+func TestSimpleStorer_Errors(t *testing.T) {
+	ctx := context.Background()
+	s := NewSimpleStorer()
+	errDenied := errors.New("denied")
+	errUnavailable := errors.New("unavailable")
+	store := func(i int) error {
+		t.Helper()
+		_, err := s.Store(ctx, &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+			Artifact: testDigest(t, i),
+			Bundle:   &signing.Bundle{Signature: []byte("signature")},
+		})
+		return err
+	}
+
+	s.SetArtifactError(testDigest(t, 1), errDenied)
+	if err := store(0); err != nil {
+		t.Errorf("Store() error = %v, want nil", err)
+	}
+	if err := store(1); !errors.Is(err, errDenied) {
+		t.Errorf("Store() error = %v, want %v", err, errDenied)
+	}
+	s.SetError(errUnavailable)
+	if err := store(0); !errors.Is(err, errUnavailable) {
+		t.Errorf("Store() error = %v, want %v", err, errUnavailable)
+	}
+	if err := store(1); !errors.Is(err, errDenied) {
+		t.Errorf("Store() error = %v, want %v", err, errDenied)
+	}
+	s.SetError(nil)
+	s.SetArtifactError(testDigest(t, 1), nil)
+	if err := store(1); err != nil {
+		t.Errorf("Store() error = %v, want nil", err)
+	}
+
+	if _, err := s.Store(ctx, &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{Artifact: testDigest(t, 0)}); !errors.Is(err, oci.ErrMissingBundle) {
+		t.Errorf("Store() error = %v, want %v", err, oci.ErrMissingBundle)
+	}
+
+	stored := s.Stored()
+	if len(stored) != 2 || len(stored[testDigest(t, 0).String()]) != 1 || len(stored[testDigest(t, 1).String()]) != 1 {
+		t.Errorf("Stored() = %v, want one request for each artifact", stored)
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+	stderrors "errors"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/pkg/errors"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+)
+
+// storeChildren stores the attestation of req against every image and index
+// referenced by idx, the signed entity of the artifact of req. All children
+// are attempted, and their failures are aggregated in the returned error.
+func (s *AttestationStorer) storeChildren(ctx context.Context, idx oci.SignedImageIndex, req *api.StoreRequest[name.Digest, *intoto.Statement], signOpts ...mutate.SignOption) error {
+	m, err := idx.IndexManifest()
+	if err != nil {
+		return errors.Wrapf(err, "reading the index manifest of %s", req.Artifact.String())
+	}
+	var errs []error
+	for _, desc := range m.Manifests {
+		if !desc.MediaType.IsImage() && !desc.MediaType.IsIndex() {
+			continue
+		}
+		child := req.Artifact.Context().Digest(desc.Digest.String())
+		childReq := *req
+		childReq.Artifact = child
+		if _, err := s.store(ctx, &childReq, signOpts...); err != nil {
+			errs = append(errs, errors.Wrapf(err, "storing attestation for child %s", child.String()))
+		}
+	}
+	return stderrors.Join(errs...)
+}
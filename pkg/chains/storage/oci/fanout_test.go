@@ -0,0 +1,127 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// pushRandomIndex writes a random image index to the registry and returns
+// its digest and the digests of its children.
+func pushRandomIndex(t *testing.T, registryName string, children int64) (name.Digest, []name.Digest) {
+	t.Helper()
+	idx, err := random.Index(1024, 1, children)
+	if err != nil {
+		t.Fatalf("failed to create random index: %v", err)
+	}
+	idxDigest, err := idx.Digest()
+	if err != nil {
+		t.Fatalf("failed to get index digest: %v", err)
+	}
+	ref, err := name.NewDigest(fmt.Sprintf("%s/test/img@%s", registryName, idxDigest))
+	if err != nil {
+		t.Fatalf("failed to parse digest: %v", err)
+	}
+	if err := remote.WriteIndex(ref, idx); err != nil {
+		t.Fatalf("failed to write index to mock registry: %v", err)
+	}
+	m, err := idx.IndexManifest()
+	if err != nil {
+		t.Fatalf("failed to read index manifest: %v", err)
+	}
+	var refs []name.Digest
+	for _, desc := range m.Manifests {
+		refs = append(refs, ref.Context().Digest(desc.Digest.String()))
+	}
+	return ref, refs
+}
+
+func TestWithFanoutToChildren(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	ref, children := pushRandomIndex(t, newTestRegistry(t, nil), 3)
+
+	storer, err := NewAttestationStorer(WithFanoutToChildren())
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	statement, payload := newTestStatement(t, ref, "https://slsa.dev/provenance/v1")
+	if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  statement,
+		Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+	}); err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+
+	for _, d := range append([]name.Digest{ref}, children...) {
+		if got := countAttestationLayers(t, ref.Context(), d); got != 1 {
+			t.Errorf("attestation layers of %s = %d, want 1", d, got)
+		}
+	}
+}
+
+func TestWithFanoutToChildren_Errors(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	var failTag atomic.Value
+	failTag.Store("")
+	registryName := newTestRegistry(t, func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if tag := failTag.Load().(string); tag != "" && r.Method == http.MethodPut && strings.HasSuffix(r.URL.Path, "/manifests/"+tag) {
+				http.Error(w, "denied", http.StatusForbidden)
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	})
+	ref, children := pushRandomIndex(t, registryName, 3)
+	failing := children[1]
+	failTag.Store(strings.ReplaceAll(failing.DigestStr(), ":", "-") + ".att")
+
+	storer, err := NewAttestationStorer(WithFanoutToChildren())
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	statement, payload := newTestStatement(t, ref, "https://slsa.dev/provenance/v1")
+	resp, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  statement,
+		Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+	})
+	if err == nil || !strings.Contains(err.Error(), failing.String()) {
+		t.Fatalf("Store() error = %v, want an error for %s", err, failing)
+	}
+	if resp == nil {
+		t.Error("Store() should describe the attestations stored for the index")
+	}
+
+	// The other children are still attempted.
+	for _, d := range []name.Digest{ref, children[0], children[2]} {
+		if got := countAttestationLayers(t, ref.Context(), d); got != 1 {
+			t.Errorf("attestation layers of %s = %d, want 1", d, got)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestStore_ForeignLayerSubject(t *testing.T) {
+	var blobReads atomic.Int32
+	registryName := newTestRegistry(t, func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet && strings.Contains(r.URL.Path, "/blobs/") {
+				blobReads.Add(1)
+			}
+			h.ServeHTTP(w, r)
+		})
+	})
+
+	// An image whose foreign layer is only available from an unreachable URL,
+	// so it is never pushed to the registry.
+	foreign, err := random.Layer(1024, types.DockerForeignLayer)
+	if err != nil {
+		t.Fatalf("failed to create layer: %v", err)
+	}
+	local, err := random.Layer(1024, types.DockerLayer)
+	if err != nil {
+		t.Fatalf("failed to create layer: %v", err)
+	}
+	img, err := mutate.Append(mutate.MediaType(empty.Image, types.DockerManifestSchema2),
+		mutate.Addendum{Layer: foreign, MediaType: types.DockerForeignLayer, URLs: []string{"https://foreign.invalid/layer"}},
+		mutate.Addendum{Layer: local},
+	)
+	if err != nil {
+		t.Fatalf("failed to create image: %v", err)
+	}
+	imgDigest, err := img.Digest()
+	if err != nil {
+		t.Fatalf("failed to get image digest: %v", err)
+	}
+	ref, err := name.NewDigest(fmt.Sprintf("%s/test/foreign@%s", registryName, imgDigest))
+	if err != nil {
+		t.Fatalf("failed to parse digest: %v", err)
+	}
+	if err := remote.Write(ref, img); err != nil {
+		t.Fatalf("failed to write image to mock registry: %v", err)
+	}
+	fd, err := foreign.Digest()
+	if err != nil {
+		t.Fatalf("failed to get layer digest: %v", err)
+	}
+	resp, err := http.Head(fmt.Sprintf("http://%s/v2/test/foreign/blobs/%s", registryName, fd))
+	if err != nil {
+		t.Fatalf("failed to check for the foreign layer: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected the foreign layer not to be pushed, got status %d", resp.StatusCode)
+	}
+	blobReads.Store(0)
+
+	ctx := logtesting.TestContextWithLogger(t)
+	as, err := NewAttestationStorer()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	if _, err := as.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  &intoto.Statement{},
+		Bundle:   &signing.Bundle{},
+	}); err != nil {
+		t.Errorf("error storing attestation: %v", err)
+	}
+	ss, err := NewSimpleStorerFromConfig()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	if _, err := ss.Store(ctx, &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+		Artifact: ref,
+		Payload:  simple.NewSimpleStruct(ref),
+		Bundle:   &signing.Bundle{},
+	}); err != nil {
+		t.Errorf("error storing signature: %v", err)
+	}
+	if got := blobReads.Load(); got != 0 {
+		t.Errorf("got %d blob reads, want the subject layers never to be read", got)
+	}
+}
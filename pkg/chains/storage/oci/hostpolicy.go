@@ -0,0 +1,34 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+// HostPolicy configures how subjects on a given registry host are stored.
+type HostPolicy struct {
+	// Options are applied on top of the storer's own options for subjects on the host.
+	Options []Option
+}
+
+// withoutHostPolicies returns the options other than host policies, so that
+// the storers derived for each host do not derive storers of their own.
+func withoutHostPolicies[T any](opts []T) []T {
+	out := make([]T, 0, len(opts))
+	for _, o := range opts {
+		if _, ok := any(o).(*hostPolicyOption); ok {
+			continue
+		}
+		out = append(out, o)
+	}
+	return out
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestWithHostPolicy(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	policyHost := newTestRegistry(t, nil)
+	defaultHost := newTestRegistry(t, nil)
+	policyRef := pushRandomImage(t, policyHost)
+	defaultRef := pushRandomImage(t, defaultHost)
+
+	policyRepo, err := name.NewRepository(policyHost + "/policy/attestations")
+	if err != nil {
+		t.Fatalf("failed to parse repository: %v", err)
+	}
+	defaultRepo, err := name.NewRepository(defaultHost + "/default/attestations")
+	if err != nil {
+		t.Fatalf("failed to parse repository: %v", err)
+	}
+	opts := []Option{
+		WithTargetRepository(defaultRepo),
+		WithHostPolicy(map[string]HostPolicy{
+			policyHost: {Options: []Option{WithTargetRepository(policyRepo)}},
+		}),
+	}
+	var attOpts []AttestationStorerOption
+	var sigOpts []SimpleStorerOption
+	for _, o := range opts {
+		attOpts = append(attOpts, o)
+		sigOpts = append(sigOpts, o)
+	}
+	attStorer, err := NewAttestationStorer(attOpts...)
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	sigStorer, err := NewSimpleStorerFromConfig(sigOpts...)
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+
+	for _, tt := range []struct {
+		ref  name.Digest
+		want name.Repository
+	}{
+		{ref: policyRef, want: policyRepo},
+		{ref: defaultRef, want: defaultRepo},
+	} {
+		if _, err := attStorer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+			Artifact: tt.ref,
+			Payload:  &intoto.Statement{},
+			Bundle:   &signing.Bundle{},
+		}); err != nil {
+			t.Fatalf("error during Store(): %v", err)
+		}
+		if _, err := sigStorer.Store(ctx, &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+			Artifact: tt.ref,
+			Payload:  simple.NewSimpleStruct(tt.ref),
+			Bundle:   &signing.Bundle{},
+		}); err != nil {
+			t.Fatalf("error during Store(): %v", err)
+		}
+
+		tag := strings.ReplaceAll(tt.ref.DigestStr(), ":", "-")
+		for _, suffix := range []string{".att", ".sig"} {
+			if _, err := remote.Head(tt.want.Tag(tag + suffix)); err != nil {
+				t.Errorf("expected %s of %s in %s: %v", suffix, tt.ref, tt.want, err)
+			}
+		}
+	}
+}
+
+func TestWithHostPolicy_InvalidHost(t *testing.T) {
+	if _, err := NewAttestationStorer(WithHostPolicy(map[string]HostPolicy{"not a host": {}})); err == nil {
+		t.Error("expected an error for an invalid host")
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	"knative.dev/pkg/logging"
+)
+
+// idempotencyKeyAnnotation holds the idempotency key configured with
+// WithIdempotencyKey.
+const idempotencyKeyAnnotation = "dev.tekton.chains/idempotency-key"
+
+// findIdempotent looks for an attestation attached to se that was stored with
+// the configured idempotency key. Errors while reading the existing
+// attestations are logged and reported as not found, so that the store falls
+// through to a normal write.
+func (s *AttestationStorer) findIdempotent(ctx context.Context, se oci.SignedEntity, artifact name.Digest, repo name.Repository) (*api.StoreResponse, bool) {
+	logger := logging.FromContext(ctx)
+	atts, err := se.Attestations()
+	if err != nil {
+		logger.Warnf("Failed to read existing attestations for %s, storing anyway: %v", artifact.String(), err)
+		return nil, false
+	}
+	existing, err := atts.Get()
+	if err != nil {
+		logger.Warnf("Failed to read existing attestations for %s, storing anyway: %v", artifact.String(), err)
+		return nil, false
+	}
+	for _, att := range existing {
+		ann, err := att.Annotations()
+		if err != nil {
+			logger.Warnf("Failed to read existing attestations for %s, storing anyway: %v", artifact.String(), err)
+			return nil, false
+		}
+		if ann[idempotencyKeyAnnotation] != s.idempotencyKey {
+			continue
+		}
+		resp, err := s.existingResponse(artifact, repo, atts)
+		if err != nil {
+			logger.Warnf("Failed to describe existing attestations for %s, storing anyway: %v", artifact.String(), err)
+			return nil, false
+		}
+		return resp, true
+	}
+	return nil, false
+}
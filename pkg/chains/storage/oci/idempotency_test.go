@@ -0,0 +1,91 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestWithIdempotencyKey(t *testing.T) {
+	const provenance = "https://slsa.dev/provenance/v1"
+	tests := []struct {
+		name       string
+		secondKey  string
+		wantExists bool
+		wantLayers int
+	}{
+		{
+			name:       "same key is skipped",
+			secondKey:  "taskrun-uid/1",
+			wantExists: true,
+			wantLayers: 1,
+		},
+		{
+			name:       "different key is stored",
+			secondKey:  "taskrun-uid/2",
+			wantLayers: 2,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := logtesting.TestContextWithLogger(t)
+			ref := pushRandomImage(t, newTestRegistry(t, nil))
+			store := func(key, sig string) *api.StoreResponse {
+				t.Helper()
+				storer, err := NewAttestationStorer(WithIdempotencyKey(key))
+				if err != nil {
+					t.Fatalf("failed to create storer: %v", err)
+				}
+				resp, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+					Artifact: ref,
+					Payload:  &intoto.Statement{},
+					Bundle:   &signing.Bundle{Signature: newSignedEnvelope(t, ref, provenance, sig)},
+				})
+				if err != nil {
+					t.Fatalf("error during Store(): %v", err)
+				}
+				return resp
+			}
+
+			if first := store("taskrun-uid/1", "c2lnLTE="); first.AlreadyExists {
+				t.Error("first Store() reported AlreadyExists")
+			}
+			// The retried store carries a different signature, so only the
+			// key identifies it as a duplicate.
+			second := store(tc.secondKey, "c2lnLTI=")
+			if second.AlreadyExists != tc.wantExists {
+				t.Errorf("second Store() AlreadyExists = %v, want %v", second.AlreadyExists, tc.wantExists)
+			}
+			if tc.wantExists && second.Digest == "" {
+				t.Error("skipped Store() should describe the existing attestations")
+			}
+			if got := countAttestationLayers(t, ref.Context(), ref); got != tc.wantLayers {
+				t.Errorf("attestation layers = %d, want %d", got, tc.wantLayers)
+			}
+		})
+	}
+}
+
+func TestWithIdempotencyKey_Empty(t *testing.T) {
+	if _, err := NewAttestationStorer(WithIdempotencyKey("")); err == nil {
+		t.Error("expected an error for an empty idempotency key")
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// newAliasedRegistry starts a plain HTTP test registry and returns an image
+// pushed to it, referenced through an alias host that ggcr does not treat as
+// local, and a transport that routes the alias to the registry.
+func newAliasedRegistry(t *testing.T) (name.Digest, http.RoundTripper) {
+	t.Helper()
+	registryName := newTestRegistry(t, nil)
+	pushed := pushRandomImage(t, registryName)
+
+	_, port, err := net.SplitHostPort(registryName)
+	if err != nil {
+		t.Fatalf("failed to split registry host: %v", err)
+	}
+	ref, err := name.NewDigest(strings.Replace(pushed.String(), registryName, "registry.test:"+port, 1))
+	if err != nil {
+		t.Fatalf("failed to parse digest: %v", err)
+	}
+
+	rt := http.DefaultTransport.(*http.Transport).Clone()
+	rt.DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, registryName)
+	}
+	return ref, rt
+}
+
+func TestWithInsecure(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	ref, rt := newAliasedRegistry(t)
+
+	attReq := &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  &intoto.Statement{},
+		Bundle:   &signing.Bundle{},
+	}
+	sigReq := &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+		Artifact: ref,
+		Payload:  simple.NewSimpleStruct(ref),
+		Bundle:   &signing.Bundle{},
+	}
+
+	t.Run("not allowlisted", func(t *testing.T) {
+		as, err := NewAttestationStorer(WithTransport(rt), WithInsecure("other.test"))
+		if err != nil {
+			t.Fatalf("failed to create storer: %v", err)
+		}
+		if _, err := as.Store(ctx, attReq); err == nil {
+			t.Error("expected the store to fail over HTTPS")
+		}
+	})
+
+	t.Run("allowlisted", func(t *testing.T) {
+		as, err := NewAttestationStorer(WithTransport(rt), WithInsecure(ref.RegistryStr()))
+		if err != nil {
+			t.Fatalf("failed to create storer: %v", err)
+		}
+		if _, err := as.Store(ctx, attReq); err != nil {
+			t.Errorf("error storing attestation: %v", err)
+		}
+		ss, err := NewSimpleStorerFromConfig(WithTransport(rt), WithInsecure(ref.RegistryStr()))
+		if err != nil {
+			t.Fatalf("failed to create storer: %v", err)
+		}
+		if _, err := ss.Store(ctx, sigReq); err != nil {
+			t.Errorf("error storing signature: %v", err)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if _, err := NewAttestationStorer(WithInsecure()); err == nil {
+			t.Error("expected an error without registries")
+		}
+		if _, err := NewAttestationStorer(WithInsecure("not a host")); err == nil {
+			t.Error("expected an error for an invalid registry")
+		}
+	})
+}
@@ -0,0 +1,48 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/empty"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+)
+
+// latestPointerSuffix is appended to the .att tag of a subject to name the tag
+// maintained with WithMaintainLatestPointer.
+const latestPointerSuffix = ".latest"
+
+// latestPointerTag returns the tag pointing at the newest attestation stored
+// under the .att tag attTag.
+func latestPointerTag(attTag name.Tag) name.Tag {
+	return attTag.Context().Tag(attTag.TagStr() + latestPointerSuffix)
+}
+
+// writeLatestPointer points the latest pointer tag of attTag at a manifest
+// holding only the layers of the attestation just stored.
+func (s *AttestationStorer) writeLatestPointer(ctx context.Context, attTag name.Tag, layers []oci.Signature) error {
+	latest, err := mutate.AppendSignatures(empty.Signatures(), false, layers...)
+	if err != nil {
+		return err
+	}
+	tag := latestPointerTag(attTag)
+	return s.retryWrite(ctx, "latest attestation pointer "+tag.String(), func() error {
+		return remote.Write(tag, latest, s.remoteOptions(ctx)...)
+	})
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	intoto "github.com/in-toto/attestation/go/v1"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestWithMaintainLatestPointer(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	ref := pushRandomImage(t, newTestRegistry(t, nil))
+	storer, err := NewAttestationStorer(WithMaintainLatestPointer(true))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	attTag, err := ociremote.AttestationTag(ref)
+	if err != nil {
+		t.Fatalf("failed to get the attestation tag: %v", err)
+	}
+	latest := latestPointerTag(attTag)
+
+	for _, pt := range []string{"https://example.com/first", "https://example.com/second"} {
+		statement, payload := newTestStatement(t, ref, pt)
+		envelope := newTestEnvelope(t, payload)
+		if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+			Artifact: ref,
+			Payload:  statement,
+			Bundle:   &signing.Bundle{Signature: envelope},
+		}); err != nil {
+			t.Fatalf("error during Store(): %v", err)
+		}
+
+		img, err := remote.Image(latest)
+		if err != nil {
+			t.Fatalf("failed to fetch %s: %v", latest, err)
+		}
+		layers, err := img.Layers()
+		if err != nil || len(layers) != 1 {
+			t.Fatalf("%s has %d layers (%v), want 1", latest, len(layers), err)
+		}
+		rc, err := layers[0].Uncompressed()
+		if err != nil {
+			t.Fatalf("failed to open the layer: %v", err)
+		}
+		got, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("failed to read the layer: %v", err)
+		}
+		if !bytes.Equal(got, envelope) {
+			t.Errorf("%s points at %s, want the envelope of %s", latest, got, pt)
+		}
+	}
+	if n := countAttestationLayers(t, ref.Context(), ref); n != 2 {
+		t.Errorf("found %d attestations, want both stores", n)
+	}
+}
+
+func TestWithMaintainLatestPointer_Disabled(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	ref := pushRandomImage(t, newTestRegistry(t, nil))
+	storer, err := NewAttestationStorer(WithMaintainLatestPointer(false))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	statement, payload := newTestStatement(t, ref, "https://example.com/predicate")
+	if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  statement,
+		Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+	}); err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+	attTag, err := ociremote.AttestationTag(ref)
+	if err != nil {
+		t.Fatalf("failed to get the attestation tag: %v", err)
+	}
+	if _, err := remote.Head(latestPointerTag(attTag)); err == nil {
+		t.Error("expected no latest pointer tag")
+	}
+}
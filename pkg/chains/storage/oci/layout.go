@@ -0,0 +1,175 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+	"os"
+	"sync"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/match"
+	"github.com/pkg/errors"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	cosignempty "github.com/sigstore/cosign/v2/pkg/oci/empty"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci/signature"
+)
+
+// refNameAnnotation names the manifests of an OCI layout index. The
+// signature and attestation manifests written with WithLocalLayout carry the
+// full reference of their .sig or .att tag, so that they can be pushed there
+// once the registry is reachable.
+const refNameAnnotation = "org.opencontainers.image.ref.name"
+
+// maxLayoutLayers bounds the layers read from a signature or attestation
+// manifest of a layout, like cosign does for remote manifests.
+const maxLayoutLayers = 1000
+
+// layoutMu serializes the updates of layout indexes, which are read, modified
+// and rewritten as a whole.
+var layoutMu sync.Mutex
+
+// layoutEntity is the signed entity of an artifact whose signatures and
+// attestations are kept in an OCI layout rather than in a registry. The
+// artifact itself need not be in the layout.
+type layoutEntity struct {
+	path     string
+	artifact name.Digest
+	// tagOpts name the .sig and .att manifests of the artifact.
+	tagOpts []ociremote.Option
+}
+
+var _ oci.SignedEntity = (*layoutEntity)(nil)
+
+// Digest implements oci.SignedEntity.
+func (e *layoutEntity) Digest() (v1.Hash, error) {
+	return v1.NewHash(e.artifact.DigestStr())
+}
+
+// Signatures implements oci.SignedEntity.
+func (e *layoutEntity) Signatures() (oci.Signatures, error) {
+	tag, err := ociremote.SignatureTag(e.artifact, e.tagOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return readLayoutSignatures(e.path, tag)
+}
+
+// Attestations implements oci.SignedEntity.
+func (e *layoutEntity) Attestations() (oci.Signatures, error) {
+	tag, err := ociremote.AttestationTag(e.artifact, e.tagOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return readLayoutSignatures(e.path, tag)
+}
+
+// Attachment implements oci.SignedEntity.
+func (e *layoutEntity) Attachment(attName string) (oci.File, error) {
+	return nil, errors.Errorf("attachment %q is not supported in OCI layouts", attName)
+}
+
+// storedEntity returns the signed entity of the artifact stored in repo, read
+// from the OCI layout configured with WithLocalLayout or from the registry.
+// Unlike lookupEntity, it is never cached.
+func (b *baseStorer) storedEntity(ctx context.Context, artifact name.Digest, repo name.Repository) (oci.SignedEntity, error) {
+	if b.localLayout != "" {
+		return &layoutEntity{path: b.localLayout, artifact: artifact, tagOpts: b.tagOptions(repo)}, nil
+	}
+	return b.signedEntity(ctx, artifact, b.tagOptions(repo)...)
+}
+
+// readLayoutSignatures returns the signatures of the manifest named tag in
+// the layout at path, or no signatures if there is none.
+func readLayoutSignatures(path string, tag name.Tag) (oci.Signatures, error) {
+	p, err := layout.FromPath(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cosignempty.Signatures(), nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "opening OCI layout %s", path)
+	}
+	idx, err := p.ImageIndex()
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading OCI layout %s", path)
+	}
+	m, err := idx.IndexManifest()
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading OCI layout %s", path)
+	}
+	for _, desc := range m.Manifests {
+		if desc.Annotations[refNameAnnotation] != tag.Name() {
+			continue
+		}
+		img, err := idx.Image(desc.Digest)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading %s from OCI layout %s", tag.Name(), path)
+		}
+		return &layoutSignatures{Image: img}, nil
+	}
+	return cosignempty.Signatures(), nil
+}
+
+// writeLayout writes sigs to the layout at path, creating it if needed, as
+// the manifest named tag, replacing the previous one.
+func writeLayout(path string, tag name.Tag, sigs oci.Signatures) error {
+	layoutMu.Lock()
+	defer layoutMu.Unlock()
+
+	p, err := layout.FromPath(path)
+	if errors.Is(err, os.ErrNotExist) {
+		p, err = layout.Write(path, empty.Index)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "opening OCI layout %s", path)
+	}
+	if err := p.ReplaceImage(sigs, match.Annotation(refNameAnnotation, tag.Name()), layout.WithAnnotations(map[string]string{
+		refNameAnnotation: tag.Name(),
+	})); err != nil {
+		return errors.Wrapf(err, "writing %s to OCI layout %s", tag.Name(), path)
+	}
+	return nil
+}
+
+// layoutSignatures are the signatures or attestations of a manifest read from
+// an OCI layout.
+type layoutSignatures struct {
+	v1.Image
+}
+
+var _ oci.Signatures = (*layoutSignatures)(nil)
+
+// Get implements oci.Signatures.
+func (s *layoutSignatures) Get() ([]oci.Signature, error) {
+	m, err := s.Manifest()
+	if err != nil {
+		return nil, err
+	}
+	if n := int64(len(m.Layers)); n > maxLayoutLayers {
+		return nil, oci.NewMaxLayersExceeded(n, maxLayoutLayers)
+	}
+	sigs := make([]oci.Signature, 0, len(m.Layers))
+	for _, desc := range m.Layers {
+		l, err := s.LayerByDigest(desc.Digest)
+		if err != nil {
+			return nil, err
+		}
+		sigs = append(sigs, signature.New(l, desc))
+	}
+	return sigs, nil
+}
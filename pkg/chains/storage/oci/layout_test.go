@@ -0,0 +1,171 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	intoto "github.com/in-toto/attestation/go/v1"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestWithLocalLayout(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	path := filepath.Join(t.TempDir(), "layout")
+	// The registry of the subject is never contacted.
+	ref, err := name.NewDigest("registry.invalid/test/img@sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855")
+	if err != nil {
+		t.Fatalf("failed to parse digest: %v", err)
+	}
+
+	as, err := NewAttestationStorer(WithLocalLayout(path))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	var envelopes [][]byte
+	for _, pt := range []string{"https://example.com/first", "https://example.com/second"} {
+		statement, payload := newTestStatement(t, ref, pt)
+		envelope := newTestEnvelope(t, payload)
+		envelopes = append(envelopes, envelope)
+		if _, err := as.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+			Artifact: ref,
+			Payload:  statement,
+			Bundle:   &signing.Bundle{Signature: envelope},
+		}); err != nil {
+			t.Fatalf("error during Store(): %v", err)
+		}
+	}
+	ss, err := NewSimpleStorerFromConfig(WithLocalLayout(path))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	if _, err := ss.Store(ctx, &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+		Artifact: ref,
+		Payload:  simple.NewSimpleStruct(ref),
+		Bundle:   &signing.Bundle{Content: []byte("{}"), Signature: []byte("signature")},
+	}); err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+
+	statements, err := as.Retrieve(ctx, ref)
+	if err != nil {
+		t.Fatalf("error during Retrieve(): %v", err)
+	}
+	if len(statements) != 2 || statements[0].GetPredicateType() != "https://example.com/first" || statements[1].GetPredicateType() != "https://example.com/second" {
+		t.Errorf("Retrieve() = %v, want both statements in order", statements)
+	}
+
+	// The layout holds one manifest per tag, which can be pushed as is.
+	p, err := layout.FromPath(path)
+	if err != nil {
+		t.Fatalf("failed to open the layout: %v", err)
+	}
+	idx, err := p.ImageIndex()
+	if err != nil {
+		t.Fatalf("failed to read the layout: %v", err)
+	}
+	m, err := idx.IndexManifest()
+	if err != nil {
+		t.Fatalf("failed to read the layout: %v", err)
+	}
+	if len(m.Manifests) != 2 {
+		t.Fatalf("layout has %d manifests, want the .att and .sig manifests", len(m.Manifests))
+	}
+
+	reg := newTestRegistry(t, nil)
+	target, err := name.NewDigest(reg + "/test/img@" + ref.DigestStr())
+	if err != nil {
+		t.Fatalf("failed to parse digest: %v", err)
+	}
+	for _, desc := range m.Manifests {
+		tag, err := name.NewTag(desc.Annotations[refNameAnnotation])
+		if err != nil {
+			t.Fatalf("failed to parse the manifest name: %v", err)
+		}
+		if tag.Context() != ref.Context() {
+			t.Errorf("manifest %s is not named in the subject repository", tag.Name())
+		}
+		img, err := idx.Image(desc.Digest)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", tag.Name(), err)
+		}
+		if err := remote.Write(target.Context().Tag(tag.TagStr()), img); err != nil {
+			t.Fatalf("failed to push %s: %v", tag.Name(), err)
+		}
+	}
+	se := ociremote.SignedUnknown(target)
+	atts, err := se.Attestations()
+	if err != nil {
+		t.Fatalf("failed to get attestations: %v", err)
+	}
+	layers, err := atts.Get()
+	if err != nil || len(layers) != 2 {
+		t.Fatalf("found %d pushed attestations (%v), want 2", len(layers), err)
+	}
+	for i, l := range layers {
+		got, err := l.Payload()
+		if err != nil {
+			t.Fatalf("failed to read attestation %d: %v", i, err)
+		}
+		if string(got) != string(envelopes[i]) {
+			t.Errorf("attestation %d = %s, want %s", i, got, envelopes[i])
+		}
+	}
+	sigs, err := se.Signatures()
+	if err != nil {
+		t.Fatalf("failed to get signatures: %v", err)
+	}
+	if layers, err := sigs.Get(); err != nil || len(layers) != 1 {
+		t.Errorf("found %d pushed signatures (%v), want 1", len(layers), err)
+	}
+}
+
+func TestWithLocalLayout_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	for name, opts := range map[string][]AttestationStorerOption{
+		"empty path":         {WithLocalLayout("")},
+		"verify annotations": {WithLocalLayout(dir), WithVerifyAnnotations(true)},
+		"latest pointer":     {WithLocalLayout(dir), WithMaintainLatestPointer(true)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewAttestationStorer(opts...); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestWithLocalLayout_RetrieveByArtifactType(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	s, err := NewAttestationStorer(WithLocalLayout(t.TempDir()))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	ref, err := name.NewDigest("registry.invalid/test/img@sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855")
+	if err != nil {
+		t.Fatalf("failed to parse digest: %v", err)
+	}
+	if _, err := s.RetrieveByArtifactType(ctx, ref, "application/vnd.in-toto+json"); err == nil {
+		t.Error("expected an error retrieving referrers from a layout")
+	}
+}
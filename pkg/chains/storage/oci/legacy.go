@@ -119,7 +119,7 @@ func (b *Backend) uploadSignature(ctx context.Context, format simple.SimpleConta
 	imageName := format.ImageName()
 	logger.Infof("Uploading %s signature", imageName)
 
-	ref, err := name.NewDigest(imageName)
+	ref, err := parseDigest(imageName)
 	if err != nil {
 		return errors.Wrap(err, "getting digest")
 	}
@@ -129,12 +129,10 @@ func (b *Backend) uploadSignature(ctx context.Context, format simple.SimpleConta
 		return errors.Wrapf(err, "getting storage repo for sub %s", imageName)
 	}
 
-	store, err := NewSimpleStorerFromConfig(WithTargetRepository(repo))
+	store, err := NewSimpleStorerFromConfig(WithTargetRepository(repo), WithRemoteOptions(remoteOpts...))
 	if err != nil {
 		return err
 	}
-	// TODO: make these creation opts.
-	store.remoteOpts = remoteOpts
 	if _, err := store.Store(ctx, &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
 		Object:   nil,
 		Artifact: ref,
@@ -159,7 +157,7 @@ func (b *Backend) uploadAttestation(ctx context.Context, attestation *intoto.Sta
 		imageName := fmt.Sprintf("%s@sha256:%s", subj.Name, subj.Digest["sha256"])
 		logger.Infof("Starting attestation upload to OCI for %s...", imageName)
 
-		ref, err := name.NewDigest(imageName)
+		ref, err := parseDigest(imageName)
 		if err != nil {
 			return errors.Wrapf(err, "getting digest for subj %s", imageName)
 		}
@@ -169,12 +167,10 @@ func (b *Backend) uploadAttestation(ctx context.Context, attestation *intoto.Sta
 			return errors.Wrapf(err, "getting storage repo for sub %s", imageName)
 		}
 
-		store, err := NewAttestationStorer(WithTargetRepository(repo))
+		store, err := NewAttestationStorer(WithTargetRepository(repo), WithRemoteOptions(remoteOpts...))
 		if err != nil {
 			return err
 		}
-		// TODO: make these creation opts.
-		store.remoteOpts = remoteOpts
 		if _, err := store.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
 			Object:   nil,
 			Artifact: ref,
@@ -14,11 +14,17 @@ limitations under the License.
 package oci
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/sigstore/sigstore/pkg/signature/payload"
 	"github.com/stretchr/testify/assert"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
 	"github.com/tektoncd/chains/pkg/config"
+	logtesting "knative.dev/pkg/logging/testing"
 )
 
 func TestNewRepo(t *testing.T) {
@@ -56,3 +62,39 @@ func TestNewRepo(t *testing.T) {
 		}
 	})
 }
+
+func TestBackend_UppercaseDigest(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	ref := pushRandomImage(t, newTestRegistry(t, nil))
+	algorithm, hex, _ := strings.Cut(ref.DigestStr(), ":")
+	upper := strings.ToUpper(hex)
+	b := &Backend{}
+
+	statement := &intoto.Statement{
+		Type: intoto.StatementTypeUri,
+		Subject: []*intoto.ResourceDescriptor{{
+			Name:   ref.Context().Name(),
+			Digest: map[string]string{algorithm: upper},
+		}},
+	}
+	if err := b.uploadAttestation(ctx, statement, "attestation", config.StorageOpts{}); err != nil {
+		t.Fatalf("error uploading attestation: %v", err)
+	}
+	format := simple.SimpleContainerImage{
+		Critical: payload.Critical{
+			Identity: payload.Identity{DockerReference: ref.Context().Name()},
+			Image:    payload.Image{DockerManifestDigest: algorithm + ":" + upper},
+		},
+	}
+	if err := b.uploadSignature(ctx, format, []byte("payload"), "signature", config.StorageOpts{}); err != nil {
+		t.Fatalf("error uploading signature: %v", err)
+	}
+
+	// The tags must be the ones cosign derives from the normalized digest.
+	for _, suffix := range []string{".att", ".sig"} {
+		tag := ref.Context().Tag(algorithm + "-" + hex + suffix)
+		if _, err := remote.Head(tag); err != nil {
+			t.Errorf("failed to find %s: %v", tag, err)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/pkg/errors"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	"golang.org/x/sync/semaphore"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestWithStoreLimiter(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	var inFlight, maxInFlight atomic.Int32
+	var counting atomic.Bool
+	registryName := newTestRegistry(t, func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Each store writes a single manifest, while its blobs may be
+			// uploaded concurrently.
+			if counting.Load() && r.Method == http.MethodPut && strings.Contains(r.URL.Path, "/manifests/") {
+				n := inFlight.Add(1)
+				defer inFlight.Add(-1)
+				for m := maxInFlight.Load(); n > m && !maxInFlight.CompareAndSwap(m, n); m = maxInFlight.Load() {
+				}
+				time.Sleep(20 * time.Millisecond)
+			}
+			h.ServeHTTP(w, r)
+		})
+	})
+	var refs []name.Digest
+	for range 3 {
+		refs = append(refs, pushRandomImage(t, registryName))
+	}
+	counting.Store(true)
+
+	sem := semaphore.NewWeighted(1)
+	as, err := NewAttestationStorer(WithStoreLimiter(sem))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	ss, err := NewSimpleStorerFromConfig(WithStoreLimiter(sem))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*len(refs))
+	for _, ref := range refs {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := as.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+				Artifact: ref,
+				Payload:  &intoto.Statement{},
+				Bundle:   &signing.Bundle{},
+			})
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := ss.Store(ctx, &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+				Artifact: ref,
+				Payload:  simple.NewSimpleStruct(ref),
+				Bundle:   &signing.Bundle{},
+			})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("error during Store(): %v", err)
+		}
+	}
+	if got := maxInFlight.Load(); got != 1 {
+		t.Errorf("got up to %d concurrent stores, want 1", got)
+	}
+}
+
+func TestWithMaxConcurrentStores(t *testing.T) {
+	registryName := newTestRegistry(t, nil)
+	ref := pushRandomImage(t, registryName)
+
+	storer, err := NewAttestationStorer(WithMaxConcurrentStores(1))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	// Hold the only slot so that the store has to wait for it.
+	if err := storer.storeLimiter.Acquire(context.Background(), 1); err != nil {
+		t.Fatalf("failed to acquire the limiter: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(logtesting.TestContextWithLogger(t), 50*time.Millisecond)
+	defer cancel()
+	_, err = storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  &intoto.Statement{},
+		Bundle:   &signing.Bundle{},
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Store() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	storer.storeLimiter.Release(1)
+	if _, err := storer.Store(logtesting.TestContextWithLogger(t), &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  &intoto.Statement{},
+		Bundle:   &signing.Bundle{},
+	}); err != nil {
+		t.Errorf("error during Store(): %v", err)
+	}
+
+	if _, err := NewSimpleStorerFromConfig(WithMaxConcurrentStores(0)); err == nil {
+		t.Error("expected an error for a non-positive limit")
+	}
+	if _, err := NewSimpleStorerFromConfig(WithStoreLimiter(nil)); err == nil {
+		t.Error("expected an error for a nil limiter")
+	}
+}
+
+func TestWithMaxConcurrentStores_Shared(t *testing.T) {
+	mirror, err := name.NewRepository("mirror.example.com/test/img")
+	if err != nil {
+		t.Fatalf("failed to parse repository: %v", err)
+	}
+	own := semaphore.NewWeighted(1)
+	as, err := NewAttestationStorer(
+		WithMaxConcurrentStores(2),
+		WithMirrors([]MirrorRepository{{Repository: mirror}}, MirrorModeAll),
+		WithHostPolicy(map[string]HostPolicy{
+			"shared.example.com": {},
+			"own.example.com":    {Options: []Option{WithStoreLimiter(own)}},
+		}))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	if as.mirrorStorers[0].storeLimiter != as.storeLimiter {
+		t.Error("the mirror storer does not share the limiter of the storer")
+	}
+	if as.hostStorers["shared.example.com"].storeLimiter != as.storeLimiter {
+		t.Error("the host storer does not share the limiter of the storer")
+	}
+	if as.hostStorers["own.example.com"].storeLimiter != own {
+		t.Error("the host storer does not use the limiter of its policy")
+	}
+
+	ss, err := NewSimpleStorerFromConfig(
+		WithMaxConcurrentStores(2),
+		WithMirrors([]MirrorRepository{{Repository: mirror}}, MirrorModeAll),
+		WithHostPolicy(map[string]HostPolicy{"shared.example.com": {}}))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	if ss.mirrorStorers[0].storeLimiter != ss.storeLimiter || ss.hostStorers["shared.example.com"].storeLimiter != ss.storeLimiter {
+		t.Error("the nested storers do not share the limiter of the storer")
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/pkg/errors"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"knative.dev/pkg/logging"
+)
+
+// The distribution spec does not define how registries advertise their limits,
+// so they are read from these headers of the API version check response
+// (GET /v2/), when present.
+const (
+	manifestSizeLimitHeader   = "Registry-Limit-Manifest-Size"
+	blobSizeLimitHeader       = "Registry-Limit-Blob-Size"
+	annotationSizeLimitHeader = "Registry-Limit-Annotation-Size"
+)
+
+// ErrPayloadTooLarge is matched by errors of stores whose payload exceeds the
+// size configured with WithMaxPayloadBytes.
+var ErrPayloadTooLarge = errors.New("payload too large")
+
+// RegistryLimits are the size limits, in bytes, that a registry enforces on
+// the content pushed to it. Zero means no limit.
+type RegistryLimits struct {
+	// ManifestSize is the maximum size of a manifest.
+	ManifestSize int64
+	// BlobSize is the maximum size of a blob, e.g. an attestation layer.
+	BlobSize int64
+	// AnnotationSize is the maximum total size of the keys and values of the
+	// annotations of a single descriptor.
+	AnnotationSize int64
+}
+
+// LimitError is returned when the content to store exceeds a registry limit.
+type LimitError struct {
+	// Limit names the violated limit: "manifest size", "blob size" or
+	// "annotation size".
+	Limit string
+	// Size is the size of the offending content.
+	Size int64
+	// Max is the limit enforced by the registry.
+	Max int64
+}
+
+// Error implements error.
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s of %d bytes exceeds the registry limit of %d bytes", e.Limit, e.Size, e.Max)
+}
+
+// registryLimits validates stores against the limits of the target registries.
+type registryLimits struct {
+	// static are the limits applied when a registry does not advertise its own.
+	static RegistryLimits
+
+	mu sync.Mutex
+	// advertised caches the limits advertised by each registry host.
+	advertised map[string]RegistryLimits
+}
+
+func newRegistryLimits(static RegistryLimits) *registryLimits {
+	return &registryLimits{
+		static:     static,
+		advertised: map[string]RegistryLimits{},
+	}
+}
+
+// forRegistry returns the limits of the registry, preferring the advertised
+// limits over the static ones. Registries that cannot be queried fall back to
+// the static limits.
+func (l *registryLimits) forRegistry(ctx context.Context, reg name.Registry, rt http.RoundTripper) RegistryLimits {
+	l.mu.Lock()
+	limits, ok := l.advertised[reg.RegistryStr()]
+	l.mu.Unlock()
+	if !ok {
+		var err error
+		limits, err = fetchRegistryLimits(ctx, reg, rt)
+		if err != nil {
+			logging.FromContext(ctx).Warnf("Failed to query the limits of %s, using the configured limits: %v", reg.RegistryStr(), err)
+			return l.static
+		}
+		l.mu.Lock()
+		l.advertised[reg.RegistryStr()] = limits
+		l.mu.Unlock()
+	}
+	if limits.ManifestSize == 0 {
+		limits.ManifestSize = l.static.ManifestSize
+	}
+	if limits.BlobSize == 0 {
+		limits.BlobSize = l.static.BlobSize
+	}
+	if limits.AnnotationSize == 0 {
+		limits.AnnotationSize = l.static.AnnotationSize
+	}
+	return limits
+}
+
+// fetchRegistryLimits reads the limits advertised by the registry. The check
+// is unauthenticated, as registries answer it with their limits whether or not
+// they challenge the client.
+func fetchRegistryLimits(ctx context.Context, reg name.Registry, rt http.RoundTripper) (RegistryLimits, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s://%s/v2/", reg.Scheme(), reg.RegistryStr()), nil)
+	if err != nil {
+		return RegistryLimits{}, err
+	}
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		return RegistryLimits{}, err
+	}
+	defer resp.Body.Close()
+
+	var limits RegistryLimits
+	for header, limit := range map[string]*int64{
+		manifestSizeLimitHeader:   &limits.ManifestSize,
+		blobSizeLimitHeader:       &limits.BlobSize,
+		annotationSizeLimitHeader: &limits.AnnotationSize,
+	} {
+		v := strings.TrimSpace(resp.Header.Get(header))
+		if v == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			return RegistryLimits{}, errors.Errorf("invalid %s header %q", header, v)
+		}
+		*limit = n
+	}
+	return limits, nil
+}
+
+// validate checks the manifest, layers and annotations of sigs against limits.
+func (l RegistryLimits) validate(sigs oci.Signatures) error {
+	manifest, err := sigs.RawManifest()
+	if err != nil {
+		return err
+	}
+	if err := checkLimit("manifest size", int64(len(manifest)), l.ManifestSize); err != nil {
+		return err
+	}
+	layers, err := sigs.Get()
+	if err != nil {
+		return err
+	}
+	for _, layer := range layers {
+		size, err := layer.Size()
+		if err != nil {
+			return err
+		}
+		if err := checkLimit("blob size", size, l.BlobSize); err != nil {
+			return err
+		}
+		ann, err := layer.Annotations()
+		if err != nil {
+			return err
+		}
+		var annSize int64
+		for k, v := range ann {
+			annSize += int64(len(k) + len(v))
+		}
+		if err := checkLimit("annotation size", annSize, l.AnnotationSize); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkLimit(limit string, size, maxSize int64) error {
+	if maxSize > 0 && size > maxSize {
+		return &LimitError{Limit: limit, Size: size, Max: maxSize}
+	}
+	return nil
+}
+
+// checkRegistryLimits validates sigs against the limits of the registry of
+// repo, if configured with WithRegistryLimits.
+func (b *baseStorer) checkRegistryLimits(ctx context.Context, repo name.Repository, sigs oci.Signatures) error {
+	if b.registryLimits == nil {
+		return nil
+	}
+	rt := b.transport
+	if rt == nil {
+		rt = remote.DefaultTransport
+	}
+	rt = transport.NewUserAgent(rt, b.userAgentString())
+	return b.registryLimits.forRegistry(ctx, repo.Registry, rt).validate(sigs)
+}
+
+// checkPayloadSize fails stores of payloads larger than the size configured
+// with WithMaxPayloadBytes, before anything is sent to the registry.
+func (b *baseStorer) checkPayloadSize(artifact name.Digest, size int) error {
+	if b.maxPayloadBytes > 0 && int64(size) > b.maxPayloadBytes {
+		return &classifiedError{
+			sentinel: ErrPayloadTooLarge,
+			err:      errors.Errorf("payload of %d bytes for %s exceeds the maximum payload size of %d bytes", size, artifact.String(), b.maxPayloadBytes),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/pkg/errors"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// newLimitedRegistry starts a registry advertising the given limit headers on
+// its API version check, and counts the manifests pushed to it.
+func newLimitedRegistry(t *testing.T, headers map[string]string) (string, *atomic.Int32) {
+	t.Helper()
+	var pushed atomic.Int32
+	registryName := newTestRegistry(t, func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/v2/" {
+				for k, v := range headers {
+					w.Header().Set(k, v)
+				}
+			}
+			if r.Method == http.MethodPut && strings.Contains(r.URL.Path, "/manifests/") {
+				pushed.Add(1)
+			}
+			h.ServeHTTP(w, r)
+		})
+	})
+	return registryName, &pushed
+}
+
+func TestWithRegistryLimits(t *testing.T) {
+	tests := []struct {
+		name      string
+		headers   map[string]string
+		static    RegistryLimits
+		wantLimit string
+	}{
+		{
+			name:      "advertised blob size",
+			headers:   map[string]string{blobSizeLimitHeader: "16"},
+			wantLimit: "blob size",
+		},
+		{
+			name:      "advertised manifest size",
+			headers:   map[string]string{manifestSizeLimitHeader: "64"},
+			wantLimit: "manifest size",
+		},
+		{
+			name:      "advertised annotation size",
+			headers:   map[string]string{annotationSizeLimitHeader: "8"},
+			wantLimit: "annotation size",
+		},
+		{
+			name:      "advertised limits override static",
+			headers:   map[string]string{blobSizeLimitHeader: "16"},
+			static:    RegistryLimits{BlobSize: 1 << 20},
+			wantLimit: "blob size",
+		},
+		{
+			name:      "static fallback",
+			static:    RegistryLimits{BlobSize: 16},
+			wantLimit: "blob size",
+		},
+		{
+			name:    "within limits",
+			headers: map[string]string{blobSizeLimitHeader: "1048576", manifestSizeLimitHeader: "1048576"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := logtesting.TestContextWithLogger(t)
+			registryName, pushed := newLimitedRegistry(t, tt.headers)
+			ref := pushRandomImage(t, registryName)
+			pushed.Store(0)
+
+			ss, err := NewSimpleStorerFromConfig(WithTargetRepository(ref.Repository), WithRegistryLimits(tt.static))
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+			as, err := NewAttestationStorer(WithTargetRepository(ref.Repository), WithRegistryLimits(tt.static))
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+			bundle := &signing.Bundle{Content: []byte(strings.Repeat("c", 64)), Signature: []byte(strings.Repeat("s", 64))}
+			errs := map[string]error{}
+			_, errs["signature"] = ss.Store(ctx, &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+				Artifact: ref,
+				Payload:  simple.NewSimpleStruct(ref),
+				Bundle:   bundle,
+			})
+			_, errs["attestation"] = as.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+				Artifact: ref,
+				Payload:  &intoto.Statement{},
+				Bundle:   bundle,
+			})
+
+			for kind, err := range errs {
+				if tt.wantLimit == "" {
+					if err != nil {
+						t.Errorf("storing %s: unexpected error: %v", kind, err)
+					}
+					continue
+				}
+				var lerr *LimitError
+				if !errors.As(err, &lerr) {
+					t.Errorf("storing %s: error = %v, want a *LimitError", kind, err)
+				} else if lerr.Limit != tt.wantLimit {
+					t.Errorf("storing %s: violated limit = %q, want %q", kind, lerr.Limit, tt.wantLimit)
+				}
+			}
+			if tt.wantLimit != "" && pushed.Load() != 0 {
+				t.Errorf("got %d manifests pushed, want none", pushed.Load())
+			}
+		})
+	}
+}
+
+func TestWithRegistryLimits_Invalid(t *testing.T) {
+	if _, err := NewAttestationStorer(WithRegistryLimits(RegistryLimits{BlobSize: -1})); err == nil {
+		t.Error("expected an error for negative limits")
+	}
+}
+
+func TestWithMaxPayloadBytes(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	ref := pushRandomImage(t, newTestRegistry(t, nil))
+	transport := &countingTransport{inner: http.DefaultTransport}
+	statement, payload := newTestStatement(t, ref, "https://example.com/predicate")
+	envelope := newTestEnvelope(t, payload)
+	attReq := &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  statement,
+		Bundle:   &signing.Bundle{Signature: envelope},
+	}
+	sigReq := &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+		Artifact: ref,
+		Payload:  simple.NewSimpleStruct(ref),
+		Bundle:   &signing.Bundle{Content: []byte(strings.Repeat("c", 64)), Signature: []byte("signature")},
+	}
+
+	as, err := NewAttestationStorer(WithTransport(transport), WithMaxPayloadBytes(int64(len(envelope)-1)))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	if _, err := as.Store(ctx, attReq); !errors.Is(err, ErrPayloadTooLarge) {
+		t.Errorf("Store() = %v, want ErrPayloadTooLarge", err)
+	}
+	ss, err := NewSimpleStorerFromConfig(WithTransport(transport), WithMaxPayloadBytes(63))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	if _, err := ss.Store(ctx, sigReq); !errors.Is(err, ErrPayloadTooLarge) {
+		t.Errorf("Store() = %v, want ErrPayloadTooLarge", err)
+	}
+	if n := transport.requests.Load(); n != 0 {
+		t.Errorf("%d requests sent for oversized payloads, want none", n)
+	}
+
+	// Payloads at the limit, or without a limit, are stored.
+	for _, opt := range []Option{WithMaxPayloadBytes(int64(len(envelope))), WithMaxPayloadBytes(0)} {
+		as, err := NewAttestationStorer(opt)
+		if err != nil {
+			t.Fatalf("failed to create storer: %v", err)
+		}
+		if _, err := as.Store(ctx, attReq); err != nil {
+			t.Errorf("error during Store(): %v", err)
+		}
+	}
+	ss, err = NewSimpleStorerFromConfig(WithMaxPayloadBytes(64))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	if _, err := ss.Store(ctx, sigReq); err != nil {
+		t.Errorf("error during Store(): %v", err)
+	}
+
+	if _, err := NewAttestationStorer(WithMaxPayloadBytes(-1)); err == nil {
+		t.Error("expected an error for a negative size")
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+)
+
+const (
+	// parentAttestationsAnnotation holds the comma separated references of the
+	// attestation manifests an attestation was derived from.
+	parentAttestationsAnnotation = "dev.tekton.chains/parent-attestations"
+	// chainDepthAnnotation holds the length of the longest chain of parent
+	// attestations leading to an attestation. Attestations without parents
+	// have a depth of 0.
+	chainDepthAnnotation = "dev.tekton.chains/chain-depth"
+)
+
+// addLineageAnnotations records in annotations the parent attestations
+// configured with WithParentAttestations and the resulting chain depth, one
+// more than the deepest parent.
+func (s *AttestationStorer) addLineageAnnotations(ctx context.Context, annotations map[string]string) error {
+	if len(s.parentAttestations) == 0 {
+		return nil
+	}
+	parents := make([]string, 0, len(s.parentAttestations))
+	depth := 0
+	for _, parent := range s.parentAttestations {
+		d, err := chainDepth(parent, s.remoteOptions(ctx)...)
+		if err != nil {
+			return errors.Wrapf(err, "reading chain depth of parent attestation %s", parent.String())
+		}
+		depth = max(depth, d+1)
+		parents = append(parents, parent.String())
+	}
+	annotations[parentAttestationsAnnotation] = strings.Join(parents, ",")
+	annotations[chainDepthAnnotation] = strconv.Itoa(depth)
+	return nil
+}
+
+// chainDepth returns the chain depth recorded on the layers of the attestation
+// manifest, the deepest if they differ.
+func chainDepth(ref name.Digest, opts ...remote.Option) (int, error) {
+	img, err := remote.Image(ref, opts...)
+	if err != nil {
+		return 0, err
+	}
+	m, err := img.Manifest()
+	if err != nil {
+		return 0, err
+	}
+	depth := 0
+	for _, layer := range m.Layers {
+		v, ok := layer.Annotations[chainDepthAnnotation]
+		if !ok {
+			continue
+		}
+		d, err := strconv.Atoi(v)
+		if err != nil || d < 0 {
+			return 0, errors.Errorf("invalid chain depth %q", v)
+		}
+		depth = max(depth, d)
+	}
+	return depth, nil
+}
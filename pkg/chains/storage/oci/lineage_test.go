@@ -0,0 +1,91 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestWithParentAttestations(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	registryName := newTestRegistry(t, nil)
+
+	// store attests a new image with the given parents and returns the
+	// attestation manifest and the annotations of its layer.
+	store := func(parents ...name.Digest) (name.Digest, map[string]string) {
+		t.Helper()
+		ref := pushRandomImage(t, registryName)
+		storer, err := NewAttestationStorer(WithParentAttestations(parents))
+		if err != nil {
+			t.Fatalf("failed to create storer: %v", err)
+		}
+		resp, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+			Artifact: ref,
+			Payload:  &intoto.Statement{},
+			Bundle:   &signing.Bundle{},
+		})
+		if err != nil {
+			t.Fatalf("error during Store(): %v", err)
+		}
+		att := ref.Context().Digest(resp.Digest)
+		img, err := remote.Image(att)
+		if err != nil {
+			t.Fatalf("failed to fetch attestation: %v", err)
+		}
+		m, err := img.Manifest()
+		if err != nil {
+			t.Fatalf("failed to get attestation manifest: %v", err)
+		}
+		return att, m.Layers[0].Annotations
+	}
+
+	root, ann := store()
+	if _, ok := ann[chainDepthAnnotation]; ok {
+		t.Errorf("expected no chain depth on an attestation without parents, got %q", ann[chainDepthAnnotation])
+	}
+	child, ann := store(root)
+	if got, want := ann[chainDepthAnnotation], "1"; got != want {
+		t.Errorf("child chain depth = %q, want %q", got, want)
+	}
+	_, ann = store(root, child)
+	if got, want := ann[chainDepthAnnotation], "2"; got != want {
+		t.Errorf("grandchild chain depth = %q, want %q", got, want)
+	}
+	if got, want := ann[parentAttestationsAnnotation], root.String()+","+child.String(); got != want {
+		t.Errorf("grandchild parents = %q, want %q", got, want)
+	}
+}
+
+func TestWithParentAttestations_MissingParent(t *testing.T) {
+	ref := pushRandomImage(t, newTestRegistry(t, nil))
+	storer, err := NewAttestationStorer(WithParentAttestations([]name.Digest{ref.Context().Digest("sha256:" + "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef")}))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	if _, err := storer.Store(logtesting.TestContextWithLogger(t), &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  &intoto.Statement{},
+		Bundle:   &signing.Bundle{},
+	}); err == nil {
+		t.Error("expected an error for a missing parent attestation")
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/pkg/errors"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+)
+
+// AttestationInfo describes an attestation stored for an artifact.
+type AttestationInfo struct {
+	// PredicateType is the predicate type of the statement, or empty if it
+	// could not be determined.
+	PredicateType string
+	// MediaType is the media type of the layer holding the attestation, e.g.
+	// application/vnd.dsse.envelope.v1+json.
+	MediaType string
+	// Digest is the digest of the layer holding the attestation. For
+	// attestations split across layers, it is the digest of the first chunk.
+	Digest string
+	// ManifestDigest is the digest of the manifest holding the attestation:
+	// the .att manifest or the referrer manifest.
+	ManifestDigest string
+	// Created is the creation time recorded for the attestation, or the zero
+	// time if none is recorded.
+	Created time.Time
+	// Annotations are the annotations of the layer holding the attestation.
+	Annotations map[string]string
+}
+
+// List returns the attestations stored for the given artifact, whether they
+// were attached through the legacy .att tag or as referrers, without fetching
+// more than needed to describe them. Attestations attached both ways are
+// listed once. An empty slice is returned if there are none.
+func (s *AttestationStorer) List(ctx context.Context, artifact name.Digest) ([]AttestationInfo, error) {
+	if hs := s.hostStorer(artifact); hs != nil {
+		return hs.List(ctx, artifact)
+	}
+	repo := s.targetRepository(artifact)
+
+	infos, err := s.listTagged(ctx, artifact, repo)
+	if err != nil {
+		return nil, err
+	}
+	if s.localLayout == "" {
+		referred, err := s.listReferrers(ctx, repo.Digest(artifact.DigestStr()))
+		if err != nil {
+			return nil, err
+		}
+		seen := map[string]bool{}
+		for _, info := range infos {
+			seen[info.Digest] = true
+		}
+		for _, info := range referred {
+			if !seen[info.Digest] {
+				seen[info.Digest] = true
+				infos = append(infos, info)
+			}
+		}
+	}
+	return infos, nil
+}
+
+// listTagged describes the attestations attached through the legacy .att tag.
+func (s *AttestationStorer) listTagged(ctx context.Context, artifact name.Digest, repo name.Repository) ([]AttestationInfo, error) {
+	se, err := s.storedEntity(ctx, artifact, repo)
+	if err != nil {
+		return nil, err
+	}
+	atts, err := se.Attestations()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting attestations")
+	}
+	sigs, err := atts.Get()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting attestations")
+	}
+	infos := []AttestationInfo{}
+	if len(sigs) == 0 {
+		return infos, nil
+	}
+	manifestDigest, err := atts.Digest()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting attestations")
+	}
+	// The payloads are reassembled in the order their first layer appears in,
+	// which is the order the infos are collected in.
+	payloads, err := reassemblePayloads(sigs)
+	if err != nil {
+		return nil, err
+	}
+	groups := map[string]bool{}
+	for _, sig := range sigs {
+		ann, err := sig.Annotations()
+		if err != nil {
+			return nil, errors.Wrap(err, "reading attestation annotations")
+		}
+		if group, ok := ann[splitGroupAnnotation]; ok {
+			if groups[group] {
+				continue
+			}
+			groups[group] = true
+		}
+		info, err := layerInfo(sig, ann, manifestDigest)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	// The predicate type annotation may have been shortened, so the statements
+	// are preferred.
+	for i := range infos {
+		if i >= len(payloads) {
+			break
+		}
+		if statement, err := statementFromEnvelope(payloads[i]); err == nil {
+			infos[i].PredicateType = statement.GetPredicateType()
+		}
+	}
+	return infos, nil
+}
+
+// layerInfo describes the attestation layer l with annotations ann, held by
+// the manifest with the given digest.
+func layerInfo(l v1.Layer, ann map[string]string, manifestDigest v1.Hash) (AttestationInfo, error) {
+	mt, err := l.MediaType()
+	if err != nil {
+		return AttestationInfo{}, errors.Wrap(err, "reading attestation media type")
+	}
+	d, err := l.Digest()
+	if err != nil {
+		return AttestationInfo{}, errors.Wrap(err, "reading attestation digest")
+	}
+	info := AttestationInfo{
+		PredicateType:  ann[predicateTypeAnnotation],
+		MediaType:      string(mt),
+		Digest:         d.String(),
+		ManifestDigest: manifestDigest.String(),
+		Annotations:    ann,
+	}
+	if t, err := time.Parse(time.RFC3339, ann[createdAnnotation]); err == nil {
+		info.Created = t
+	}
+	return info, nil
+}
+
+// listReferrers describes the in-toto and sigstore bundle referrers of the
+// artifact.
+func (s *AttestationStorer) listReferrers(ctx context.Context, d name.Digest) ([]AttestationInfo, error) {
+	idx, err := ociremote.Referrers(d, "", ociremote.WithRemoteOptions(s.remoteOptions(ctx)...))
+	var terr *transport.Error
+	if errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "listing referrers")
+	}
+
+	var infos []AttestationInfo
+	for _, desc := range idx.Manifests {
+		img, err := remote.Image(d.Context().Digest(desc.Digest.String()), s.remoteOptions(ctx)...)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fetching referrer %s", desc.Digest)
+		}
+		m, err := img.Manifest()
+		if err != nil {
+			return nil, errors.Wrapf(err, "fetching referrer %s", desc.Digest)
+		}
+		created, err := referrerCreated(desc, img)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fetching referrer %s", desc.Digest)
+		}
+		for _, ld := range m.Layers {
+			l, err := img.LayerByDigest(ld.Digest)
+			if err != nil {
+				return nil, errors.Wrapf(err, "fetching referrer %s", desc.Digest)
+			}
+			statement, err := statementFromLayer(l)
+			if err != nil {
+				return nil, errors.Wrapf(err, "reading referrer %s", desc.Digest)
+			}
+			if statement == nil {
+				continue
+			}
+			info, err := layerInfo(l, ld.Annotations, desc.Digest)
+			if err != nil {
+				return nil, err
+			}
+			info.PredicateType = statement.GetPredicateType()
+			if info.Created.IsZero() {
+				info.Created = created
+			}
+			infos = append(infos, info)
+		}
+	}
+	return infos, nil
+}
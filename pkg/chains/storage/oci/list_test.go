@@ -0,0 +1,95 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/sigstore/cosign/v2/pkg/types"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestAttestationStorer_List(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	s := httptest.NewServer(registry.New(registry.WithReferrersSupport(true)))
+	t.Cleanup(s.Close)
+	ref := pushRandomImage(t, strings.TrimPrefix(s.URL, "http://"))
+
+	storer, err := NewAttestationStorer()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	infos, err := storer.List(ctx, ref)
+	if err != nil {
+		t.Fatalf("error during List(): %v", err)
+	}
+	if infos == nil || len(infos) != 0 {
+		t.Fatalf("List() = %v, want an empty slice", infos)
+	}
+
+	tagged, taggedPayload := newTestStatement(t, ref, "https://example.com/tagged")
+	resp, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  tagged,
+		Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, taggedPayload)},
+	})
+	if err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+	// The referrer manifest also holds the attestation attached to the .att
+	// tag, which must be listed once.
+	writeTestReferrers(t, ref)
+
+	infos, err = storer.List(ctx, ref)
+	if err != nil {
+		t.Fatalf("error during List(): %v", err)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].PredicateType < infos[j].PredicateType })
+	want := []struct {
+		predicateType, mediaType string
+	}{
+		{"https://example.com/bundle", testBundleMediaType},
+		{"https://example.com/referrer", string(types.DssePayloadType)},
+		{"https://example.com/tagged", string(types.DssePayloadType)},
+	}
+	if len(infos) != len(want) {
+		t.Fatalf("List() returned %d attestations, want %d: %+v", len(infos), len(want), infos)
+	}
+	for i, w := range want {
+		if infos[i].PredicateType != w.predicateType || infos[i].MediaType != w.mediaType {
+			t.Errorf("attestation %d = %s (%s), want %s (%s)", i, infos[i].PredicateType, infos[i].MediaType, w.predicateType, w.mediaType)
+		}
+		if infos[i].Digest == "" || infos[i].ManifestDigest == "" {
+			t.Errorf("attestation %d has no layer or manifest digest: %+v", i, infos[i])
+		}
+	}
+	if got := infos[2].ManifestDigest; got != resp.Digest {
+		t.Errorf("tagged attestation manifest = %s, want the .att manifest %s", got, resp.Digest)
+	}
+	if got := infos[2].Annotations[predicateTypeAnnotation]; got != "https://example.com/tagged" {
+		t.Errorf("tagged attestation annotation %s = %q", predicateTypeAnnotation, got)
+	}
+	if infos[0].ManifestDigest == infos[1].ManifestDigest {
+		t.Error("referrers are listed with the same manifest")
+	}
+}
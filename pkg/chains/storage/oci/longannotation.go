@@ -0,0 +1,62 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// LongAnnotationStrategy controls how annotation values longer than the
+// configured limit are recorded.
+type LongAnnotationStrategy int
+
+const (
+	// LongAnnotationHash records "sha256:<hex>", the digest of the value, in
+	// place of the value.
+	LongAnnotationHash LongAnnotationStrategy = iota
+	// LongAnnotationTruncate records the start of the value followed by "~"
+	// and the first 16 hex characters of the digest of the value, so that
+	// truncated values sharing a prefix stay distinct.
+	LongAnnotationTruncate
+)
+
+// minLongAnnotationLength is the shortest limit for which both strategies
+// can record an over-limit value: the length of a hashed value.
+const minLongAnnotationLength = len("sha256:") + sha256.Size*2
+
+// truncatedHashLength is the number of hex characters of the digest appended
+// to truncated values.
+const truncatedHashLength = 16
+
+// longAnnotations shortens over-limit annotation values.
+type longAnnotations struct {
+	strategy  LongAnnotationStrategy
+	maxLength int
+}
+
+// shorten returns v, shortened with the configured strategy if it is longer
+// than the limit. A nil longAnnotations returns v as is.
+func (l *longAnnotations) shorten(v string) string {
+	if l == nil || len(v) <= l.maxLength {
+		return v
+	}
+	sum := sha256.Sum256([]byte(v))
+	digest := hex.EncodeToString(sum[:])
+	if l.strategy == LongAnnotationTruncate {
+		return v[:l.maxLength-truncatedHashLength-1] + "~" + digest[:truncatedHashLength]
+	}
+	return "sha256:" + digest
+}
@@ -0,0 +1,117 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestWithLongAnnotationStrategy(t *testing.T) {
+	longType := "https://example.com/predicates/" + strings.Repeat("very-long-segment/", 20) + "v1"
+	sum := sha256.Sum256([]byte(longType))
+	digest := hex.EncodeToString(sum[:])
+	tests := []struct {
+		name          string
+		predicateType string
+		opts          []AttestationStorerOption
+		want          string
+	}{
+		{
+			name:          "recorded as is by default",
+			predicateType: longType,
+			want:          longType,
+		},
+		{
+			name:          "hash",
+			predicateType: longType,
+			opts:          []AttestationStorerOption{WithLongAnnotationStrategy(LongAnnotationHash, 128)},
+			want:          "sha256:" + digest,
+		},
+		{
+			name:          "truncate",
+			predicateType: longType,
+			opts:          []AttestationStorerOption{WithLongAnnotationStrategy(LongAnnotationTruncate, 128)},
+			want:          longType[:111] + "~" + digest[:16],
+		},
+		{
+			name:          "within the limit",
+			predicateType: "https://slsa.dev/provenance/v1",
+			opts:          []AttestationStorerOption{WithLongAnnotationStrategy(LongAnnotationHash, 128)},
+			want:          "https://slsa.dev/provenance/v1",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := logtesting.TestContextWithLogger(t)
+			ref := pushRandomImage(t, newTestRegistry(t, nil))
+			storer, err := NewAttestationStorer(tc.opts...)
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+			statement, payload := newTestStatement(t, ref, tc.predicateType)
+			if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+				Artifact: ref,
+				Payload:  statement,
+				Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+			}); err != nil {
+				t.Fatalf("error during Store(): %v", err)
+			}
+
+			se, err := ociremote.SignedEntity(ref)
+			if err != nil {
+				t.Fatalf("failed to get signed entity: %v", err)
+			}
+			atts, err := se.Attestations()
+			if err != nil {
+				t.Fatalf("failed to get attestations: %v", err)
+			}
+			layers, err := atts.Get()
+			if err != nil || len(layers) != 1 {
+				t.Fatalf("failed to get the attestation: %d layers, %v", len(layers), err)
+			}
+			ann, err := layers[0].Annotations()
+			if err != nil {
+				t.Fatalf("failed to read annotations: %v", err)
+			}
+			if got := ann[predicateTypeAnnotation]; got != tc.want {
+				t.Errorf("annotation %s = %q, want %q", predicateTypeAnnotation, got, tc.want)
+			}
+			if len(tc.opts) > 0 && len(ann[predicateTypeAnnotation]) > 128 {
+				t.Errorf("annotation %s is %d bytes long, want at most 128", predicateTypeAnnotation, len(ann[predicateTypeAnnotation]))
+			}
+		})
+	}
+}
+
+func TestWithLongAnnotationStrategy_Invalid(t *testing.T) {
+	for _, o := range []AttestationStorerOption{
+		WithLongAnnotationStrategy(LongAnnotationHash, 70),
+		WithLongAnnotationStrategy(LongAnnotationStrategy(42), 128),
+	} {
+		if _, err := NewAttestationStorer(o); err == nil {
+			t.Errorf("expected an error for %+v", o)
+		}
+	}
+}
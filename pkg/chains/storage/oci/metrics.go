@@ -0,0 +1,122 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tektoncd/chains/pkg/chains/formats"
+)
+
+const (
+	metricsNamespace = "chains"
+	metricsSubsystem = "oci_storage"
+
+	outcomeSuccess = "success"
+	outcomeError   = "error"
+)
+
+var (
+	// attestationFormat labels the metrics of the AttestationStorer.
+	attestationFormat = string(formats.PayloadTypeInTotoIte6)
+	// signatureFormat labels the metrics of the SimpleStorer.
+	signatureFormat = string(formats.PayloadTypeSimpleSigning)
+)
+
+// storeMetrics are the metrics of the store operations. They are labeled by
+// payload format and outcome only, never by image reference, to bound their
+// cardinality.
+type storeMetrics struct {
+	stores  *prometheus.CounterVec
+	latency *prometheus.HistogramVec
+	retries *prometheus.CounterVec
+}
+
+// newStoreMetrics registers the store metrics with reg. Collectors already
+// registered, e.g. by another storer sharing reg, are reused.
+func newStoreMetrics(reg prometheus.Registerer) (*storeMetrics, error) {
+	m := &storeMetrics{
+		stores: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "stores_total",
+			Help:      "Number of store attempts by payload format and outcome.",
+		}, []string{"format", "outcome"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "store_duration_seconds",
+			Help:      "Duration of the store attempts by payload format and outcome.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"format", "outcome"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "retries_total",
+			Help:      "Number of retried registry requests by payload format.",
+		}, []string{"format"}),
+	}
+	var err error
+	if m.stores, err = register(reg, m.stores); err != nil {
+		return nil, err
+	}
+	if m.latency, err = register(reg, m.latency); err != nil {
+		return nil, err
+	}
+	if m.retries, err = register(reg, m.retries); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func register[C prometheus.Collector](reg prometheus.Registerer, c C) (C, error) {
+	err := reg.Register(c)
+	var are prometheus.AlreadyRegisteredError
+	if errors.As(err, &are) {
+		if existing, ok := are.ExistingCollector.(C); ok {
+			return existing, nil
+		}
+	}
+	return c, err
+}
+
+// formatMetrics are the store metrics of a single payload format.
+type formatMetrics struct {
+	*storeMetrics
+	format string
+}
+
+// observeStore records the outcome and latency of a store that took elapsed.
+func (m *formatMetrics) observeStore(elapsed time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	outcome := outcomeSuccess
+	if err != nil {
+		outcome = outcomeError
+	}
+	m.stores.WithLabelValues(m.format, outcome).Inc()
+	m.latency.WithLabelValues(m.format, outcome).Observe(elapsed.Seconds())
+}
+
+// observeRetry records a retried registry request.
+func (m *formatMetrics) observeRetry() {
+	if m == nil {
+		return
+	}
+	m.retries.WithLabelValues(m.format).Inc()
+}
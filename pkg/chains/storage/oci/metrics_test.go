@@ -0,0 +1,194 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// metricValue returns the value of the counter or the sample count of the
+// histogram with the given name and labels.
+func metricValue(t *testing.T, reg prometheus.Gatherer, metric string, labels map[string]string) float64 {
+	t.Helper()
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+	for _, f := range families {
+		if f.GetName() != metric {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			if !hasLabels(m, labels) {
+				continue
+			}
+			if h := m.GetHistogram(); h != nil {
+				return float64(h.GetSampleCount())
+			}
+			return m.GetCounter().GetValue()
+		}
+	}
+	return 0
+}
+
+func hasLabels(m *dto.Metric, labels map[string]string) bool {
+	if len(m.GetLabel()) != len(labels) {
+		return false
+	}
+	for _, l := range m.GetLabel() {
+		if labels[l.GetName()] != l.GetValue() {
+			return false
+		}
+	}
+	return true
+}
+
+func TestWithMetrics(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	ref := pushRandomImage(t, newTestRegistry(t, nil))
+	reg := prometheus.NewRegistry()
+
+	ss, err := NewSimpleStorerFromConfig(WithTargetRepository(ref.Repository), WithMetrics(reg))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	as, err := NewAttestationStorer(WithTargetRepository(ref.Repository), WithMetrics(reg))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	if _, err := ss.Store(ctx, &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+		Artifact: ref,
+		Payload:  simple.NewSimpleStruct(ref),
+		Bundle:   &signing.Bundle{},
+	}); err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+	for range 2 {
+		if _, err := as.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+			Artifact: ref,
+			Payload:  &intoto.Statement{},
+			Bundle:   &signing.Bundle{},
+		}); err != nil {
+			t.Fatalf("error during Store(): %v", err)
+		}
+	}
+	// A store to a missing registry fails.
+	missing, err := name.NewDigest("127.0.0.1:1/missing@" + ref.DigestStr())
+	if err != nil {
+		t.Fatalf("failed to parse digest: %v", err)
+	}
+	if _, err := as.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: missing,
+		Payload:  &intoto.Statement{},
+		Bundle:   &signing.Bundle{},
+	}); err == nil {
+		t.Fatal("expected an error storing to a missing registry")
+	}
+
+	for _, tt := range []struct {
+		metric string
+		labels map[string]string
+		want   float64
+	}{
+		{"chains_oci_storage_stores_total", map[string]string{"format": signatureFormat, "outcome": outcomeSuccess}, 1},
+		{"chains_oci_storage_stores_total", map[string]string{"format": attestationFormat, "outcome": outcomeSuccess}, 2},
+		{"chains_oci_storage_stores_total", map[string]string{"format": attestationFormat, "outcome": outcomeError}, 1},
+		{"chains_oci_storage_store_duration_seconds", map[string]string{"format": attestationFormat, "outcome": outcomeSuccess}, 2},
+	} {
+		if got := metricValue(t, reg, tt.metric, tt.labels); got != tt.want {
+			t.Errorf("%s%v = %v, want %v", tt.metric, tt.labels, got, tt.want)
+		}
+	}
+}
+
+func TestWithMetrics_Clock(t *testing.T) {
+	clk := &fakeClock{t: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	// Writing the attestation manifest takes a minute on the configured clock.
+	registryName := newTestRegistry(t, func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPut && strings.HasSuffix(r.URL.Path, ".att") {
+				clk.step(time.Minute)
+			}
+			h.ServeHTTP(w, r)
+		})
+	})
+	ref := pushRandomImage(t, registryName)
+	reg := prometheus.NewRegistry()
+	storer, err := NewAttestationStorer(WithClock(clk), WithMetrics(reg))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	storeTestAttestations(t, storer, ref, "https://example.com/predicate")
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+	var got float64
+	for _, f := range families {
+		if f.GetName() != "chains_oci_storage_store_duration_seconds" {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			if hasLabels(m, map[string]string{"format": attestationFormat, "outcome": outcomeSuccess}) {
+				got = m.GetHistogram().GetSampleSum()
+			}
+		}
+	}
+	if want := time.Minute.Seconds(); got != want {
+		t.Errorf("store duration = %vs, want %vs", got, want)
+	}
+}
+
+func TestWithMetrics_Retries(t *testing.T) {
+	ref := pushRandomImage(t, newTestRegistry(t, nil))
+	reg := prometheus.NewRegistry()
+
+	storer, err := NewAttestationStorer(WithTargetRepository(ref.Repository), WithMetrics(reg), WithRetry(3, time.Millisecond))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	storer.transport = &failingWriteTransport{inner: http.DefaultTransport, status: http.StatusTooManyRequests, failures: 2}
+	storer.remoteOpts = []remote.Option{remote.WithRetryBackoff(remote.Backoff{Steps: 1}), remote.WithRetryStatusCodes()}
+	if _, err := storer.Store(logtesting.TestContextWithLogger(t), &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  &intoto.Statement{},
+		Bundle:   &signing.Bundle{},
+	}); err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+	if got := metricValue(t, reg, "chains_oci_storage_retries_total", map[string]string{"format": attestationFormat}); got != 2 {
+		t.Errorf("got %v retries, want 2", got)
+	}
+}
+
+func TestWithMetrics_NilRegisterer(t *testing.T) {
+	if _, err := NewAttestationStorer(WithMetrics(nil)); err == nil {
+		t.Error("expected an error for a nil registerer")
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/pkg/errors"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	cosigntypes "github.com/sigstore/cosign/v2/pkg/types"
+	"knative.dev/pkg/logging"
+)
+
+// emptyConfigMediaType is the media type of the empty config of the referrer
+// manifests written by Migrate and StoreSBOM.
+const emptyConfigMediaType = "application/vnd.oci.empty.v1+json"
+
+// Migrate copies the attestations attached to the given artifact through the
+// legacy .att tag to referrers of the artifact, one referrer per attestation,
+// without re-signing them: the envelope layers and their annotations, holding
+// the signatures and certificate chains, are reused as is. Attestations that
+// are already referrers are skipped, so migrating again is safe. If the
+// storer was configured with WithDeleteMigratedTags, the .att manifest is
+// deleted once all of its attestations are referrers. It returns the number
+// of referrers written.
+func (s *AttestationStorer) Migrate(ctx context.Context, artifact name.Digest) (int, error) {
+	if hs := s.hostStorer(artifact); hs != nil {
+		return hs.Migrate(ctx, artifact)
+	}
+	if s.localLayout != "" {
+		return 0, errors.New("attestations in OCI layouts cannot be migrated to referrers")
+	}
+	repo := s.targetRepository(artifact)
+
+	se, err := s.storedEntity(ctx, artifact, repo)
+	if err != nil {
+		return 0, err
+	}
+	atts, err := se.Attestations()
+	if err != nil {
+		return 0, errors.Wrap(err, "getting attestations")
+	}
+	sigs, err := atts.Get()
+	if err != nil {
+		return 0, errors.Wrap(err, "getting attestations")
+	}
+	if len(sigs) == 0 {
+		return 0, nil
+	}
+	for _, sig := range sigs {
+		ann, err := sig.Annotations()
+		if err != nil {
+			return 0, errors.Wrap(err, "reading attestation annotations")
+		}
+		// Referrers are read one layer at a time, so the chunks of a split
+		// attestation could not be reassembled.
+		if _, ok := ann[splitGroupAnnotation]; ok {
+			return 0, errors.Errorf("attestations of %s are split across layers and cannot be migrated to referrers", artifact.String())
+		}
+	}
+
+	d := repo.Digest(artifact.DigestStr())
+	referred, err := s.listReferrers(ctx, d)
+	if err != nil {
+		return 0, err
+	}
+	migrated := map[string]bool{}
+	for _, info := range referred {
+		migrated[info.Digest] = true
+	}
+	subject, err := remote.Head(artifact, s.remoteOptions(ctx)...)
+	if err != nil {
+		return 0, errors.Wrapf(err, "fetching %s", artifact.String())
+	}
+
+	written := 0
+	for _, sig := range sigs {
+		ld, err := sig.Digest()
+		if err != nil {
+			return written, errors.Wrap(err, "reading attestation digest")
+		}
+		if migrated[ld.String()] {
+			continue
+		}
+		if err := s.writeReferrer(ctx, d, subject, sig); err != nil {
+			return written, errors.Wrapf(err, "migrating attestation %s of %s", ld, artifact.String())
+		}
+		migrated[ld.String()] = true
+		written++
+	}
+
+	if s.deleteMigratedTags {
+		tag, err := ociremote.AttestationTag(artifact, s.tagOptions(repo)...)
+		if err != nil {
+			return written, err
+		}
+		if err := s.deleteAttached(ctx, tag, atts); err != nil {
+			return written, errors.Wrapf(err, "deleting attestations of %s", artifact.String())
+		}
+	}
+	return written, nil
+}
+
+// referrerManifest is an image manifest with the artifactType field of OCI
+// 1.1, which go-containerregistry does not model.
+type referrerManifest struct {
+	v1.Manifest
+	ArtifactType string `json:"artifactType,omitempty"`
+}
+
+// RawManifest implements remote.Taggable.
+func (m *referrerManifest) RawManifest() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// MediaType sets the content type the manifest is pushed with.
+func (m *referrerManifest) MediaType() (types.MediaType, error) {
+	return m.Manifest.MediaType, nil
+}
+
+// writeReferrer writes the attestation layer sig to a referrer of the
+// artifact d, described by subject. The layer is uploaded as is and keeps its
+// annotations. The referrer is created at the creation time of the
+// attestation, if recorded.
+func (s *AttestationStorer) writeReferrer(ctx context.Context, d name.Digest, subject *v1.Descriptor, sig oci.Signature) error {
+	ann, err := sig.Annotations()
+	if err != nil {
+		return err
+	}
+	created := ann[createdAnnotation]
+	if created == "" {
+		created = s.now().UTC().Format(time.RFC3339)
+	}
+	_, err = s.putReferrer(ctx, d, subject, sig, ann, cosigntypes.IntotoPayloadType, created)
+	return err
+}
+
+// putReferrer writes a referrer of the artifact d, described by subject,
+// holding the single layer l with annotations ann, with an empty config. It
+// returns the reference of the referrer manifest.
+func (b *baseStorer) putReferrer(ctx context.Context, d name.Digest, subject *v1.Descriptor, l v1.Layer, ann map[string]string, artifactType, created string) (name.Digest, error) {
+	mt, err := l.MediaType()
+	if err != nil {
+		return name.Digest{}, err
+	}
+	ld, err := l.Digest()
+	if err != nil {
+		return name.Digest{}, err
+	}
+	size, err := l.Size()
+	if err != nil {
+		return name.Digest{}, err
+	}
+	config := static.NewLayer([]byte("{}"), emptyConfigMediaType)
+	cd, err := config.Digest()
+	if err != nil {
+		return name.Digest{}, err
+	}
+	csize, err := config.Size()
+	if err != nil {
+		return name.Digest{}, err
+	}
+
+	m := &referrerManifest{
+		Manifest: v1.Manifest{
+			SchemaVersion: 2,
+			MediaType:     types.OCIManifestSchema1,
+			Config: v1.Descriptor{
+				MediaType: emptyConfigMediaType,
+				Digest:    cd,
+				Size:      csize,
+			},
+			Layers: []v1.Descriptor{{
+				MediaType:   mt,
+				Digest:      ld,
+				Size:        size,
+				Annotations: ann,
+			}},
+			Subject: &v1.Descriptor{
+				MediaType: subject.MediaType,
+				Digest:    subject.Digest,
+				Size:      subject.Size,
+			},
+			Annotations: map[string]string{
+				createdAnnotation: created,
+			},
+		},
+		ArtifactType: artifactType,
+	}
+	raw, err := m.RawManifest()
+	if err != nil {
+		return name.Digest{}, err
+	}
+	md, _, err := v1.SHA256(bytes.NewReader(raw))
+	if err != nil {
+		return name.Digest{}, err
+	}
+	ref := d.Context().Digest(md.String())
+	if b.dryRun {
+		logging.FromContext(ctx).Infof("Dry run: would write layer %s to referrer %s", ld, ref.String())
+		return ref, nil
+	}
+	return ref, b.retryWrite(ctx, ref.String(), func() error {
+		for _, l := range []v1.Layer{config, l} {
+			if err := remote.WriteLayer(d.Context(), l, b.remoteOptions(ctx)...); err != nil {
+				return err
+			}
+		}
+		return remote.Put(ref, m, b.remoteOptions(ctx)...)
+	})
+}
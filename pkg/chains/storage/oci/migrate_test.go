@@ -0,0 +1,118 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestAttestationStorer_Migrate(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	ref := newReferrersTestRegistry(t)
+	storer, err := NewAttestationStorer()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	storeTestAttestations(t, storer, ref, "https://example.com/a", "https://example.com/b")
+
+	se, err := ociremote.SignedEntity(ref)
+	if err != nil {
+		t.Fatalf("failed to get signed entity: %v", err)
+	}
+	atts, err := se.Attestations()
+	if err != nil {
+		t.Fatalf("failed to get attestations: %v", err)
+	}
+	legacy, err := atts.Manifest()
+	if err != nil {
+		t.Fatalf("failed to get the .att manifest: %v", err)
+	}
+
+	n, err := storer.Migrate(ctx, ref)
+	if err != nil {
+		t.Fatalf("error during Migrate(): %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Migrate() = %d, want 2", n)
+	}
+
+	// The referrers hold the legacy layers unchanged, annotations included.
+	idx, err := ociremote.Referrers(ref, "")
+	if err != nil {
+		t.Fatalf("failed to list referrers: %v", err)
+	}
+	var got []v1.Descriptor
+	for _, desc := range idx.Manifests {
+		img, err := remote.Image(ref.Context().Digest(desc.Digest.String()))
+		if err != nil {
+			t.Fatalf("failed to fetch referrer: %v", err)
+		}
+		m, err := img.Manifest()
+		if err != nil {
+			t.Fatalf("failed to fetch referrer: %v", err)
+		}
+		got = append(got, m.Layers...)
+	}
+	want := append([]v1.Descriptor(nil), legacy.Layers...)
+	for _, ds := range [][]v1.Descriptor{got, want} {
+		sort.Slice(ds, func(i, j int) bool { return ds[i].Digest.String() < ds[j].Digest.String() })
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected referrer layers (-want +got):\n%s", diff)
+	}
+
+	// Migrating again writes nothing.
+	if n, err := storer.Migrate(ctx, ref); err != nil || n != 0 {
+		t.Errorf("Migrate() again = %d, %v, want 0, nil", n, err)
+	}
+
+	deleting, err := NewAttestationStorer(WithDeleteMigratedTags())
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	if n, err := deleting.Migrate(ctx, ref); err != nil || n != 0 {
+		t.Errorf("Migrate() with deletion = %d, %v, want 0, nil", n, err)
+	}
+	tag, err := ociremote.AttestationTag(ref)
+	if err != nil {
+		t.Fatalf("failed to get the .att tag: %v", err)
+	}
+	if _, err := remote.Head(tag); err == nil {
+		t.Errorf("expected %s to be deleted", tag)
+	}
+	types := listedPredicateTypes(t, storer, ref)
+	sort.Strings(types)
+	if diff := cmp.Diff([]string{"https://example.com/a", "https://example.com/b"}, types); diff != "" {
+		t.Errorf("unexpected attestations after migration (-want +got):\n%s", diff)
+	}
+}
+
+func TestAttestationStorer_Migrate_Nothing(t *testing.T) {
+	ref := newReferrersTestRegistry(t)
+	storer, err := NewAttestationStorer(WithDeleteMigratedTags())
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	if n, err := storer.Migrate(logtesting.TestContextWithLogger(t), ref); err != nil || n != 0 {
+		t.Errorf("Migrate() = %d, %v, want 0, nil", n, err)
+	}
+}
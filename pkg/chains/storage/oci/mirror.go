@@ -0,0 +1,43 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+)
+
+// MirrorSink is an external metadata store (e.g. Grafeas) that receives
+// statements after they have been stored in the OCI registry.
+type MirrorSink interface {
+	api.Storer[name.Digest, *intoto.Statement]
+}
+
+// MirrorFailurePolicy controls how a failure to mirror a statement is handled.
+type MirrorFailurePolicy int
+
+const (
+	// MirrorFailureWarn logs mirror failures without failing the store.
+	MirrorFailureWarn MirrorFailurePolicy = iota
+	// MirrorFailureFail returns mirror failures as store errors.
+	MirrorFailureFail
+)
+
+// metadataMirror pairs a MirrorSink with its failure policy.
+type metadataMirror struct {
+	sink   MirrorSink
+	policy MirrorFailurePolicy
+}
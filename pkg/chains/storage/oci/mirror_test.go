@@ -0,0 +1,111 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/pkg/errors"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+type fakeMirrorSink struct {
+	err      error
+	requests []*api.StoreRequest[name.Digest, *intoto.Statement]
+}
+
+func (f *fakeMirrorSink) Store(_ context.Context, req *api.StoreRequest[name.Digest, *intoto.Statement]) (*api.StoreResponse, error) {
+	f.requests = append(f.requests, req)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &api.StoreResponse{}, nil
+}
+
+func TestAttestationStorer_MetadataMirror(t *testing.T) {
+	mirrorErr := errors.New("mirror unavailable")
+	tests := []struct {
+		name    string
+		sinkErr error
+		policy  MirrorFailurePolicy
+		wantErr bool
+	}{
+		{
+			name:   "mirrored",
+			policy: MirrorFailureFail,
+		},
+		{
+			name:    "failure warns",
+			sinkErr: mirrorErr,
+			policy:  MirrorFailureWarn,
+		},
+		{
+			name:    "failure fails",
+			sinkErr: mirrorErr,
+			policy:  MirrorFailureFail,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registryName := newTestRegistry(t, nil)
+			ref := pushRandomImage(t, registryName)
+
+			sink := &fakeMirrorSink{err: tt.sinkErr}
+			storer, err := NewAttestationStorer(
+				WithTargetRepository(ref.Repository),
+				WithMetadataMirror(sink, tt.policy),
+			)
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+
+			statement := &intoto.Statement{
+				Subject: []*intoto.ResourceDescriptor{subjectFromDigest(ref)},
+			}
+			ctx := logtesting.TestContextWithLogger(t)
+			_, err = storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+				Artifact: ref,
+				Payload:  statement,
+				Bundle:   &signing.Bundle{},
+			})
+			if tt.wantErr {
+				if !errors.Is(err, mirrorErr) {
+					t.Errorf("Store() error = %v, want %v", err, mirrorErr)
+				}
+			} else if err != nil {
+				t.Fatalf("error during Store(): %v", err)
+			}
+
+			if len(sink.requests) != 1 {
+				t.Fatalf("got %d mirrored requests, want 1", len(sink.requests))
+			}
+			if got := sink.requests[0].Payload; got != statement {
+				t.Errorf("mirrored statement = %v, want %v", got, statement)
+			}
+		})
+	}
+}
+
+func TestAttestationStorer_NilMetadataMirror(t *testing.T) {
+	if _, err := NewAttestationStorer(WithMetadataMirror(nil, MirrorFailureWarn)); err == nil {
+		t.Error("expected an error for a nil mirror sink")
+	}
+}
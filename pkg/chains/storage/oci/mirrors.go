@@ -0,0 +1,155 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+	stderrors "errors"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/pkg/errors"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	"knative.dev/pkg/logging"
+)
+
+// MirrorRepository is a repository that signatures and attestations are
+// written to besides the target repository, configured with WithMirrors.
+type MirrorRepository struct {
+	// Repository is the repository to write to.
+	Repository name.Repository
+	// RemoteOptions are added to the storer's remote options for writes to
+	// the repository, e.g. to authenticate with credentials of its own.
+	RemoteOptions []remote.Option
+}
+
+// MirrorMode controls when the mirror repositories are written to.
+type MirrorMode int
+
+const (
+	// MirrorModeFailover writes to the mirrors in order only if the writes
+	// to the target repository and to the previous mirrors failed.
+	MirrorModeFailover MirrorMode = iota
+	// MirrorModeAll writes to the target repository and to every mirror, and
+	// fails if any of the writes failed.
+	MirrorModeAll
+)
+
+// replica stores a request in a single repository.
+type replica struct {
+	repo  string
+	store func(context.Context) (*api.StoreResponse, error)
+}
+
+// storeReplicas stores a request in each of the replicas, in order, as
+// required by mode. The response of the first successful store is returned,
+// with the repositories of all successful stores.
+func storeReplicas(ctx context.Context, mode MirrorMode, replicas []replica) (*api.StoreResponse, error) {
+	logger := logging.FromContext(ctx)
+	var (
+		resp *api.StoreResponse
+		errs []error
+	)
+	for _, r := range replicas {
+		rresp, err := r.store(ctx)
+		if err != nil {
+			logger.Warnf("Failed to store in %s: %v", r.repo, err)
+			errs = append(errs, errors.Wrapf(err, "storing in %s", r.repo))
+			continue
+		}
+		if resp == nil {
+			resp = rresp
+		}
+		resp.Repositories = append(resp.Repositories, r.repo)
+		if mode == MirrorModeFailover {
+			return resp, nil
+		}
+	}
+	return resp, stderrors.Join(errs...)
+}
+
+// withoutMirrors returns the options other than mirrors, so that the storers
+// derived for each mirror do not derive storers of their own.
+func withoutMirrors[T any](opts []T) []T {
+	out := make([]T, 0, len(opts))
+	for _, o := range opts {
+		if _, ok := any(o).(*mirrorsOption); ok {
+			continue
+		}
+		out = append(out, o)
+	}
+	return out
+}
+
+// withoutMetadataMirror returns the options other than the metadata mirror,
+// so that the storers derived for each mirror do not forward the statements
+// the storer forwards once.
+func withoutMetadataMirror[T any](opts []T) []T {
+	out := make([]T, 0, len(opts))
+	for _, o := range opts {
+		if _, ok := any(o).(*metadataMirrorOption); ok {
+			continue
+		}
+		out = append(out, o)
+	}
+	return out
+}
+
+// mirrorOptions returns the options of the storer derived for the mirror m.
+func mirrorOptions[T any](opts []T, m MirrorRepository) []T {
+	out := withoutMetadataMirror(withoutMirrors(withoutHostPolicies(opts)))
+	for _, o := range []Option{WithTargetRepository(m.Repository), WithRemoteOptions(m.RemoteOptions...)} {
+		out = append(out, o.(T))
+	}
+	return out
+}
+
+// storeWithMirrors stores req with primary and, as configured with
+// WithMirrors, in the mirror repositories.
+func (s *AttestationStorer) storeWithMirrors(ctx context.Context, req *api.StoreRequest[name.Digest, *intoto.Statement], primary func(context.Context) (*api.StoreResponse, error)) (*api.StoreResponse, error) {
+	if len(s.mirrorStorers) == 0 {
+		return primary(ctx)
+	}
+	replicas := []replica{{repo: s.targetRepository(req.Artifact).String(), store: primary}}
+	for _, ms := range s.mirrorStorers {
+		replicas = append(replicas, replica{
+			repo: ms.targetRepository(req.Artifact).String(),
+			store: func(ctx context.Context) (*api.StoreResponse, error) {
+				return ms.store(ctx, req)
+			},
+		})
+	}
+	return storeReplicas(ctx, s.mirrorMode, replicas)
+}
+
+// storeWithMirrors stores req with primary and, as configured with
+// WithMirrors, in the mirror repositories.
+func (s *SimpleStorer) storeWithMirrors(ctx context.Context, req *api.StoreRequest[name.Digest, simple.SimpleContainerImage], primary func(context.Context) (*api.StoreResponse, error)) (*api.StoreResponse, error) {
+	if len(s.mirrorStorers) == 0 {
+		return primary(ctx)
+	}
+	replicas := []replica{{repo: s.targetRepository(req.Artifact).String(), store: primary}}
+	for _, ms := range s.mirrorStorers {
+		replicas = append(replicas, replica{
+			repo: ms.targetRepository(req.Artifact).String(),
+			store: func(ctx context.Context) (*api.StoreResponse, error) {
+				return ms.store(ctx, req)
+			},
+		})
+	}
+	return storeReplicas(ctx, s.mirrorMode, replicas)
+}
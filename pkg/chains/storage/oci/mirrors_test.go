@@ -0,0 +1,192 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestWithMirrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        MirrorMode
+		primaryDown bool
+		wantPrimary bool
+		wantMirror  bool
+		wantErr     bool
+	}{
+		{
+			name:        "failover with the primary up",
+			mode:        MirrorModeFailover,
+			wantPrimary: true,
+		},
+		{
+			name:        "failover with the primary down",
+			mode:        MirrorModeFailover,
+			primaryDown: true,
+			wantMirror:  true,
+		},
+		{
+			name:        "all",
+			mode:        MirrorModeAll,
+			wantPrimary: true,
+			wantMirror:  true,
+		},
+		{
+			name:        "all with the primary down",
+			mode:        MirrorModeAll,
+			primaryDown: true,
+			wantMirror:  true,
+			wantErr:     true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := logtesting.TestContextWithLogger(t)
+			var down atomic.Bool
+			primaryRegistry := newTestRegistry(t, func(h http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if down.Load() {
+						http.Error(w, "unavailable", http.StatusServiceUnavailable)
+						return
+					}
+					h.ServeHTTP(w, r)
+				})
+			})
+			ref := pushRandomImage(t, newTestRegistry(t, nil))
+			primary, err := name.NewRepository(primaryRegistry + "/primary")
+			if err != nil {
+				t.Fatalf("failed to parse repository: %v", err)
+			}
+			mirror, err := name.NewRepository(newTestRegistry(t, nil) + "/mirror")
+			if err != nil {
+				t.Fatalf("failed to parse repository: %v", err)
+			}
+			down.Store(tc.primaryDown)
+
+			opts := []Option{
+				WithTargetRepository(primary),
+				WithMirrors([]MirrorRepository{{Repository: mirror, RemoteOptions: []remote.Option{remote.WithUserAgent("mirror")}}}, tc.mode),
+			}
+			var wantRepos []string
+			if tc.wantPrimary {
+				wantRepos = append(wantRepos, primary.String())
+			}
+			if tc.wantMirror {
+				wantRepos = append(wantRepos, mirror.String())
+			}
+
+			as, err := NewAttestationStorer(opts[0], opts[1])
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+			statement, payload := newTestStatement(t, ref, "https://slsa.dev/provenance/v1")
+			attResp, err := as.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+				Artifact: ref,
+				Payload:  statement,
+				Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+			})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("attestation Store() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			ss, err := NewSimpleStorerFromConfig(opts[0], opts[1])
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+			sigResp, err := ss.Store(ctx, &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+				Artifact: ref,
+				Payload:  simple.NewSimpleStruct(ref),
+				Bundle:   &signing.Bundle{Content: []byte("{}"), Signature: []byte("signature")},
+			})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("signature Store() error = %v, wantErr %v", err, tc.wantErr)
+			}
+
+			for what, resp := range map[string]*api.StoreResponse{"attestation": attResp, "signature": sigResp} {
+				if resp == nil || !slices.Equal(resp.Repositories, wantRepos) {
+					t.Errorf("%s StoreResponse = %+v, want repositories %v", what, resp, wantRepos)
+				}
+			}
+			down.Store(false)
+			for repo, want := range map[name.Repository]bool{primary: tc.wantPrimary, mirror: tc.wantMirror} {
+				for _, suffix := range []string{".att", ".sig"} {
+					tag := repo.Tag(strings.ReplaceAll(ref.DigestStr(), ":", "-") + suffix)
+					_, err := remote.Head(tag)
+					if got := err == nil; got != want {
+						t.Errorf("%s exists = %v, want %v", tag, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestWithMirrors_MetadataMirror(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	ref := pushRandomImage(t, newTestRegistry(t, nil))
+	mirror, err := name.NewRepository(newTestRegistry(t, nil) + "/mirror")
+	if err != nil {
+		t.Fatalf("failed to parse repository: %v", err)
+	}
+	sink := &fakeMirrorSink{}
+	as, err := NewAttestationStorer(
+		WithMirrors([]MirrorRepository{{Repository: mirror}}, MirrorModeAll),
+		WithMetadataMirror(sink, MirrorFailureFail))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	statement, payload := newTestStatement(t, ref, "https://slsa.dev/provenance/v1")
+	resp, err := as.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  statement,
+		Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+	})
+	if err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+	if len(resp.Repositories) != 2 {
+		t.Errorf("stored in %v, want the target repository and the mirror", resp.Repositories)
+	}
+	if got := len(sink.requests); got != 1 {
+		t.Errorf("the metadata mirror got %d requests, want 1", got)
+	}
+}
+
+func TestWithMirrors_Invalid(t *testing.T) {
+	mirror, err := name.NewRepository("registry.example.com/mirror")
+	if err != nil {
+		t.Fatalf("failed to parse repository: %v", err)
+	}
+	for _, o := range []Option{
+		WithMirrors(nil, MirrorModeFailover),
+		WithMirrors([]MirrorRepository{{Repository: mirror}}, MirrorMode(42)),
+	} {
+		if _, err := NewAttestationStorer(o); err == nil {
+			t.Errorf("expected an error for %+v", o)
+		}
+	}
+}
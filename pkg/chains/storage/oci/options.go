@@ -14,7 +14,33 @@
 
 package oci
 
-import "github.com/google/go-containerregistry/pkg/name"
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	ecr "github.com/awslabs/amazon-ecr-credential-helper/ecr-login"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/google"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sigstore/cosign/v2/pkg/cosign/bundle"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"golang.org/x/sync/semaphore"
+	"k8s.io/utils/clock"
+)
 
 // Option provides a config option compatible with all OCI storers.
 type Option interface {
@@ -52,3 +78,1682 @@ func (o *targetRepoOption) applySimpleStorer(s *SimpleStorer) error {
 	s.repo = &o.repo
 	return nil
 }
+
+// WithProactiveRateLimiting configures the storers to honor the RateLimit-Remaining
+// and RateLimit-Reset headers advertised by registries, delaying subsequent
+// requests when the remaining quota runs low instead of waiting to be rejected.
+func WithProactiveRateLimiting(enabled bool) Option {
+	return &proactiveRateLimitingOption{
+		enabled: enabled,
+	}
+}
+
+type proactiveRateLimitingOption struct {
+	enabled bool
+}
+
+func (o *proactiveRateLimitingOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *proactiveRateLimitingOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *proactiveRateLimitingOption) apply(b *baseStorer) error {
+	if o.enabled {
+		b.rateLimiter = newRateLimiter()
+	} else {
+		b.rateLimiter = nil
+	}
+	return nil
+}
+
+// WithEquivalentSubjects configures additional digest references (e.g. mirrors or
+// retags of the artifact) that the subjects of the stored Statement must
+// record. MergeEquivalentSubjects adds them to a Statement before it is signed;
+// subjects already present, as determined by WithSubjectMatchBy, are not
+// duplicated. Stores of Statements that do not record them, or whose DSSE
+// envelope cannot be decoded, are rejected.
+func WithEquivalentSubjects(digests []string) AttestationStorerOption {
+	return &equivalentSubjectsOption{
+		digests: digests,
+	}
+}
+
+type equivalentSubjectsOption struct {
+	digests []string
+}
+
+func (o *equivalentSubjectsOption) applyAttestationStorer(s *AttestationStorer) error {
+	for _, d := range o.digests {
+		ref, err := parseDigest(d)
+		if err != nil {
+			return errors.Wrapf(err, "parsing equivalent subject %q", d)
+		}
+		s.equivalentSubjects = append(s.equivalentSubjects, subjectFromDigest(ref))
+	}
+	return nil
+}
+
+// WithEventSampling configures the fraction, between 0 and 1, of successful stores
+// that emit their success events (e.g. the "successfully uploaded" log line).
+// Failures are always reported regardless of the sampling rate.
+func WithEventSampling(rate float64) Option {
+	return &eventSamplingOption{
+		rate: rate,
+	}
+}
+
+type eventSamplingOption struct {
+	rate float64
+}
+
+func (o *eventSamplingOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *eventSamplingOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *eventSamplingOption) apply(b *baseStorer) error {
+	if o.rate < 0 || o.rate > 1 {
+		return errors.Errorf("event sampling rate must be between 0 and 1, got %v", o.rate)
+	}
+	rate := o.rate
+	b.eventSampleRate = &rate
+	return nil
+}
+
+// WithMetadataMirror configures a sink that the request is forwarded to after
+// the attestation has been successfully stored in the OCI registry. The policy
+// determines whether a mirror failure fails the store or is only logged.
+func WithMetadataMirror(sink MirrorSink, policy MirrorFailurePolicy) AttestationStorerOption {
+	return &metadataMirrorOption{
+		mirror: metadataMirror{
+			sink:   sink,
+			policy: policy,
+		},
+	}
+}
+
+type metadataMirrorOption struct {
+	mirror metadataMirror
+}
+
+func (o *metadataMirrorOption) applyAttestationStorer(s *AttestationStorer) error {
+	if o.mirror.sink == nil {
+		return errors.New("metadata mirror sink must not be nil")
+	}
+	s.mirror = &o.mirror
+	return nil
+}
+
+// WithDurableRetryQueue configures a directory where stores that failed
+// transiently are persisted so that they survive controller restarts. Persisted
+// stores are re-attempted with the same payload and signature by
+// ReplayRetryQueue. Stores the registry rejected are not persisted.
+func WithDurableRetryQueue(path string) Option {
+	return &durableRetryQueueOption{
+		path: path,
+	}
+}
+
+type durableRetryQueueOption struct {
+	path string
+}
+
+func (o *durableRetryQueueOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *durableRetryQueueOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *durableRetryQueueOption) apply(b *baseStorer) error {
+	q, err := newRetryQueue(o.path)
+	if err != nil {
+		return err
+	}
+	b.retryQueue = q
+	return nil
+}
+
+// WithSubjectMatchBy configures when two subjects are considered the same when
+// merging subjects. Defaults to SubjectMatchDigest.
+func WithSubjectMatchBy(mode SubjectMatchMode) AttestationStorerOption {
+	return &subjectMatchOption{
+		mode: mode,
+	}
+}
+
+type subjectMatchOption struct {
+	mode SubjectMatchMode
+}
+
+func (o *subjectMatchOption) applyAttestationStorer(s *AttestationStorer) error {
+	switch o.mode {
+	case SubjectMatchDigest, SubjectMatchNameAndDigest:
+		s.subjectMatch = o.mode
+		return nil
+	default:
+		return errors.Errorf("unknown subject match mode %d", o.mode)
+	}
+}
+
+// WithTransparencyIndex configures the storer to record the digest of every
+// stored attestation in an append-only Merkle tree index, stored in the target
+// repository under TransparencyIndexTag. The tree head of the index is signed
+// with the signer set with WithTransparencyIndexSigner, which is required.
+// Consumers can use inclusion proofs from the index, once its signature is
+// checked by FetchTransparencyIndex, to detect attestations that were removed.
+func WithTransparencyIndex(enabled bool) AttestationStorerOption {
+	return &transparencyIndexOption{
+		enabled: enabled,
+	}
+}
+
+type transparencyIndexOption struct {
+	enabled bool
+}
+
+func (o *transparencyIndexOption) applyAttestationStorer(s *AttestationStorer) error {
+	if o.enabled {
+		s.transparencyIndex = &transparencyIndexer{}
+	} else {
+		s.transparencyIndex = nil
+	}
+	return nil
+}
+
+// WithTransparencyIndexSigner sets the signer of the tree heads of the index
+// maintained with WithTransparencyIndex. The indexes read before an update are
+// verified with it too.
+func WithTransparencyIndexSigner(signer signature.SignerVerifier) AttestationStorerOption {
+	return &transparencyIndexSignerOption{
+		signer: signer,
+	}
+}
+
+type transparencyIndexSignerOption struct {
+	signer signature.SignerVerifier
+}
+
+func (o *transparencyIndexSignerOption) applyAttestationStorer(s *AttestationStorer) error {
+	if o.signer == nil {
+		return errors.New("transparency index signer must not be nil")
+	}
+	s.transparencyIndexSigner = o.signer
+	return nil
+}
+
+// WithEntityFetchRetry configures retries of the fetch of the artifact and the
+// signatures and attestations already attached to it, independently of any
+// retries of the writes. Transient errors are retried up to the given total
+// number of attempts, waiting backoff before the first retry and doubling the
+// wait after each one.
+func WithEntityFetchRetry(attempts int, backoff time.Duration) Option {
+	return &entityFetchRetryOption{
+		retry: entityFetchRetry{
+			attempts: attempts,
+			backoff:  backoff,
+		},
+	}
+}
+
+type entityFetchRetryOption struct {
+	retry entityFetchRetry
+}
+
+func (o *entityFetchRetryOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *entityFetchRetryOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *entityFetchRetryOption) apply(b *baseStorer) error {
+	if o.retry.attempts < 1 {
+		return errors.Errorf("entity fetch attempts must be at least 1, got %d", o.retry.attempts)
+	}
+	if o.retry.backoff < 0 {
+		return errors.Errorf("entity fetch backoff must not be negative, got %s", o.retry.backoff)
+	}
+	retry := o.retry
+	b.entityFetchRetry = &retry
+	return nil
+}
+
+// WithCreationTime configures the created timestamp recorded in the signature
+// and attestation manifests, so that storing identical inputs produces
+// identical manifests. Use time.Unix(0, 0) to record the epoch.
+func WithCreationTime(t time.Time) Option {
+	return &creationTimeOption{
+		t: t,
+	}
+}
+
+type creationTimeOption struct {
+	t time.Time
+}
+
+func (o *creationTimeOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *creationTimeOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *creationTimeOption) apply(b *baseStorer) error {
+	t := o.t.UTC()
+	b.creationTime = &t
+	return nil
+}
+
+// WithVerifyAnnotations configures the storers to re-read the manifest after
+// writing it and fail the store if the registry did not keep the annotations
+// of the written signature or attestation.
+func WithVerifyAnnotations(enabled bool) Option {
+	return &verifyAnnotationsOption{
+		enabled: enabled,
+	}
+}
+
+type verifyAnnotationsOption struct {
+	enabled bool
+}
+
+func (o *verifyAnnotationsOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *verifyAnnotationsOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *verifyAnnotationsOption) apply(b *baseStorer) error {
+	b.verifyAnnotations = o.enabled
+	return nil
+}
+
+// WithHostPolicy configures options that apply to subjects on specific registry
+// hosts, e.g. a different target repository or rate limiting for a single
+// registry. Subjects on hosts that are not listed use the storer's own options.
+func WithHostPolicy(policies map[string]HostPolicy) Option {
+	return &hostPolicyOption{
+		policies: policies,
+	}
+}
+
+type hostPolicyOption struct {
+	policies map[string]HostPolicy
+}
+
+func (o *hostPolicyOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *hostPolicyOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *hostPolicyOption) apply(b *baseStorer) error {
+	if b.hostPolicies == nil {
+		b.hostPolicies = map[string]HostPolicy{}
+	}
+	for host, p := range o.policies {
+		reg, err := name.NewRegistry(host)
+		if err != nil {
+			return errors.Wrapf(err, "parsing host policy registry %q", host)
+		}
+		b.hostPolicies[reg.RegistryStr()] = p
+	}
+	return nil
+}
+
+// WithSkipIfExists configures the storer to skip the upload if an attestation
+// with the same DSSE payload and predicate type is already attached to the
+// artifact. The StoreResponse of a skipped upload has AlreadyExists set.
+func WithSkipIfExists() AttestationStorerOption {
+	return &skipIfExistsOption{}
+}
+
+type skipIfExistsOption struct{}
+
+func (o *skipIfExistsOption) applyAttestationStorer(s *AttestationStorer) error {
+	s.skipIfExists = true
+	return nil
+}
+
+// WithPayloadSplitSize configures the size in bytes above which attestation
+// payloads are split across multiple layers, for registries that reject large
+// blobs. The chunks are annotated with their order so that Retrieve can
+// reassemble the payload.
+func WithPayloadSplitSize(size int64) AttestationStorerOption {
+	return &payloadSplitSizeOption{
+		size: size,
+	}
+}
+
+type payloadSplitSizeOption struct {
+	size int64
+}
+
+func (o *payloadSplitSizeOption) applyAttestationStorer(s *AttestationStorer) error {
+	if o.size <= 0 {
+		return errors.Errorf("payload split size must be positive, got %d", o.size)
+	}
+	s.payloadSplitSize = o.size
+	return nil
+}
+
+// WithRetry configures retries of the writes of signatures and attestations,
+// independently of the retries of the signed entity fetch. Transient errors,
+// such as 429 and 5xx responses, are retried up to maxAttempts total attempts
+// with an exponential backoff starting at baseDelay, with jitter, up to the
+// maximum set with WithMaxRetryDelay. Client
+// errors such as 400, 401 and 403 are not retried. A longer wait requested by
+// the registry through a Retry-After header is honored, up to the maximum set
+// with WithMaxRetryAfter.
+func WithRetry(maxAttempts int, baseDelay time.Duration) Option {
+	return &retryOption{
+		maxAttempts: maxAttempts,
+		baseDelay:   baseDelay,
+	}
+}
+
+type retryOption struct {
+	maxAttempts int
+	baseDelay   time.Duration
+}
+
+func (o *retryOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *retryOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *retryOption) apply(b *baseStorer) error {
+	if o.maxAttempts < 1 {
+		return errors.Errorf("write attempts must be at least 1, got %d", o.maxAttempts)
+	}
+	if o.baseDelay < 0 {
+		return errors.Errorf("write retry delay must not be negative, got %s", o.baseDelay)
+	}
+	b.writeRetry = newWriteRetry(o.maxAttempts, o.baseDelay)
+	return nil
+}
+
+// WithMaxRetryAfter caps the wait requested by registries through the
+// Retry-After header when retrying writes configured with WithRetry.
+// Defaults to one minute.
+func WithMaxRetryAfter(d time.Duration) Option {
+	return &maxRetryAfterOption{
+		max: d,
+	}
+}
+
+type maxRetryAfterOption struct {
+	max time.Duration
+}
+
+func (o *maxRetryAfterOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *maxRetryAfterOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *maxRetryAfterOption) apply(b *baseStorer) error {
+	if o.max <= 0 {
+		return errors.Errorf("maximum Retry-After wait must be positive, got %s", o.max)
+	}
+	b.maxRetryAfter = o.max
+	return nil
+}
+
+// WithMaxRetryDelay caps the exponential delay between the write attempts
+// configured with WithRetry. Defaults to one minute.
+func WithMaxRetryDelay(d time.Duration) Option {
+	return &maxRetryDelayOption{
+		max: d,
+	}
+}
+
+type maxRetryDelayOption struct {
+	max time.Duration
+}
+
+func (o *maxRetryDelayOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *maxRetryDelayOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *maxRetryDelayOption) apply(b *baseStorer) error {
+	if o.max <= 0 {
+		return errors.Errorf("maximum write retry delay must be positive, got %s", o.max)
+	}
+	b.maxRetryDelay = o.max
+	return nil
+}
+
+// WithStoreTimeout bounds the duration of each store, including the lookup of
+// the signed entity and the writes to the registry. Stores interrupted by the
+// timeout return a *TimeoutError.
+func WithStoreTimeout(d time.Duration) Option {
+	return &storeTimeoutOption{
+		timeout: d,
+	}
+}
+
+type storeTimeoutOption struct {
+	timeout time.Duration
+}
+
+func (o *storeTimeoutOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *storeTimeoutOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *storeTimeoutOption) apply(b *baseStorer) error {
+	if o.timeout <= 0 {
+		return errors.Errorf("store timeout must be positive, got %s", o.timeout)
+	}
+	b.storeTimeout = o.timeout
+	return nil
+}
+
+// WithRegistryLimits validates signatures and attestations against the size
+// limits of the target registry before they are written. Limits advertised by
+// the registry take precedence over the given static limits, which apply to
+// registries that do not advertise them. Stores exceeding a limit return a
+// *LimitError.
+func WithRegistryLimits(static RegistryLimits) Option {
+	return &registryLimitsOption{
+		static: static,
+	}
+}
+
+type registryLimitsOption struct {
+	static RegistryLimits
+}
+
+func (o *registryLimitsOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *registryLimitsOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *registryLimitsOption) apply(b *baseStorer) error {
+	if o.static.ManifestSize < 0 || o.static.BlobSize < 0 || o.static.AnnotationSize < 0 {
+		return errors.Errorf("registry limits must not be negative, got %+v", o.static)
+	}
+	b.registryLimits = newRegistryLimits(o.static)
+	return nil
+}
+
+// WithMetrics records metrics of the store operations with reg: the number
+// of stores by payload format and outcome, their latency and the number of
+// retried registry requests. Storers may share reg.
+func WithMetrics(reg prometheus.Registerer) Option {
+	return &metricsOption{
+		reg: reg,
+	}
+}
+
+type metricsOption struct {
+	reg prometheus.Registerer
+}
+
+func (o *metricsOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer, attestationFormat)
+}
+
+func (o *metricsOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer, signatureFormat)
+}
+
+func (o *metricsOption) apply(b *baseStorer, format string) error {
+	if o.reg == nil {
+		return errors.New("metrics registerer must not be nil")
+	}
+	m, err := newStoreMetrics(o.reg)
+	if err != nil {
+		return errors.Wrap(err, "registering metrics")
+	}
+	b.metrics = &formatMetrics{storeMetrics: m, format: format}
+	return nil
+}
+
+// WithRemoteOptions adds remote options to use for client operations.
+// Remote options are applied in the order the storer options are given. To
+// combine auth with WithKeychain, set explicit credentials with
+// WithAuthenticator rather than with remote.WithAuth, as the registry client
+// rejects operations given both a keychain and an authenticator.
+func WithRemoteOptions(opts ...remote.Option) Option {
+	return &remoteOptionsOption{
+		opts: opts,
+	}
+}
+
+type remoteOptionsOption struct {
+	opts []remote.Option
+}
+
+func (o *remoteOptionsOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *remoteOptionsOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *remoteOptionsOption) apply(b *baseStorer) error {
+	b.remoteOpts = append(b.remoteOpts, o.opts...)
+	return nil
+}
+
+// WithKeychain authenticates client operations with credentials resolved from
+// kc. Credentials are resolved for every operation, so keychains refreshing
+// short-lived tokens keep working in long-running controllers. If combined
+// with WithAuthenticator, whichever is given last wins.
+func WithKeychain(kc authn.Keychain) Option {
+	return &authOption{
+		kc: kc,
+	}
+}
+
+// WithAuthenticator authenticates client operations with auth. If combined
+// with WithKeychain, whichever is given last wins.
+func WithAuthenticator(auth authn.Authenticator) Option {
+	return &authOption{
+		auth: auth,
+	}
+}
+
+type authOption struct {
+	kc   authn.Keychain
+	auth authn.Authenticator
+}
+
+func (o *authOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *authOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *authOption) apply(b *baseStorer) error {
+	switch {
+	case o.kc != nil:
+		b.auth = remote.WithAuthFromKeychain(o.kc)
+	case o.auth != nil:
+		b.auth = remote.WithAuth(o.auth)
+	default:
+		return errors.New("keychain or authenticator must not be nil")
+	}
+	return nil
+}
+
+// WithECRKeychain authenticates client operations to Amazon ECR with the ECR
+// credential helper, which refreshes the 12 hour authorization tokens as they
+// expire. See WithKeychain for how it composes with other auth options.
+func WithECRKeychain() Option {
+	return WithKeychain(authn.NewKeychainFromHelper(ecr.NewECRHelper(ecr.WithLogger(io.Discard))))
+}
+
+// WithPredicateSchemaURL annotates attestations with the URL of the JSON
+// schema their predicate conforms to, keyed by predicate type, so that
+// consumers can validate the predicate against it. Attestations with other
+// predicate types are not annotated.
+func WithPredicateSchemaURL(schemas map[string]string) AttestationStorerOption {
+	return &predicateSchemaURLOption{
+		schemas: schemas,
+	}
+}
+
+type predicateSchemaURLOption struct {
+	schemas map[string]string
+}
+
+func (o *predicateSchemaURLOption) applyAttestationStorer(s *AttestationStorer) error {
+	schemas := make(map[string]string, len(o.schemas))
+	for predicateType, schema := range o.schemas {
+		u, err := url.Parse(schema)
+		if err != nil {
+			return errors.Wrapf(err, "parsing schema URL for predicate type %s", predicateType)
+		}
+		if !u.IsAbs() {
+			return errors.Errorf("schema URL for predicate type %s must be absolute, got %q", predicateType, schema)
+		}
+		schemas[predicateType] = schema
+	}
+	s.predicateSchemas = schemas
+	return nil
+}
+
+// googleKeychain resolves credentials for Google Container Registry and
+// Artifact Registry. It is a variable so that tests can replace it.
+var googleKeychain = google.Keychain
+
+// WithGoogleKeychain authenticates client operations to Google Container
+// Registry and Artifact Registry with Application Default Credentials,
+// including workload identity and metadata server tokens. Other registries
+// are accessed anonymously. See WithKeychain for how it composes with other
+// auth options.
+func WithGoogleKeychain() Option {
+	return WithKeychain(googleKeychain)
+}
+
+// WithParentAttestations records that the stored attestations are derived from
+// the given attestation manifests. Their references are recorded in an
+// annotation along with the depth of the attestation chain, one more than the
+// deepest parent, so that consumers can follow the derivation lineage.
+func WithParentAttestations(parents []name.Digest) AttestationStorerOption {
+	return &parentAttestationsOption{
+		parents: parents,
+	}
+}
+
+type parentAttestationsOption struct {
+	parents []name.Digest
+}
+
+func (o *parentAttestationsOption) applyAttestationStorer(s *AttestationStorer) error {
+	s.parentAttestations = append([]name.Digest{}, o.parents...)
+	return nil
+}
+
+// WithTransport sets the HTTP transport of all client operations, e.g. to go
+// through a proxy or present client certificates. Auth, rate limiting and
+// retries are layered on top of it.
+func WithTransport(rt http.RoundTripper) Option {
+	return &transportOption{
+		rt: rt,
+	}
+}
+
+type transportOption struct {
+	rt http.RoundTripper
+}
+
+func (o *transportOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *transportOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *transportOption) apply(b *baseStorer) error {
+	if o.rt == nil {
+		return errors.New("transport must not be nil")
+	}
+	b.transport = o.rt
+	return nil
+}
+
+// WithInsecure configures the storer to access the given registries, e.g.
+// "localhost:5000" or "registry.internal", over plain HTTP. Other registries
+// are still accessed over HTTPS. This is meant for local and air-gapped
+// registries that do not serve TLS.
+func WithInsecure(registries ...string) Option {
+	return &insecureOption{
+		registries: registries,
+	}
+}
+
+type insecureOption struct {
+	registries []string
+}
+
+func (o *insecureOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *insecureOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *insecureOption) apply(b *baseStorer) error {
+	if len(o.registries) == 0 {
+		return errors.New("at least one insecure registry must be given")
+	}
+	if b.insecureRegistries == nil {
+		b.insecureRegistries = map[string]bool{}
+	}
+	for _, r := range o.registries {
+		reg, err := name.NewRegistry(r, name.StrictValidation)
+		if err != nil {
+			return errors.Wrapf(err, "parsing insecure registry %q", r)
+		}
+		b.insecureRegistries[reg.RegistryStr()] = true
+	}
+	return nil
+}
+
+// WithCABundle configures the storer to trust the PEM encoded CA certificates
+// in addition to the system roots, e.g. for registries using a private CA. It
+// applies to the transport configured so far, so it must come after
+// WithTransport, which must then be an *http.Transport.
+func WithCABundle(pemBytes []byte) Option {
+	return &caBundleOption{
+		pem: pemBytes,
+	}
+}
+
+type caBundleOption struct {
+	pem []byte
+}
+
+func (o *caBundleOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *caBundleOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *caBundleOption) apply(b *baseStorer) error {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if err := appendCertsFromPEM(pool, o.pem); err != nil {
+		return errors.Wrap(err, "parsing CA bundle")
+	}
+
+	t, err := b.httpTransport("CA bundle")
+	if err != nil {
+		return err
+	}
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	t.TLSClientConfig.RootCAs = pool
+	b.transport = t
+	return nil
+}
+
+// httpTransport returns a copy of the transport configured so far, which
+// must be an *http.Transport, for an option named what to modify.
+func (b *baseStorer) httpTransport(what string) (*http.Transport, error) {
+	rt := b.transport
+	if rt == nil {
+		rt = remote.DefaultTransport
+	}
+	t, ok := rt.(*http.Transport)
+	if !ok {
+		return nil, errors.Errorf("%s requires an *http.Transport, got %T", what, rt)
+	}
+	return t.Clone(), nil
+}
+
+// appendCertsFromPEM adds the certificates in pemBytes to pool. Unlike
+// x509.CertPool.AppendCertsFromPEM, it fails on malformed input instead of
+// skipping it.
+func appendCertsFromPEM(pool *x509.CertPool, pemBytes []byte) error {
+	found := false
+	for rest := pemBytes; len(rest) > 0; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			if len(bytes.TrimSpace(rest)) > 0 {
+				return errors.New("malformed PEM data")
+			}
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return errors.Errorf("unexpected PEM block of type %q", block.Type)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return errors.Wrap(err, "parsing certificate")
+		}
+		pool.AddCert(cert)
+		found = true
+	}
+	if !found {
+		return errors.New("no certificates found")
+	}
+	return nil
+}
+
+// WithMaxConcurrentStores bounds the number of stores of the storer that write
+// to registries at once to n. Stores beyond the limit wait for a slot, or for
+// their context to be done. The limit covers the writes to the mirrors and,
+// unless their policy sets a limit of its own, the stores on the hosts with a
+// policy. Use WithStoreLimiter to share a limit between storers.
+func WithMaxConcurrentStores(n int) Option {
+	return &maxConcurrentStoresOption{
+		n: n,
+	}
+}
+
+type maxConcurrentStoresOption struct {
+	n int
+}
+
+func (o *maxConcurrentStoresOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *maxConcurrentStoresOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *maxConcurrentStoresOption) apply(b *baseStorer) error {
+	if o.n <= 0 {
+		return errors.Errorf("max concurrent stores must be positive, got %d", o.n)
+	}
+	b.storeLimiter = semaphore.NewWeighted(int64(o.n))
+	return nil
+}
+
+// WithStoreLimiter bounds the stores writing to registries at once by the
+// slots of sem, each store holding one slot during its writes. Passing the
+// same semaphore to several storers, e.g. a SimpleStorer and an
+// AttestationStorer, makes them share the limit.
+func WithStoreLimiter(sem *semaphore.Weighted) Option {
+	return &storeLimiterOption{
+		sem: sem,
+	}
+}
+
+type storeLimiterOption struct {
+	sem *semaphore.Weighted
+}
+
+func (o *storeLimiterOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *storeLimiterOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *storeLimiterOption) apply(b *baseStorer) error {
+	if o.sem == nil {
+		return errors.New("store limiter must not be nil")
+	}
+	b.storeLimiter = o.sem
+	return nil
+}
+
+// WithRecordPayloadDigests configures the storer to annotate signatures and
+// attestations with the digests of both the payload of the store request, as
+// marshaled again by the storer, and the signed payload bytes when they differ.
+// This helps debugging signature mismatches caused by re-marshaling.
+func WithRecordPayloadDigests(record bool) Option {
+	return &recordPayloadDigestsOption{
+		record: record,
+	}
+}
+
+type recordPayloadDigestsOption struct {
+	record bool
+}
+
+func (o *recordPayloadDigestsOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *recordPayloadDigestsOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *recordPayloadDigestsOption) apply(b *baseStorer) error {
+	b.recordPayloadDigests = o.record
+	return nil
+}
+
+// WithEntityCache configures the storer to fetch the artifact of a store once
+// within ttl, saving a registry round trip for each of the next stores of the
+// same artifact. The signatures and attestations already attached are read
+// again by every store, so that none attaches to a stale copy of them.
+func WithEntityCache(ttl time.Duration) Option {
+	return &entityCacheOption{
+		ttl: ttl,
+	}
+}
+
+type entityCacheOption struct {
+	ttl time.Duration
+}
+
+func (o *entityCacheOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *entityCacheOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *entityCacheOption) apply(b *baseStorer) error {
+	if o.ttl <= 0 {
+		return errors.Errorf("entity cache ttl must be positive, got %s", o.ttl)
+	}
+	b.entityCache = newEntityCache(o.ttl)
+	b.entityCache.now = b.now
+	return nil
+}
+
+// WithConfigBlob sets the config of the attestation manifests to data, with
+// the given media type, in place of the config generated by cosign. This lets
+// callers attach their own metadata to the manifests. When combined with
+// WithCreationTime, the config blob takes precedence.
+func WithConfigBlob(data []byte, mediaType string) AttestationStorerOption {
+	return &configBlobOption{
+		data:      data,
+		mediaType: mediaType,
+	}
+}
+
+type configBlobOption struct {
+	data      []byte
+	mediaType string
+}
+
+func (o *configBlobOption) applyAttestationStorer(s *AttestationStorer) error {
+	config, err := newConfigBlob(o.data, o.mediaType)
+	if err != nil {
+		return err
+	}
+	s.configBlob = config
+	return nil
+}
+
+// reservedAnnotationPrefixes are the prefixes of the annotation keys set by
+// cosign and by the storers, which cannot be configured with WithAnnotations.
+var reservedAnnotationPrefixes = []string{
+	"dev.cosignproject.cosign/",
+	"dev.sigstore.cosign/",
+	"dev.tekton.chains/",
+	"chains.tekton.dev/",
+}
+
+// WithAnnotations adds the annotations, e.g. the name of the PipelineRun or
+// the git commit, to the stored signatures and attestations. Annotations
+// configured several times are merged, later values taking precedence. Keys
+// reserved by cosign and the storers are rejected.
+func WithAnnotations(annotations map[string]string) Option {
+	return &annotationsOption{
+		annotations: annotations,
+	}
+}
+
+type annotationsOption struct {
+	annotations map[string]string
+}
+
+func (o *annotationsOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *annotationsOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *annotationsOption) apply(b *baseStorer) error {
+	for k := range o.annotations {
+		if k == "" {
+			return errors.New("annotation key must not be empty")
+		}
+		for _, prefix := range reservedAnnotationPrefixes {
+			if strings.HasPrefix(k, prefix) {
+				return errors.Errorf("annotation %q is reserved", k)
+			}
+		}
+	}
+	if b.annotations == nil {
+		b.annotations = map[string]string{}
+	}
+	maps.Copy(b.annotations, o.annotations)
+	return nil
+}
+
+// WithIdempotencyKey records key, e.g. the UID of a TaskRun and its attempt,
+// on the stored attestations, and skips the upload if an attestation with the
+// same key is already attached to the artifact. Unlike WithSkipIfExists, the
+// upload is skipped even if the attestations differ.
+func WithIdempotencyKey(key string) AttestationStorerOption {
+	return &idempotencyKeyOption{
+		key: key,
+	}
+}
+
+type idempotencyKeyOption struct {
+	key string
+}
+
+func (o *idempotencyKeyOption) applyAttestationStorer(s *AttestationStorer) error {
+	if o.key == "" {
+		return errors.New("idempotency key must not be empty")
+	}
+	s.idempotencyKey = o.key
+	return nil
+}
+
+// WithFanoutToChildren also stores attestations of an image index against
+// each image and index it references, so that consumers pulling a single
+// platform find them. Failures on individual children are aggregated and
+// returned after all children were attempted.
+func WithFanoutToChildren() AttestationStorerOption {
+	return &fanoutToChildrenOption{}
+}
+
+type fanoutToChildrenOption struct{}
+
+func (o *fanoutToChildrenOption) applyAttestationStorer(s *AttestationStorer) error {
+	s.fanoutToChildren = true
+	return nil
+}
+
+// WithMirrors configures repositories that signatures and attestations are
+// written to besides the target repository, e.g. geo-replicas. With
+// MirrorModeFailover, the mirrors are tried in order when the write to the
+// target repository fails. With MirrorModeAll, every repository is written to.
+// The StoreResponse lists the repositories that were written to.
+func WithMirrors(mirrors []MirrorRepository, mode MirrorMode) Option {
+	return &mirrorsOption{
+		mirrors: mirrors,
+		mode:    mode,
+	}
+}
+
+type mirrorsOption struct {
+	mirrors []MirrorRepository
+	mode    MirrorMode
+}
+
+func (o *mirrorsOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *mirrorsOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *mirrorsOption) apply(b *baseStorer) error {
+	if len(o.mirrors) == 0 {
+		return errors.New("at least one mirror repository is required")
+	}
+	if o.mode != MirrorModeFailover && o.mode != MirrorModeAll {
+		return errors.Errorf("unsupported mirror mode %d", o.mode)
+	}
+	b.mirrors = o.mirrors
+	b.mirrorMode = o.mode
+	return nil
+}
+
+// WithDryRun configures the storers to look up the artifact and build the
+// signature or attestation manifest without writing it. The StoreResponse
+// holds the reference and digest the manifest would be stored at. As nothing
+// is written, only pull access to the registry is required.
+func WithDryRun() Option {
+	return &dryRunOption{}
+}
+
+type dryRunOption struct{}
+
+func (o *dryRunOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *dryRunOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *dryRunOption) apply(b *baseStorer) error {
+	b.dryRun = true
+	return nil
+}
+
+// WithReproducible configures the storers to record the epoch, or the time
+// configured with WithCreationTime, as every timestamp of the signature and
+// attestation manifests: the created timestamp of the config and those of its
+// history entries. The layers hold the signed payloads as is and carry no
+// timestamps, so storing identical inputs produces byte-identical manifests.
+func WithReproducible(enabled bool) Option {
+	return &reproducibleOption{
+		enabled: enabled,
+	}
+}
+
+type reproducibleOption struct {
+	enabled bool
+}
+
+func (o *reproducibleOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *reproducibleOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *reproducibleOption) apply(b *baseStorer) error {
+	b.reproducible = o.enabled
+	return nil
+}
+
+// WithLongAnnotationStrategy configures how predicate types longer than
+// maxLength bytes are recorded in the predicate type annotation of the stored
+// attestations, for registries limiting the length of annotation values. The
+// full predicate type remains available in the signed statement.
+func WithLongAnnotationStrategy(strategy LongAnnotationStrategy, maxLength int) AttestationStorerOption {
+	return &longAnnotationStrategyOption{
+		strategy:  strategy,
+		maxLength: maxLength,
+	}
+}
+
+type longAnnotationStrategyOption struct {
+	strategy  LongAnnotationStrategy
+	maxLength int
+}
+
+func (o *longAnnotationStrategyOption) applyAttestationStorer(s *AttestationStorer) error {
+	if o.strategy != LongAnnotationHash && o.strategy != LongAnnotationTruncate {
+		return errors.Errorf("unsupported long annotation strategy %d", o.strategy)
+	}
+	if o.maxLength < minLongAnnotationLength {
+		return errors.Errorf("annotation length limit must be at least %d, got %d", minLongAnnotationLength, o.maxLength)
+	}
+	s.longAnnotations = &longAnnotations{strategy: o.strategy, maxLength: o.maxLength}
+	return nil
+}
+
+// WithSortedReferrers configures Retrieve and RetrieveByArtifactType to return
+// the statements of referrers newest first, by the
+// org.opencontainers.image.created annotation of their manifests, rather than
+// in the order listed by the registry. Referrers without the annotation follow,
+// and referrers created at the same time are ordered by digest.
+func WithSortedReferrers() AttestationStorerOption {
+	return &sortedReferrersOption{}
+}
+
+type sortedReferrersOption struct{}
+
+func (o *sortedReferrersOption) applyAttestationStorer(s *AttestationStorer) error {
+	s.sortReferrers = true
+	return nil
+}
+
+// WithSubjectValidation configures the storer to check, before writing, that
+// one of the subjects of the statement has the digest of the artifact it is
+// attached to, failing the store otherwise.
+func WithSubjectValidation() AttestationStorerOption {
+	return &subjectValidationOption{}
+}
+
+type subjectValidationOption struct{}
+
+func (o *subjectValidationOption) applyAttestationStorer(s *AttestationStorer) error {
+	s.validateSubject = true
+	return nil
+}
+
+// WithMaxIdleConnsPerHost bounds the number of idle connections the transport
+// keeps open to each registry host, so that bursts of stores do not leave many
+// file descriptors open. Like WithCABundle, it applies to the transport
+// configured so far, which must be an *http.Transport.
+func WithMaxIdleConnsPerHost(n int) Option {
+	return &connLimitOption{
+		name: "max idle connections per host",
+		n:    n,
+		set:  func(t *http.Transport, n int) { t.MaxIdleConnsPerHost = n },
+	}
+}
+
+// WithMaxConnsPerHost bounds the number of connections, in any state, the
+// transport opens to each registry host. Requests beyond the limit wait for a
+// connection to become available. Like WithCABundle, it applies to the
+// transport configured so far, which must be an *http.Transport.
+func WithMaxConnsPerHost(n int) Option {
+	return &connLimitOption{
+		name: "max connections per host",
+		n:    n,
+		set:  func(t *http.Transport, n int) { t.MaxConnsPerHost = n },
+	}
+}
+
+type connLimitOption struct {
+	name string
+	n    int
+	set  func(t *http.Transport, n int)
+}
+
+func (o *connLimitOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *connLimitOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *connLimitOption) apply(b *baseStorer) error {
+	if o.n <= 0 {
+		return errors.Errorf("%s must be positive, got %d", o.name, o.n)
+	}
+	t, err := b.httpTransport(o.name)
+	if err != nil {
+		return err
+	}
+	o.set(t, o.n)
+	b.transport = t
+	return nil
+}
+
+// WithMaintainLatestPointer configures the storer to also tag each stored
+// attestation as <subject .att tag>.latest, e.g. sha256-<hex>.att.latest, a
+// mutable tag always pointing at a manifest holding only the newest
+// attestation of the subject. It is a convenience for humans and does not
+// change where the attestations themselves are stored.
+func WithMaintainLatestPointer(enabled bool) AttestationStorerOption {
+	return &maintainLatestPointerOption{
+		enabled: enabled,
+	}
+}
+
+type maintainLatestPointerOption struct {
+	enabled bool
+}
+
+func (o *maintainLatestPointerOption) applyAttestationStorer(s *AttestationStorer) error {
+	s.maintainLatestPointer = o.enabled
+	return nil
+}
+
+// WithLocalLayout configures the storer to write signatures and attestations
+// to the OCI image layout at path, created if needed, rather than to the
+// registry, e.g. for air-gapped builds synced to a registry later. Their
+// manifests are the ones that would be pushed to the .sig and .att tags, and
+// are named in the layout index by the full reference of those tags. The
+// subjects need not be in the layout, and the registry is never contacted.
+// Annotation verification, the transparency index and the latest attestation
+// pointer are not supported.
+func WithLocalLayout(path string) Option {
+	return &localLayoutOption{
+		path: path,
+	}
+}
+
+type localLayoutOption struct {
+	path string
+}
+
+func (o *localLayoutOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *localLayoutOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *localLayoutOption) apply(b *baseStorer) error {
+	if o.path == "" {
+		return errors.New("OCI layout path must not be empty")
+	}
+	b.localLayout = o.path
+	return nil
+}
+
+// WithCanonicalizer sets the serialization of statements expected by the
+// verifiers of the stored attestations, e.g. JCSCanonicalizer{} or
+// CosignCanonicalizer{}. The envelopes are stored as signed, since
+// re-serializing their payload would invalidate the signature, but a warning
+// is logged when the signed payload is not in this serialization. It is also
+// the serialization recorded with WithRecordPayloadDigests.
+func WithCanonicalizer(c Canonicalizer) AttestationStorerOption {
+	return &canonicalizerOption{
+		c: c,
+	}
+}
+
+type canonicalizerOption struct {
+	c Canonicalizer
+}
+
+func (o *canonicalizerOption) applyAttestationStorer(s *AttestationStorer) error {
+	if o.c == nil {
+		return errors.New("canonicalizer must not be nil")
+	}
+	s.canonicalizer = o.c
+	return nil
+}
+
+// WithRekorBundle attaches the Rekor transparency log entry of the signature,
+// including its signed entry timestamp, to the stored signatures and
+// attestations, so that they can be verified offline. Without it, nothing is
+// attached, e.g. for signatures that were not uploaded to a transparency log.
+func WithRekorBundle(b *bundle.RekorBundle) Option {
+	return &rekorBundleOption{
+		bundle: b,
+	}
+}
+
+type rekorBundleOption struct {
+	bundle *bundle.RekorBundle
+}
+
+func (o *rekorBundleOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *rekorBundleOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *rekorBundleOption) apply(b *baseStorer) error {
+	if o.bundle == nil {
+		return errors.New("rekor bundle must not be nil")
+	}
+	b.rekorBundle = o.bundle
+	return nil
+}
+
+// WithSignatureValidation configures the storer to check that the signature
+// to store holds raw signature bytes, failing the store with an error matching
+// ErrMalformedSignature if it is empty or already base64 encoded, e.g. by a
+// misconfigured signer. Such signatures would otherwise be stored encoded
+// twice and fail verification.
+func WithSignatureValidation() SimpleStorerOption {
+	return &signatureValidationOption{}
+}
+
+type signatureValidationOption struct{}
+
+func (o *signatureValidationOption) applySimpleStorer(s *SimpleStorer) error {
+	s.validateSignature = true
+	return nil
+}
+
+// WithRFC3161Timestamp attaches the DER encoded RFC 3161 timestamp response
+// obtained from a timestamp authority for the signature to the stored
+// signatures and attestations, e.g. for keyed signing without a transparency
+// log.
+func WithRFC3161Timestamp(token []byte) Option {
+	return &rfc3161TimestampOption{
+		token: token,
+	}
+}
+
+type rfc3161TimestampOption struct {
+	token []byte
+}
+
+func (o *rfc3161TimestampOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *rfc3161TimestampOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *rfc3161TimestampOption) apply(b *baseStorer) error {
+	if len(o.token) == 0 {
+		return errors.New("RFC 3161 timestamp must not be empty")
+	}
+	var v asn1.RawValue
+	if rest, err := asn1.Unmarshal(o.token, &v); err != nil || len(rest) > 0 {
+		return errors.New("RFC 3161 timestamp must be a DER encoded timestamp response")
+	}
+	b.rfc3161Timestamp = &bundle.RFC3161Timestamp{SignedRFC3161Timestamp: bytes.Clone(o.token)}
+	return nil
+}
+
+// WithDeleteMigratedTags configures Migrate to delete the .att manifest of
+// the artifact once all of its attestations have been written to referrers,
+// for registries where the legacy tags are no longer read.
+func WithDeleteMigratedTags() AttestationStorerOption {
+	return &deleteMigratedTagsOption{}
+}
+
+type deleteMigratedTagsOption struct{}
+
+func (o *deleteMigratedTagsOption) applyAttestationStorer(s *AttestationStorer) error {
+	s.deleteMigratedTags = true
+	return nil
+}
+
+// WithMaxPayloadBytes configures the storer to reject store requests whose
+// payload is larger than n bytes before uploading anything: the DSSE envelope
+// of attestations, or the signed content of signatures. The errors match
+// ErrPayloadTooLarge. Zero means no limit.
+func WithMaxPayloadBytes(n int64) Option {
+	return &maxPayloadBytesOption{
+		n: n,
+	}
+}
+
+type maxPayloadBytesOption struct {
+	n int64
+}
+
+func (o *maxPayloadBytesOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *maxPayloadBytesOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *maxPayloadBytesOption) apply(b *baseStorer) error {
+	if o.n < 0 {
+		return errors.Errorf("maximum payload size must not be negative, got %d", o.n)
+	}
+	b.maxPayloadBytes = o.n
+	return nil
+}
+
+// WithVerifyAfterWrite configures the storers to read the written signature
+// or attestation back from the registry and fail the store unless it is
+// served with the digest it was written with, e.g. for registries that may
+// silently drop writes. Registries lagging behind their writes are read again
+// for a few seconds. It doubles the requests of each store.
+func WithVerifyAfterWrite() Option {
+	return &verifyAfterWriteOption{}
+}
+
+type verifyAfterWriteOption struct{}
+
+func (o *verifyAfterWriteOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *verifyAfterWriteOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *verifyAfterWriteOption) apply(b *baseStorer) error {
+	rb := defaultReadBack
+	b.readBack = &rb
+	return nil
+}
+
+// WithTagPrefix configures the storers to prepend prefix to the tags of the
+// legacy .sig and .att manifests, e.g. "chains-" for chains-sha256-<hex>.att,
+// for registries with tag naming policies. The tags must stay valid: the
+// prefix may only hold letters, digits, underscores, periods and dashes, must
+// not start with a period or a dash, and must leave room for the digest.
+// Referrers are not tagged and are unaffected.
+func WithTagPrefix(prefix string) Option {
+	return &tagPrefixOption{
+		prefix: prefix,
+	}
+}
+
+// tagPrefixPattern matches the prefixes starting valid OCI tags.
+var tagPrefixPattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9._-]*$`)
+
+// maxTagLength is the maximum length of OCI tags.
+const maxTagLength = 128
+
+type tagPrefixOption struct {
+	prefix string
+}
+
+func (o *tagPrefixOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *tagPrefixOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *tagPrefixOption) apply(b *baseStorer) error {
+	if o.prefix == "" {
+		return errors.New("tag prefix must not be empty")
+	}
+	if !tagPrefixPattern.MatchString(o.prefix) {
+		return errors.Errorf("invalid tag prefix %q: tags may only hold letters, digits, underscores, periods and dashes, and must not start with a period or a dash", o.prefix)
+	}
+	// The tags of sha256 digests must not exceed the maximum tag length.
+	if n := len(o.prefix + "sha256-" + strings.Repeat("0", 64) + "." + ociremote.AttestationTagSuffix); n > maxTagLength {
+		return errors.Errorf("invalid tag prefix %q: it makes tags of %d characters, more than the maximum of %d", o.prefix, n, maxTagLength)
+	}
+	b.tagPrefix = o.prefix
+	return nil
+}
+
+// WithInProcessDedup configures the storer to skip the stores of a payload it
+// already stored for the same artifact within ttl, returning the response of
+// the earlier store. Stores of the same payload running concurrently wait for
+// the first one and are skipped if it succeeded. This covers the window in
+// which the registry may not serve a write yet, which WithSkipIfExists cannot
+// see; stores of other payloads are never skipped.
+func WithInProcessDedup(ttl time.Duration) Option {
+	return &inProcessDedupOption{
+		ttl: ttl,
+	}
+}
+
+type inProcessDedupOption struct {
+	ttl time.Duration
+}
+
+func (o *inProcessDedupOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *inProcessDedupOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *inProcessDedupOption) apply(b *baseStorer) error {
+	if o.ttl <= 0 {
+		return errors.Errorf("dedup ttl must be positive, got %s", o.ttl)
+	}
+	b.dedup = newDedupCache(o.ttl)
+	b.dedup.now = b.now
+	return nil
+}
+
+// WithUserAgent configures the storers to identify themselves to registries
+// with userAgent, e.g. "my-controller/1.2", so that registry operators can
+// attribute their traffic. go-containerregistry appends its own product token.
+// By default, the storers identify as Tekton Chains and its version.
+func WithUserAgent(userAgent string) Option {
+	return &userAgentOption{
+		userAgent: userAgent,
+	}
+}
+
+type userAgentOption struct {
+	userAgent string
+}
+
+func (o *userAgentOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *userAgentOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *userAgentOption) apply(b *baseStorer) error {
+	if strings.TrimSpace(o.userAgent) == "" {
+		return errors.New("user agent must not be empty")
+	}
+	if strings.ContainsAny(o.userAgent, "\r\n") {
+		return errors.Errorf("invalid user agent %q: it must not contain line breaks", o.userAgent)
+	}
+	b.userAgent = o.userAgent
+	return nil
+}
+
+// WithClock configures the clock the storers read the current time from, for
+// the creation times recorded in the referrers written by Migrate, the
+// expiry of the entries of WithEntityCache and WithInProcessDedup, and the
+// store durations recorded by WithMetrics. Tests can
+// pass a fake clock to get reproducible output. By default, the real clock is
+// used. The created timestamps of the signature and attestation manifests are
+// configured with WithCreationTime instead.
+func WithClock(c clock.PassiveClock) Option {
+	return &clockOption{
+		clock: c,
+	}
+}
+
+type clockOption struct {
+	clock clock.PassiveClock
+}
+
+func (o *clockOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *clockOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *clockOption) apply(b *baseStorer) error {
+	if o.clock == nil {
+		return errors.New("clock must not be nil")
+	}
+	b.clock = o.clock
+	return nil
+}
+
+// WithDebugTransport configures the storers to log every registry request and
+// its response with the logger of the context: the method, the URL, the
+// status, the duration and the headers relevant to authentication, referrers
+// and rate limiting. Credentials are never logged: the Authorization header is
+// reduced to its scheme and the query parameters that may hold secrets, e.g.
+// of presigned blob URLs, are redacted. Request and response bodies, including
+// the tokens issued by the registry, are not logged.
+func WithDebugTransport() Option {
+	return &debugTransportOption{}
+}
+
+type debugTransportOption struct{}
+
+func (o *debugTransportOption) applyAttestationStorer(s *AttestationStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *debugTransportOption) applySimpleStorer(s *SimpleStorer) error {
+	return o.apply(&s.baseStorer)
+}
+
+func (o *debugTransportOption) apply(b *baseStorer) error {
+	b.debugTransport = true
+	return nil
+}
@@ -0,0 +1,44 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+const (
+	// unsignedPayloadDigestAnnotation holds the digest of the payload of the
+	// store request as marshaled by the storer.
+	unsignedPayloadDigestAnnotation = "dev.tekton.chains/unsigned-payload-digest"
+	// signedPayloadDigestAnnotation holds the digest of the payload bytes that
+	// were signed.
+	signedPayloadDigestAnnotation = "dev.tekton.chains/signed-payload-digest"
+)
+
+// addPayloadDigestAnnotations records the digests of the unsigned and signed
+// payloads when they differ, i.e. when marshaling the payload again does not
+// reproduce the signed bytes.
+func addPayloadDigestAnnotations(annotations map[string]string, unsigned []byte, signed [sha256.Size]byte) {
+	if sha256.Sum256(unsigned) == signed {
+		return
+	}
+	annotations[unsignedPayloadDigestAnnotation] = sha256Digest(sha256.Sum256(unsigned))
+	annotations[signedPayloadDigestAnnotation] = sha256Digest(signed)
+}
+
+func sha256Digest(sum [sha256.Size]byte) string {
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
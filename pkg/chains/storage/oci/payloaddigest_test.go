@@ -0,0 +1,167 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// indentJSON returns b re-marshaled with indentation, which changes its digest
+// but not its content.
+func indentJSON(t *testing.T, b []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, b, "", "  "); err != nil {
+		t.Fatalf("failed to indent payload: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// checkPayloadDigests checks the payload digest annotations of the only
+// signature in sigs. If signed is nil, no annotations are expected.
+func checkPayloadDigests(t *testing.T, sigs oci.Signatures, unsigned, signed []byte) {
+	t.Helper()
+	layers, err := sigs.Get()
+	if err != nil {
+		t.Fatalf("failed to get layers: %v", err)
+	}
+	if len(layers) != 1 {
+		t.Fatalf("got %d layers, want 1", len(layers))
+	}
+	ann, err := layers[0].Annotations()
+	if err != nil {
+		t.Fatalf("failed to read annotations: %v", err)
+	}
+	want := map[string]string{}
+	if signed != nil {
+		want[unsignedPayloadDigestAnnotation] = sha256Digest(sha256.Sum256(unsigned))
+		want[signedPayloadDigestAnnotation] = sha256Digest(sha256.Sum256(signed))
+	}
+	for _, key := range []string{unsignedPayloadDigestAnnotation, signedPayloadDigestAnnotation} {
+		if got := ann[key]; got != want[key] {
+			t.Errorf("annotation %s = %q, want %q", key, got, want[key])
+		}
+	}
+}
+
+func TestWithRecordPayloadDigests_Attestation(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		remarshal bool
+	}{{
+		name: "same bytes",
+	}, {
+		name:      "re-marshaled",
+		remarshal: true,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := logtesting.TestContextWithLogger(t)
+			ref := pushRandomImage(t, newTestRegistry(t, nil))
+			storer, err := NewAttestationStorer(WithRecordPayloadDigests(true))
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+
+			statement, payload := newTestStatement(t, ref, "https://example.com/predicate")
+			signed := payload
+			if tc.remarshal {
+				signed = indentJSON(t, payload)
+			}
+			if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+				Artifact: ref,
+				Payload:  statement,
+				Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, signed)},
+			}); err != nil {
+				t.Fatalf("error during Store(): %v", err)
+			}
+
+			se, err := ociremote.SignedEntity(ref)
+			if err != nil {
+				t.Fatalf("failed to get signed entity: %v", err)
+			}
+			atts, err := se.Attestations()
+			if err != nil {
+				t.Fatalf("failed to get attestations: %v", err)
+			}
+			if !tc.remarshal {
+				signed = nil
+			}
+			checkPayloadDigests(t, atts, payload, signed)
+		})
+	}
+}
+
+func TestWithRecordPayloadDigests_Signature(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		remarshal bool
+	}{{
+		name: "same bytes",
+	}, {
+		name:      "re-marshaled",
+		remarshal: true,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := logtesting.TestContextWithLogger(t)
+			ref := pushRandomImage(t, newTestRegistry(t, nil))
+			storer, err := NewSimpleStorerFromConfig(WithRecordPayloadDigests(true))
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+
+			format := simple.NewSimpleStruct(ref)
+			payload, err := json.Marshal(format)
+			if err != nil {
+				t.Fatalf("failed to marshal payload: %v", err)
+			}
+			signed := payload
+			if tc.remarshal {
+				signed = indentJSON(t, payload)
+			}
+			if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+				Artifact: ref,
+				Payload:  format,
+				Bundle:   &signing.Bundle{Content: signed, Signature: []byte("signature")},
+			}); err != nil {
+				t.Fatalf("error during Store(): %v", err)
+			}
+
+			se, err := ociremote.SignedEntity(ref)
+			if err != nil {
+				t.Fatalf("failed to get signed entity: %v", err)
+			}
+			sigs, err := se.Signatures()
+			if err != nil {
+				t.Fatalf("failed to get signatures: %v", err)
+			}
+			if !tc.remarshal {
+				signed = nil
+			}
+			checkPayloadDigests(t, sigs, payload, signed)
+		})
+	}
+}
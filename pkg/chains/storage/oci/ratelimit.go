@@ -0,0 +1,130 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	rateLimitRemainingHeader = "RateLimit-Remaining"
+	rateLimitResetHeader     = "RateLimit-Reset"
+
+	// rateLimitLowWatermark is the remaining quota at or below which requests
+	// start being spread out over the remainder of the rate limit window.
+	rateLimitLowWatermark = 5
+)
+
+// rateLimiter delays requests to a registry host once the quota advertised
+// through the RateLimit-Remaining and RateLimit-Reset headers runs low.
+type rateLimiter struct {
+	mu sync.Mutex
+	// resumeAt holds, per registry host, the earliest time at which the next
+	// request should be sent.
+	resumeAt map[string]time.Time
+
+	now   func() time.Time
+	sleep func(context.Context, time.Duration) error
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		resumeAt: map[string]time.Time{},
+		now:      time.Now,
+		sleep:    sleepContext,
+	}
+}
+
+// wrap returns a RoundTripper that throttles requests sent through rt.
+func (l *rateLimiter) wrap(rt http.RoundTripper) http.RoundTripper {
+	return &rateLimitTransport{inner: rt, limiter: l}
+}
+
+// wait blocks until requests to host may resume.
+func (l *rateLimiter) wait(ctx context.Context, host string) error {
+	l.mu.Lock()
+	delay := l.resumeAt[host].Sub(l.now())
+	l.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+	return l.sleep(ctx, delay)
+}
+
+// observe records the rate limit state advertised in the response headers.
+// When the remaining quota is low, the remaining requests are spread evenly
+// over the time left until the quota resets.
+func (l *rateLimiter) observe(host string, header http.Header) {
+	remaining, ok := parseRateLimitValue(header.Get(rateLimitRemainingHeader))
+	if !ok || remaining > rateLimitLowWatermark {
+		return
+	}
+	reset, ok := parseRateLimitValue(header.Get(rateLimitResetHeader))
+	if !ok {
+		return
+	}
+	delay := time.Duration(reset) * time.Second / time.Duration(remaining+1)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.resumeAt[host] = l.now().Add(delay)
+}
+
+// parseRateLimitValue parses the leading integer of a rate limit header value.
+// Registries may append a quota policy to the value, e.g. "76;w=21600".
+func parseRateLimitValue(v string) (int, bool) {
+	v, _, _ = strings.Cut(v, ";")
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+type rateLimitTransport struct {
+	inner   http.RoundTripper
+	limiter *rateLimiter
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.wait(req.Context(), req.URL.Host); err != nil {
+		return nil, err
+	}
+	resp, err := t.inner.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	t.limiter.observe(req.URL.Host, resp.Header)
+	return resp, nil
+}
+
+// sleepContext pauses for d, returning early with the context error if ctx is
+// done first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
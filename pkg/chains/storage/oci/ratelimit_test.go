@@ -0,0 +1,127 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestProactiveRateLimiting(t *testing.T) {
+	tests := []struct {
+		name      string
+		remaining string
+		wantDelay bool
+	}{
+		{
+			name:      "quota exhausted",
+			remaining: "0",
+			wantDelay: true,
+		},
+		{
+			name:      "quota low",
+			remaining: "3;w=21600",
+			wantDelay: true,
+		},
+		{
+			name:      "quota plentiful",
+			remaining: "100",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registryName := newTestRegistry(t, func(h http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set(rateLimitRemainingHeader, tt.remaining)
+					w.Header().Set(rateLimitResetHeader, "4")
+					h.ServeHTTP(w, r)
+				})
+			})
+			ref := pushRandomImage(t, registryName)
+
+			storer, err := NewAttestationStorer(WithTargetRepository(ref.Repository), WithProactiveRateLimiting(true))
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+			var mu sync.Mutex
+			var delays []time.Duration
+			storer.rateLimiter.sleep = func(_ context.Context, d time.Duration) error {
+				mu.Lock()
+				defer mu.Unlock()
+				delays = append(delays, d)
+				return nil
+			}
+
+			ctx := logtesting.TestContextWithLogger(t)
+			if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+				Artifact: ref,
+				Payload:  &intoto.Statement{},
+				Bundle:   &signing.Bundle{},
+			}); err != nil {
+				t.Fatalf("error during Store(): %v", err)
+			}
+
+			if got := len(delays) > 0; got != tt.wantDelay {
+				t.Fatalf("throttled = %v, want %v (delays: %v)", got, tt.wantDelay, delays)
+			}
+			for _, d := range delays {
+				if d <= 0 || d > 4*time.Second {
+					t.Errorf("delay %v outside of the advertised reset window", d)
+				}
+			}
+		})
+	}
+}
+
+func TestProactiveRateLimitingDisabled(t *testing.T) {
+	storer, err := NewSimpleStorerFromConfig(WithProactiveRateLimiting(true), WithProactiveRateLimiting(false))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	if storer.rateLimiter != nil {
+		t.Error("expected rate limiting to be disabled")
+	}
+}
+
+func TestParseRateLimitValue(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   int
+		wantOK bool
+	}{
+		{value: "42", want: 42, wantOK: true},
+		{value: " 7 ", want: 7, wantOK: true},
+		{value: "76;w=21600", want: 76, wantOK: true},
+		{value: ""},
+		{value: "-1"},
+		{value: "soon"},
+	}
+	for _, tt := range tests {
+		got, ok := parseRateLimitValue(tt.value)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parseRateLimitValue(%q) = (%d, %v), want (%d, %v)", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	intoto "github.com/in-toto/attestation/go/v1"
+)
+
+// createdAnnotation is the OCI annotation holding the creation time of a
+// manifest, in RFC 3339 format.
+const createdAnnotation = "org.opencontainers.image.created"
+
+// referrer holds the statements read from a referrer manifest.
+type referrer struct {
+	digest     string
+	created    time.Time
+	statements []*intoto.Statement
+}
+
+// referrerCreated returns the creation time recorded for a referrer, read
+// from its descriptor or, as not all registries list the annotations of
+// referrers, from its manifest. The zero time is returned if none is recorded
+// or it cannot be parsed.
+func referrerCreated(desc v1.Descriptor, img v1.Image) (time.Time, error) {
+	v, ok := desc.Annotations[createdAnnotation]
+	if !ok {
+		m, err := img.Manifest()
+		if err != nil {
+			return time.Time{}, err
+		}
+		v = m.Annotations[createdAnnotation]
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, nil
+	}
+	return t, nil
+}
+
+// sortReferrers sorts the referrers newest first, followed by those without a
+// creation time. Ties are broken by digest.
+func sortReferrers(referrers []referrer) {
+	slices.SortStableFunc(referrers, func(a, b referrer) int {
+		switch {
+		case a.created.IsZero() != b.created.IsZero():
+			if a.created.IsZero() {
+				return 1
+			}
+			return -1
+		case !a.created.Equal(b.created):
+			return b.created.Compare(a.created)
+		}
+		return cmp.Compare(a.digest, b.digest)
+	})
+}
@@ -0,0 +1,151 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	intoto "github.com/in-toto/attestation/go/v1"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/sigstore/cosign/v2/pkg/types"
+	"google.golang.org/protobuf/encoding/protojson"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// reorderedReferrers lists referrers in the order returned by reorder.
+func reorderedReferrers(reorder func([]v1.Descriptor)) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.Contains(r.URL.Path, "/referrers/") {
+				h.ServeHTTP(w, r)
+				return
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, r)
+			var idx v1.IndexManifest
+			if err := json.Unmarshal(rec.Body.Bytes(), &idx); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			reorder(idx.Manifests)
+			w.Header().Set("Content-Type", rec.Header().Get("Content-Type"))
+			if err := json.NewEncoder(w).Encode(idx); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+		})
+	}
+}
+
+func TestWithSortedReferrers(t *testing.T) {
+	referrers := []struct {
+		predicateType string
+		created       string
+	}{
+		{predicateType: "https://example.com/2024", created: "2024-01-01T00:00:00Z"},
+		{predicateType: "https://example.com/undated-1"},
+		{predicateType: "https://example.com/2025", created: "2025-01-01T00:00:00Z"},
+		{predicateType: "https://example.com/undated-2"},
+		{predicateType: "https://example.com/2023", created: "2023-01-01T00:00:00Z"},
+	}
+	wantDated := []string{"https://example.com/2025", "https://example.com/2024", "https://example.com/2023"}
+	// The same image and referrers are written to each registry, so that the
+	// undated referrers have the same digests everywhere.
+	img, err := random.Image(1024, 1)
+	if err != nil {
+		t.Fatalf("failed to create random image: %v", err)
+	}
+	imgDigest, err := img.Digest()
+	if err != nil {
+		t.Fatalf("failed to get image digest: %v", err)
+	}
+
+	var got [][]string
+	for order, reorder := range map[string]func([]v1.Descriptor){
+		"listed order": func([]v1.Descriptor) {},
+		"reversed":     slices.Reverse[[]v1.Descriptor],
+		"by digest": func(descs []v1.Descriptor) {
+			slices.SortFunc(descs, func(a, b v1.Descriptor) int { return strings.Compare(b.Digest.String(), a.Digest.String()) })
+		},
+	} {
+		t.Run(order, func(t *testing.T) {
+			ctx := logtesting.TestContextWithLogger(t)
+			var h http.Handler = registry.New(registry.WithReferrersSupport(true))
+			h = reorderedReferrers(reorder)(h)
+			s := httptest.NewServer(h)
+			t.Cleanup(s.Close)
+			ref, err := name.NewDigest(strings.TrimPrefix(s.URL, "http://") + "/test/img@" + imgDigest.String())
+			if err != nil {
+				t.Fatalf("failed to parse digest: %v", err)
+			}
+			if err := remote.Write(ref, img); err != nil {
+				t.Fatalf("failed to write image to mock registry: %v", err)
+			}
+			for _, r := range referrers {
+				payload, err := protojson.Marshal(&intoto.Statement{
+					Type:          intoto.StatementTypeUri,
+					Subject:       []*intoto.ResourceDescriptor{{Name: "img", Digest: map[string]string{"sha256": imgDigest.Hex}}},
+					PredicateType: r.predicateType,
+				})
+				if err != nil {
+					t.Fatalf("failed to marshal statement: %v", err)
+				}
+				att, err := static.NewAttestation(newTestEnvelope(t, payload), static.WithLayerMediaType(types.DssePayloadType))
+				if err != nil {
+					t.Fatalf("failed to create attestation: %v", err)
+				}
+				annotations := map[string]string{}
+				if r.created != "" {
+					annotations[createdAnnotation] = r.created
+				}
+				if err := ociremote.WriteReferrer(ref, types.IntotoPayloadType, []v1.Layer{att}, annotations); err != nil {
+					t.Fatalf("failed to write referrer: %v", err)
+				}
+			}
+
+			storer, err := NewAttestationStorer(WithSortedReferrers())
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+			statements, err := storer.Retrieve(ctx, ref)
+			if err != nil {
+				t.Fatalf("error during Retrieve(): %v", err)
+			}
+			var predicateTypes []string
+			for _, statement := range statements {
+				predicateTypes = append(predicateTypes, statement.GetPredicateType())
+			}
+			if len(predicateTypes) != len(referrers) || !slices.Equal(predicateTypes[:len(wantDated)], wantDated) {
+				t.Fatalf("Retrieve() predicate types = %v, want %v first", predicateTypes, wantDated)
+			}
+			got = append(got, predicateTypes)
+		})
+	}
+	for i := 1; i < len(got); i++ {
+		if !slices.Equal(got[i], got[0]) {
+			t.Errorf("Retrieve() orders differ across registries: %v and %v", got[0], got[i])
+		}
+	}
+}
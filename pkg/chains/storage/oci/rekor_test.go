@@ -0,0 +1,130 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/sigstore/cosign/v2/pkg/cosign/bundle"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestWithRekorBundle(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	ref := pushRandomImage(t, newTestRegistry(t, nil))
+	rb := &bundle.RekorBundle{
+		SignedEntryTimestamp: []byte("signed-entry-timestamp"),
+		Payload: bundle.RekorPayload{
+			Body:           "ZW50cnk=",
+			IntegratedTime: 1700000000,
+			LogIndex:       42,
+			LogID:          "c0d23d6ad406973f9559f3ba2d1ca01f84147d8ffc5b8445c224f98b9591801d",
+		},
+	}
+
+	as, err := NewAttestationStorer(WithRekorBundle(rb))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	statement, payload := newTestStatement(t, ref, "https://example.com/predicate")
+	if _, err := as.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  statement,
+		Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+	}); err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+	ss, err := NewSimpleStorerFromConfig(WithRekorBundle(rb))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	if _, err := ss.Store(ctx, &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+		Artifact: ref,
+		Payload:  simple.NewSimpleStruct(ref),
+		Bundle:   &signing.Bundle{Content: []byte("{}"), Signature: []byte("signature")},
+	}); err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+
+	se, err := ociremote.SignedEntity(ref)
+	if err != nil {
+		t.Fatalf("failed to get signed entity: %v", err)
+	}
+	atts, err := se.Attestations()
+	if err != nil {
+		t.Fatalf("failed to get attestations: %v", err)
+	}
+	sigs, err := se.Signatures()
+	if err != nil {
+		t.Fatalf("failed to get signatures: %v", err)
+	}
+	for what, s := range map[string]oci.Signatures{"attestation": atts, "signature": sigs} {
+		layers, err := s.Get()
+		if err != nil || len(layers) != 1 {
+			t.Fatalf("failed to get the %s: %d layers, %v", what, len(layers), err)
+		}
+		got, err := layers[0].Bundle()
+		if err != nil {
+			t.Fatalf("failed to read the %s bundle: %v", what, err)
+		}
+		if diff := cmp.Diff(rb, got); diff != "" {
+			t.Errorf("unexpected %s bundle (-want +got):\n%s", what, diff)
+		}
+	}
+
+	if _, err := NewAttestationStorer(WithRekorBundle(nil)); err == nil {
+		t.Error("expected an error for a nil bundle")
+	}
+}
+
+func TestWithoutRekorBundle(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	ref := pushRandomImage(t, newTestRegistry(t, nil))
+	as, err := NewAttestationStorer()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	statement, payload := newTestStatement(t, ref, "https://example.com/predicate")
+	if _, err := as.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  statement,
+		Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+	}); err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+	se, err := ociremote.SignedEntity(ref)
+	if err != nil {
+		t.Fatalf("failed to get signed entity: %v", err)
+	}
+	atts, err := se.Attestations()
+	if err != nil {
+		t.Fatalf("failed to get attestations: %v", err)
+	}
+	layers, err := atts.Get()
+	if err != nil || len(layers) != 1 {
+		t.Fatalf("failed to get the attestation: %d layers, %v", len(layers), err)
+	}
+	if got, err := layers[0].Bundle(); err != nil || got != nil {
+		t.Errorf("Bundle() = %v, %v, want none", got, err)
+	}
+}
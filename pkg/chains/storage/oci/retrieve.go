@@ -0,0 +1,310 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/pkg/errors"
+	"github.com/secure-systems-lab/go-securesystemslib/dsse"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/types"
+	protobundle "github.com/sigstore/protobuf-specs/gen/pb-go/bundle/v1"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// sigstoreBundleMediaTypePrefix is the media type prefix of all sigstore bundle versions.
+const sigstoreBundleMediaTypePrefix = "application/vnd.dev.sigstore.bundle"
+
+var (
+	_ api.Retriever[name.Digest, *intoto.Statement] = &AttestationStorer{}
+)
+
+// NotFoundError is returned when nothing is stored for an artifact.
+type NotFoundError struct {
+	Artifact name.Digest
+}
+
+// Error implements error.
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("no attestations found for %s", e.Artifact.String())
+}
+
+// Retrieve returns the statements of the attestations stored for the given
+// artifact, whether they were attached through the legacy .att tag, as
+// referrers, or as sigstore bundle referrers. A *NotFoundError is returned if
+// there are none. With WithLocalLayout, only the attestations of the .att
+// manifest in the layout are returned.
+func (s *AttestationStorer) Retrieve(ctx context.Context, artifact name.Digest) ([]*intoto.Statement, error) {
+	if hs := s.hostStorer(artifact); hs != nil {
+		return hs.Retrieve(ctx, artifact)
+	}
+	repo := s.targetRepository(artifact)
+
+	statements, err := s.retrieveTagged(ctx, artifact, repo)
+	if err != nil {
+		return nil, err
+	}
+	if s.localLayout == "" {
+		referred, err := s.retrieveReferrers(ctx, repo.Digest(artifact.DigestStr()), "")
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, referred...)
+	}
+
+	if len(statements) == 0 {
+		return nil, &NotFoundError{Artifact: artifact}
+	}
+	return statements, nil
+}
+
+// RetrieveByArtifactType returns the statements of the referrers of the given
+// artifact whose artifact type is artifactType, e.g. "application/vnd.in-toto+json".
+// The registry is asked to filter the referrers, and those it returns anyway
+// are filtered out locally. Attestations attached through the legacy .att tag
+// are not considered. A *NotFoundError is returned if there are none.
+func (s *AttestationStorer) RetrieveByArtifactType(ctx context.Context, artifact name.Digest, artifactType string) ([]*intoto.Statement, error) {
+	if hs := s.hostStorer(artifact); hs != nil {
+		return hs.RetrieveByArtifactType(ctx, artifact, artifactType)
+	}
+	if artifactType == "" {
+		return nil, errors.New("artifact type must not be empty")
+	}
+	if s.localLayout != "" {
+		return nil, errors.New("referrers are not supported with a local OCI layout")
+	}
+	repo := s.targetRepository(artifact)
+	statements, err := s.retrieveReferrers(ctx, repo.Digest(artifact.DigestStr()), artifactType)
+	if err != nil {
+		return nil, err
+	}
+	if len(statements) == 0 {
+		return nil, &NotFoundError{Artifact: artifact}
+	}
+	return statements, nil
+}
+
+// retrieveTagged returns the statements attached through the legacy .att tag.
+func (s *AttestationStorer) retrieveTagged(ctx context.Context, artifact name.Digest, repo name.Repository) ([]*intoto.Statement, error) {
+	se, err := s.storedEntity(ctx, artifact, repo)
+	if err != nil {
+		return nil, err
+	}
+	atts, err := se.Attestations()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting attestations")
+	}
+	sigs, err := atts.Get()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting attestations")
+	}
+	payloads, err := reassemblePayloads(sigs)
+	if err != nil {
+		return nil, err
+	}
+	statements := make([]*intoto.Statement, 0, len(payloads))
+	for _, payload := range payloads {
+		statement, err := statementFromEnvelope(payload)
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, statement)
+	}
+	return statements, nil
+}
+
+// retrieveReferrers returns the statements attached as in-toto or sigstore
+// bundle referrers of the artifact. If artifactType is set, only the referrers
+// of that artifact type are considered.
+func (s *AttestationStorer) retrieveReferrers(ctx context.Context, d name.Digest, artifactType string) ([]*intoto.Statement, error) {
+	listOpts := s.remoteOptions(ctx)
+	if artifactType != "" {
+		listOpts = append(listOpts, remote.WithTransport(&artifactTypeFilterTransport{
+			inner:        s.roundTripper(),
+			artifactType: artifactType,
+		}))
+	}
+	// The artifact type is not passed on as ggcr would then filter the
+	// referrers by the artifact type reported by the registry.
+	idx, err := ociremote.Referrers(d, "", ociremote.WithRemoteOptions(listOpts...))
+	var terr *transport.Error
+	if errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "listing referrers")
+	}
+
+	var referrers []referrer
+	for _, desc := range idx.Manifests {
+		// Registries disagree on the artifact type they report for referrers, so
+		// the layer media types are used to find the attestations instead. Only
+		// the manifests are fetched for other referrers.
+		img, err := remote.Image(d.Context().Digest(desc.Digest.String()), s.remoteOptions(ctx)...)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fetching referrer %s", desc.Digest)
+		}
+		if artifactType != "" && desc.ArtifactType != artifactType {
+			// Registries that do not report the artifact type of the manifest
+			// may report the media type of its config instead.
+			if at, err := manifestArtifactType(img); err != nil {
+				return nil, errors.Wrapf(err, "fetching referrer %s", desc.Digest)
+			} else if at != artifactType {
+				continue
+			}
+		}
+		layers, err := img.Layers()
+		if err != nil {
+			return nil, errors.Wrapf(err, "fetching referrer %s", desc.Digest)
+		}
+		r := referrer{digest: desc.Digest.String()}
+		for _, l := range layers {
+			statement, err := statementFromLayer(l)
+			if err != nil {
+				return nil, errors.Wrapf(err, "reading referrer %s", desc.Digest)
+			}
+			if statement != nil {
+				r.statements = append(r.statements, statement)
+			}
+		}
+		if s.sortReferrers {
+			if r.created, err = referrerCreated(desc, img); err != nil {
+				return nil, errors.Wrapf(err, "fetching referrer %s", desc.Digest)
+			}
+		}
+		referrers = append(referrers, r)
+	}
+	if s.sortReferrers {
+		sortReferrers(referrers)
+	}
+
+	var statements []*intoto.Statement
+	for _, r := range referrers {
+		statements = append(statements, r.statements...)
+	}
+	return statements, nil
+}
+
+// manifestArtifactType returns the artifact type of the image manifest, which
+// defaults to the media type of its config.
+func manifestArtifactType(img v1.Image) (string, error) {
+	b, err := img.RawManifest()
+	if err != nil {
+		return "", err
+	}
+	var m struct {
+		ArtifactType string `json:"artifactType"`
+		Config       struct {
+			MediaType string `json:"mediaType"`
+		} `json:"config"`
+	}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return "", errors.Wrap(err, "decoding the manifest")
+	}
+	if m.ArtifactType != "" {
+		return m.ArtifactType, nil
+	}
+	return m.Config.MediaType, nil
+}
+
+// artifactTypeFilterTransport asks the registry to filter the referrers it
+// lists by artifact type. Registries that do not support filtering ignore the
+// parameter, so the listed referrers are filtered again by the client.
+type artifactTypeFilterTransport struct {
+	inner        http.RoundTripper
+	artifactType string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *artifactTypeFilterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet && strings.Contains(req.URL.Path, "/referrers/") {
+		req = req.Clone(req.Context())
+		q := req.URL.Query()
+		q.Set("artifactType", t.artifactType)
+		req.URL.RawQuery = q.Encode()
+	}
+	return t.inner.RoundTrip(req)
+}
+
+// statementFromLayer decodes a referrer layer holding a DSSE envelope or a
+// sigstore bundle. Layers of other media types are skipped.
+func statementFromLayer(l v1.Layer) (*intoto.Statement, error) {
+	mt, err := l.MediaType()
+	if err != nil {
+		return nil, err
+	}
+	if mt != types.DssePayloadType && !strings.HasPrefix(string(mt), sigstoreBundleMediaTypePrefix) {
+		return nil, nil
+	}
+	rc, err := l.Uncompressed()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	b, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+	if mt == types.DssePayloadType {
+		return statementFromEnvelope(b)
+	}
+	return statementFromBundle(b)
+}
+
+// statementFromEnvelope decodes the statement wrapped in a DSSE envelope.
+func statementFromEnvelope(b []byte) (*intoto.Statement, error) {
+	envelope := dsse.Envelope{}
+	if err := json.Unmarshal(b, &envelope); err != nil {
+		return nil, errors.Wrap(err, "decoding the envelope")
+	}
+	payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding the envelope payload")
+	}
+	return decodeStatement(payload)
+}
+
+// statementFromBundle decodes the statement wrapped in a sigstore bundle.
+func statementFromBundle(b []byte) (*intoto.Statement, error) {
+	bundle := &protobundle.Bundle{}
+	if err := protojson.Unmarshal(b, bundle); err != nil {
+		return nil, errors.Wrap(err, "decoding the bundle")
+	}
+	envelope := bundle.GetDsseEnvelope()
+	if envelope == nil {
+		return nil, errors.New("bundle does not contain a DSSE envelope")
+	}
+	return decodeStatement(envelope.GetPayload())
+}
+
+func decodeStatement(b []byte) (*intoto.Statement, error) {
+	statement := &intoto.Statement{}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(b, statement); err != nil {
+		return nil, errors.Wrap(err, "decoding the statement")
+	}
+	return statement, nil
+}
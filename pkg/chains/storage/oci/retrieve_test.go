@@ -0,0 +1,307 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/pkg/errors"
+	"github.com/secure-systems-lab/go-securesystemslib/dsse"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/sigstore/cosign/v2/pkg/types"
+	protobundle "github.com/sigstore/protobuf-specs/gen/pb-go/bundle/v1"
+	protodsse "github.com/sigstore/protobuf-specs/gen/pb-go/dsse"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/testing/protocmp"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func newTestStatement(t *testing.T, ref name.Digest, predicateType string) (*intoto.Statement, []byte) {
+	t.Helper()
+	statement := &intoto.Statement{
+		Type:          intoto.StatementTypeUri,
+		Subject:       []*intoto.ResourceDescriptor{subjectFromDigest(ref)},
+		PredicateType: predicateType,
+	}
+	b, err := protojson.Marshal(statement)
+	if err != nil {
+		t.Fatalf("failed to marshal statement: %v", err)
+	}
+	return statement, b
+}
+
+func newTestEnvelope(t *testing.T, payload []byte) []byte {
+	t.Helper()
+	b, err := json.Marshal(dsse.Envelope{
+		PayloadType: types.IntotoPayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures:  []dsse.Signature{{Sig: "c2lnbmF0dXJl"}},
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal envelope: %v", err)
+	}
+	return b
+}
+
+func TestAttestationStorer_Retrieve(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	s := httptest.NewServer(registry.New(registry.WithReferrersSupport(true)))
+	t.Cleanup(s.Close)
+	ref := pushRandomImage(t, strings.TrimPrefix(s.URL, "http://"))
+
+	storer, err := NewAttestationStorer(WithTargetRepository(ref.Repository))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+
+	// Referrer attestation, written first as it includes any attestations
+	// already attached to the entity.
+	referred, referredPayload := newTestStatement(t, ref, "https://example.com/referrer")
+	att, err := static.NewAttestation(newTestEnvelope(t, referredPayload), static.WithLayerMediaType(types.DssePayloadType))
+	if err != nil {
+		t.Fatalf("failed to create attestation: %v", err)
+	}
+	se, err := mutate.AttachAttestationToEntity(ociremote.SignedUnknown(ref), att)
+	if err != nil {
+		t.Fatalf("failed to attach attestation: %v", err)
+	}
+	if err := ociremote.WriteAttestationsReferrer(ref, se); err != nil {
+		t.Fatalf("failed to write referrer: %v", err)
+	}
+
+	// Sigstore bundle referrer.
+	bundled, bundledPayload := newTestStatement(t, ref, "https://example.com/bundle")
+	bundle, err := protojson.Marshal(&protobundle.Bundle{
+		MediaType: "application/vnd.dev.sigstore.bundle.v0.3+json",
+		Content: &protobundle.Bundle_DsseEnvelope{
+			DsseEnvelope: &protodsse.Envelope{
+				Payload:     bundledPayload,
+				PayloadType: types.IntotoPayloadType,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal bundle: %v", err)
+	}
+	if err := ociremote.WriteAttestationNewBundleFormat(ref, bundle, bundled.PredicateType); err != nil {
+		t.Fatalf("failed to write bundle: %v", err)
+	}
+
+	// Legacy tag based attestation.
+	tagged, taggedPayload := newTestStatement(t, ref, "https://example.com/tagged")
+	if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  tagged,
+		Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, taggedPayload)},
+	}); err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+
+	got, err := storer.Retrieve(ctx, ref)
+	if err != nil {
+		t.Fatalf("error during Retrieve(): %v", err)
+	}
+	want := []*intoto.Statement{tagged, referred, bundled}
+	less := func(a, b *intoto.Statement) bool { return a.GetPredicateType() < b.GetPredicateType() }
+	if diff := cmp.Diff(want, got, protocmp.Transform(), cmpopts.SortSlices(less)); diff != "" {
+		t.Errorf("unexpected statements (-want +got):\n%s", diff)
+	}
+}
+
+func TestAttestationStorer_RetrieveNotFound(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	registryName := newTestRegistry(t, nil)
+	ref := pushRandomImage(t, registryName)
+
+	storer, err := NewAttestationStorer()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	_, err = storer.Retrieve(ctx, ref)
+	var notFound *NotFoundError
+	if !errors.As(err, &notFound) {
+		t.Fatalf("Retrieve() error = %v, want a *NotFoundError", err)
+	}
+	if notFound.Artifact != ref {
+		t.Errorf("NotFoundError.Artifact = %s, want %s", notFound.Artifact, ref)
+	}
+}
+
+// writeTestReferrers attaches an in-toto referrer and a sigstore bundle
+// referrer to ref and returns their statements.
+func writeTestReferrers(t *testing.T, ref name.Digest) (referred, bundled *intoto.Statement) {
+	t.Helper()
+	referred, referredPayload := newTestStatement(t, ref, "https://example.com/referrer")
+	att, err := static.NewAttestation(newTestEnvelope(t, referredPayload), static.WithLayerMediaType(types.DssePayloadType))
+	if err != nil {
+		t.Fatalf("failed to create attestation: %v", err)
+	}
+	se, err := mutate.AttachAttestationToEntity(ociremote.SignedUnknown(ref), att)
+	if err != nil {
+		t.Fatalf("failed to attach attestation: %v", err)
+	}
+	if err := ociremote.WriteAttestationsReferrer(ref, se); err != nil {
+		t.Fatalf("failed to write referrer: %v", err)
+	}
+
+	bundled, bundledPayload := newTestStatement(t, ref, "https://example.com/bundle")
+	bundle, err := protojson.Marshal(&protobundle.Bundle{
+		MediaType: testBundleMediaType,
+		Content: &protobundle.Bundle_DsseEnvelope{
+			DsseEnvelope: &protodsse.Envelope{
+				Payload:     bundledPayload,
+				PayloadType: types.IntotoPayloadType,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal bundle: %v", err)
+	}
+	if err := ociremote.WriteAttestationNewBundleFormat(ref, bundle, bundled.PredicateType); err != nil {
+		t.Fatalf("failed to write bundle: %v", err)
+	}
+	return referred, bundled
+}
+
+// testBundleMediaType is the artifact type of the bundle referrers written by cosign.
+const testBundleMediaType = "application/vnd.dev.sigstore.bundle.v0.3+json"
+
+// conformantReferrers reports the artifact type of the manifests listed by
+// referrers requests, which the in-memory registry replaces with their config
+// media type. If filter is set, it also applies the artifactType filter and
+// counts the filtered requests in applied.
+func conformantReferrers(filter bool, applied *atomic.Int32) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			repo, _, ok := strings.Cut(r.URL.Path, "/referrers/")
+			if !ok {
+				h.ServeHTTP(w, r)
+				return
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, r)
+			var idx v1.IndexManifest
+			if err := json.Unmarshal(rec.Body.Bytes(), &idx); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			artifactType := r.URL.Query().Get("artifactType")
+			var manifests []v1.Descriptor
+			for _, desc := range idx.Manifests {
+				mrec := httptest.NewRecorder()
+				h.ServeHTTP(mrec, httptest.NewRequest(http.MethodGet, repo+"/manifests/"+desc.Digest.String(), nil))
+				var m struct {
+					ArtifactType string `json:"artifactType"`
+				}
+				if err := json.Unmarshal(mrec.Body.Bytes(), &m); err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				if m.ArtifactType != "" {
+					desc.ArtifactType = m.ArtifactType
+				}
+				if filter && artifactType != "" && desc.ArtifactType != artifactType {
+					continue
+				}
+				manifests = append(manifests, desc)
+			}
+			idx.Manifests = manifests
+			if filter && artifactType != "" {
+				applied.Add(1)
+				w.Header().Set("OCI-Filters-Applied", "artifactType")
+			}
+			w.Header().Set("Content-Type", rec.Header().Get("Content-Type"))
+			if err := json.NewEncoder(w).Encode(idx); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+		})
+	}
+}
+
+func TestAttestationStorer_RetrieveByArtifactType(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		middleware func(applied *atomic.Int32) func(http.Handler) http.Handler
+		filtered   bool
+	}{{
+		name: "registry reporting config media types",
+	}, {
+		name: "client-side filtering",
+		middleware: func(applied *atomic.Int32) func(http.Handler) http.Handler {
+			return conformantReferrers(false, applied)
+		},
+	}, {
+		name: "server-side filtering",
+		middleware: func(applied *atomic.Int32) func(http.Handler) http.Handler {
+			return conformantReferrers(true, applied)
+		},
+		filtered: true,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := logtesting.TestContextWithLogger(t)
+			var applied atomic.Int32
+			h := registry.New(registry.WithReferrersSupport(true))
+			if tc.middleware != nil {
+				h = tc.middleware(&applied)(h)
+			}
+			s := httptest.NewServer(h)
+			t.Cleanup(s.Close)
+			ref := pushRandomImage(t, strings.TrimPrefix(s.URL, "http://"))
+			referred, bundled := writeTestReferrers(t, ref)
+
+			storer, err := NewAttestationStorer()
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+			for artifactType, want := range map[string]*intoto.Statement{
+				types.IntotoPayloadType: referred,
+				testBundleMediaType:     bundled,
+			} {
+				got, err := storer.RetrieveByArtifactType(ctx, ref, artifactType)
+				if err != nil {
+					t.Fatalf("error during RetrieveByArtifactType(%s): %v", artifactType, err)
+				}
+				if diff := cmp.Diff([]*intoto.Statement{want}, got, protocmp.Transform()); diff != "" {
+					t.Errorf("unexpected statements for %s (-want +got):\n%s", artifactType, diff)
+				}
+			}
+			if tc.filtered && applied.Load() == 0 {
+				t.Error("expected the registry to filter the referrers")
+			}
+
+			_, err = storer.RetrieveByArtifactType(ctx, ref, "application/vnd.example.sbom")
+			var notFound *NotFoundError
+			if !errors.As(err, &notFound) {
+				t.Errorf("RetrieveByArtifactType() error = %v, want a *NotFoundError", err)
+			}
+		})
+	}
+}
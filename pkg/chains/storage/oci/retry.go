@@ -0,0 +1,216 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/pkg/errors"
+	"knative.dev/pkg/logging"
+)
+
+const (
+	retryAfterHeader = "Retry-After"
+
+	// defaultMaxRetryAfter caps the wait requested through Retry-After unless
+	// configured with WithMaxRetryAfter.
+	defaultMaxRetryAfter = time.Minute
+
+	// defaultMaxRetryDelay caps the exponential delay between write attempts
+	// unless configured with WithMaxRetryDelay.
+	defaultMaxRetryDelay = time.Minute
+)
+
+// writeRetry configures retries of the registry writes.
+type writeRetry struct {
+	// maxAttempts is the total number of write attempts.
+	maxAttempts int
+	// baseDelay is the delay before the first retry, doubled after each retry.
+	baseDelay time.Duration
+	// retryAfter records the waits requested by the registries.
+	retryAfter *retryAfterTracker
+
+	sleep func(context.Context, time.Duration) error
+}
+
+func newWriteRetry(maxAttempts int, baseDelay time.Duration) *writeRetry {
+	return &writeRetry{
+		maxAttempts: maxAttempts,
+		baseDelay:   baseDelay,
+		retryAfter:  newRetryAfterTracker(),
+		sleep:       sleepContext,
+	}
+}
+
+// delay returns the wait before the given retry, starting at 1, capped at
+// maxDelay. Half of the exponential delay is randomized so that storers
+// failing together do not retry in lockstep.
+func (r *writeRetry) delay(retry int, maxDelay time.Duration) time.Duration {
+	d := maxDelay
+	// Shift only while the delay stays below the cap, so that it cannot
+	// overflow however many attempts are configured.
+	if shift := retry - 1; shift < 63 && r.baseDelay <= maxDelay>>shift {
+		d = r.baseDelay << shift
+	}
+	if d <= 1 {
+		return d
+	}
+	return d/2 + rand.N(d/2)
+}
+
+// retryWrite calls write, retrying transient registry errors as configured by
+// WithRetry. The context is checked between attempts. If a store limiter is
+// configured, a slot is held for all attempts. The error of the last attempt
+// is classified as described by classifyError.
+func (b *baseStorer) retryWrite(ctx context.Context, what string, write func() error) (err error) {
+	ctx, span := startSpan(ctx, "oci.write", writeAttribute.String(what))
+	defer func() { endSpan(span, err) }()
+
+	if b.storeLimiter != nil {
+		if err := b.storeLimiter.Acquire(ctx, 1); err != nil {
+			return errors.Wrapf(err, "waiting to write %s", what)
+		}
+		defer b.storeLimiter.Release(1)
+	}
+	if b.writeRetry == nil {
+		return classifyError(write())
+	}
+	maxRetryAfter := b.maxRetryAfter
+	if maxRetryAfter == 0 {
+		maxRetryAfter = defaultMaxRetryAfter
+	}
+	maxDelay := b.maxRetryDelay
+	if maxDelay == 0 {
+		maxDelay = defaultMaxRetryDelay
+	}
+	for attempt := 1; ; attempt++ {
+		err := write()
+		if err == nil || attempt >= b.writeRetry.maxAttempts || !isTransientError(err) {
+			return classifyError(err)
+		}
+		delay := b.writeRetry.delay(attempt, maxDelay)
+		if wait, ok := b.writeRetry.retryAfter.take(err); ok {
+			delay = max(delay, min(wait, maxRetryAfter))
+		}
+		logging.FromContext(ctx).Warnf("Writing %s failed on attempt %d of %d, retrying in %s: %v", what, attempt, b.writeRetry.maxAttempts, delay, err)
+		b.metrics.observeRetry()
+		if err := b.writeRetry.sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// retryAfterTracker records, per registry host, the wait requested through the
+// Retry-After header of the last 429 or 503 response. The header is not
+// available from the errors returned by the registry client, so it is captured
+// by a transport instead.
+type retryAfterTracker struct {
+	mu sync.Mutex
+	// resumeAt holds the time before which requests should not be retried.
+	resumeAt map[string]time.Time
+
+	now func() time.Time
+}
+
+func newRetryAfterTracker() *retryAfterTracker {
+	return &retryAfterTracker{
+		resumeAt: map[string]time.Time{},
+		now:      time.Now,
+	}
+}
+
+// wrap returns a RoundTripper that records the Retry-After headers of the
+// responses received through rt.
+func (t *retryAfterTracker) wrap(rt http.RoundTripper) http.RoundTripper {
+	return &retryAfterTransport{inner: rt, tracker: t}
+}
+
+// observe records the wait requested by a rate limited or unavailable response.
+func (t *retryAfterTracker) observe(host string, resp *http.Response) {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return
+	}
+	now := t.now()
+	wait, ok := parseRetryAfter(resp.Header.Get(retryAfterHeader), now)
+	if !ok {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.resumeAt[host] = now.Add(wait)
+}
+
+// take returns the remaining wait requested by the host that caused err, if
+// any, and forgets it.
+func (t *retryAfterTracker) take(err error) (time.Duration, bool) {
+	var terr *transport.Error
+	if !errors.As(err, &terr) || terr.Request == nil || terr.Request.URL == nil {
+		return 0, false
+	}
+	if terr.StatusCode != http.StatusTooManyRequests && terr.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+	host := terr.Request.URL.Host
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	resumeAt, ok := t.resumeAt[host]
+	if !ok {
+		return 0, false
+	}
+	delete(t.resumeAt, host)
+	return max(resumeAt.Sub(t.now()), 0), true
+}
+
+// parseRetryAfter parses a Retry-After header value, given either as a number
+// of seconds or as an HTTP date.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	date, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	return max(date.Sub(now), 0), true
+}
+
+type retryAfterTransport struct {
+	inner   http.RoundTripper
+	tracker *retryAfterTracker
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *retryAfterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.inner.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	t.tracker.observe(req.URL.Host, resp)
+	return resp, nil
+}
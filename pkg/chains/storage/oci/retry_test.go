@@ -0,0 +1,324 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/pkg/errors"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// failingWriteTransport fails the first failures manifest uploads with status.
+type failingWriteTransport struct {
+	inner    http.RoundTripper
+	status   int
+	header   http.Header
+	failures int32
+	failed   atomic.Int32
+}
+
+func (t *failingWriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodPut && strings.Contains(req.URL.Path, "/manifests/") && t.failed.Load() < t.failures {
+		t.failed.Add(1)
+		header := t.header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			StatusCode: t.status,
+			Header:     header,
+			Body:       io.NopCloser(strings.NewReader("")),
+			Request:    req,
+		}, nil
+	}
+	return t.inner.RoundTrip(req)
+}
+
+func TestWithRetry(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		failures   int32
+		opts       []Option
+		wantErr    bool
+		wantFailed int32
+	}{
+		{
+			name:       "no retry",
+			status:     http.StatusTooManyRequests,
+			failures:   2,
+			wantErr:    true,
+			wantFailed: 1,
+		},
+		{
+			name:       "429 retried",
+			status:     http.StatusTooManyRequests,
+			failures:   2,
+			opts:       []Option{WithRetry(3, time.Millisecond)},
+			wantFailed: 2,
+		},
+		{
+			name:       "503 retried",
+			status:     http.StatusServiceUnavailable,
+			failures:   2,
+			opts:       []Option{WithRetry(3, time.Millisecond)},
+			wantFailed: 2,
+		},
+		{
+			name:       "retries exhausted",
+			status:     http.StatusTooManyRequests,
+			failures:   3,
+			opts:       []Option{WithRetry(3, time.Millisecond)},
+			wantErr:    true,
+			wantFailed: 3,
+		},
+		{
+			name:       "401 not retried",
+			status:     http.StatusUnauthorized,
+			failures:   2,
+			opts:       []Option{WithRetry(3, time.Millisecond)},
+			wantErr:    true,
+			wantFailed: 1,
+		},
+		{
+			name:       "403 not retried",
+			status:     http.StatusForbidden,
+			failures:   2,
+			opts:       []Option{WithRetry(3, time.Millisecond)},
+			wantErr:    true,
+			wantFailed: 1,
+		},
+		{
+			name:       "400 not retried",
+			status:     http.StatusBadRequest,
+			failures:   2,
+			opts:       []Option{WithRetry(3, time.Millisecond)},
+			wantErr:    true,
+			wantFailed: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := logtesting.TestContextWithLogger(t)
+			ref := pushRandomImage(t, newTestRegistry(t, nil))
+
+			t.Run("attestation", func(t *testing.T) {
+				rt := &failingWriteTransport{inner: http.DefaultTransport, status: tt.status, failures: tt.failures}
+				opts := append([]AttestationStorerOption{WithTargetRepository(ref.Repository)}, optionsAs[AttestationStorerOption](tt.opts)...)
+				storer, err := NewAttestationStorer(opts...)
+				if err != nil {
+					t.Fatalf("failed to create storer: %v", err)
+				}
+				// Disable the retries of the registry client so only WithRetry applies.
+				storer.transport = rt
+				storer.remoteOpts = []remote.Option{remote.WithRetryBackoff(remote.Backoff{Steps: 1}), remote.WithRetryStatusCodes()}
+
+				_, err = storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+					Artifact: ref,
+					Payload:  &intoto.Statement{},
+					Bundle:   &signing.Bundle{},
+				})
+				if gotErr := err != nil; gotErr != tt.wantErr {
+					t.Errorf("Store() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if got := rt.failed.Load(); got != tt.wantFailed {
+					t.Errorf("got %d failed writes, want %d", got, tt.wantFailed)
+				}
+			})
+
+			t.Run("simple", func(t *testing.T) {
+				rt := &failingWriteTransport{inner: http.DefaultTransport, status: tt.status, failures: tt.failures}
+				opts := append([]SimpleStorerOption{WithTargetRepository(ref.Repository)}, optionsAs[SimpleStorerOption](tt.opts)...)
+				storer, err := NewSimpleStorerFromConfig(opts...)
+				if err != nil {
+					t.Fatalf("failed to create storer: %v", err)
+				}
+				storer.transport = rt
+				storer.remoteOpts = []remote.Option{remote.WithRetryBackoff(remote.Backoff{Steps: 1}), remote.WithRetryStatusCodes()}
+
+				_, err = storer.Store(ctx, &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+					Artifact: ref,
+					Payload:  simple.NewSimpleStruct(ref),
+					Bundle:   &signing.Bundle{},
+				})
+				if gotErr := err != nil; gotErr != tt.wantErr {
+					t.Errorf("Store() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if got := rt.failed.Load(); got != tt.wantFailed {
+					t.Errorf("got %d failed writes, want %d", got, tt.wantFailed)
+				}
+			})
+		})
+	}
+}
+
+func TestWithRetry_ContextCanceled(t *testing.T) {
+	ref := pushRandomImage(t, newTestRegistry(t, nil))
+	rt := &failingWriteTransport{inner: http.DefaultTransport, status: http.StatusTooManyRequests, failures: 5}
+
+	storer, err := NewAttestationStorer(WithTargetRepository(ref.Repository), WithRetry(5, time.Hour))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	storer.transport = rt
+	storer.remoteOpts = []remote.Option{remote.WithRetryBackoff(remote.Backoff{Steps: 1}), remote.WithRetryStatusCodes()}
+
+	ctx, cancel := context.WithTimeout(logtesting.TestContextWithLogger(t), 50*time.Millisecond)
+	defer cancel()
+	_, err = storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  &intoto.Statement{},
+		Bundle:   &signing.Bundle{},
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Store() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if got := rt.failed.Load(); got != 1 {
+		t.Errorf("got %d failed writes, want 1", got)
+	}
+}
+
+func TestWithRetry_RetryAfter(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		opts      []AttestationStorerOption
+		wantDelay time.Duration
+	}{
+		{
+			name:      "429 honors Retry-After",
+			status:    http.StatusTooManyRequests,
+			wantDelay: 2 * time.Second,
+		},
+		{
+			name:      "503 honors Retry-After",
+			status:    http.StatusServiceUnavailable,
+			wantDelay: 2 * time.Second,
+		},
+		{
+			name:      "capped by WithMaxRetryAfter",
+			status:    http.StatusTooManyRequests,
+			opts:      []AttestationStorerOption{WithMaxRetryAfter(500 * time.Millisecond)},
+			wantDelay: 500 * time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref := pushRandomImage(t, newTestRegistry(t, nil))
+			rt := &failingWriteTransport{
+				inner:    http.DefaultTransport,
+				status:   tt.status,
+				header:   http.Header{retryAfterHeader: []string{"2"}},
+				failures: 1,
+			}
+
+			opts := append([]AttestationStorerOption{WithTargetRepository(ref.Repository), WithRetry(2, time.Millisecond)}, tt.opts...)
+			storer, err := NewAttestationStorer(opts...)
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+			storer.transport = rt
+			storer.remoteOpts = []remote.Option{remote.WithRetryBackoff(remote.Backoff{Steps: 1}), remote.WithRetryStatusCodes()}
+			var delays []time.Duration
+			storer.writeRetry.sleep = func(_ context.Context, d time.Duration) error {
+				delays = append(delays, d)
+				return nil
+			}
+
+			if _, err := storer.Store(logtesting.TestContextWithLogger(t), &api.StoreRequest[name.Digest, *intoto.Statement]{
+				Artifact: ref,
+				Payload:  &intoto.Statement{},
+				Bundle:   &signing.Bundle{},
+			}); err != nil {
+				t.Fatalf("error during Store(): %v", err)
+			}
+			if len(delays) != 1 {
+				t.Fatalf("got %d retries, want 1", len(delays))
+			}
+			// The wait is measured from the response, so allow for the time spent since.
+			if delays[0] > tt.wantDelay || delays[0] < tt.wantDelay-time.Second/2 {
+				t.Errorf("got delay %s, want about %s", delays[0], tt.wantDelay)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{value: "2", want: 2 * time.Second, wantOK: true},
+		{value: " 120 ", want: 2 * time.Minute, wantOK: true},
+		{value: now.Add(30 * time.Second).Format(http.TimeFormat), want: 30 * time.Second, wantOK: true},
+		{value: now.Add(-time.Minute).Format(http.TimeFormat), want: 0, wantOK: true},
+		{value: ""},
+		{value: "-1"},
+		{value: "soon"},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parseRetryAfter(%q) = %s, %t, want %s, %t", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestWriteRetry_Delay(t *testing.T) {
+	r := newWriteRetry(1000, time.Second)
+	if d := r.delay(1, time.Minute); d < time.Second/2 || d > time.Second {
+		t.Errorf("delay(1) = %s, want between 500ms and 1s", d)
+	}
+	// The delay is capped instead of overflowing for high attempt counts.
+	for _, retry := range []int{7, 30, 63, 64, 100, 1000} {
+		if d := r.delay(retry, time.Minute); d < 30*time.Second || d > time.Minute {
+			t.Errorf("delay(%d) = %s, want between 30s and 1m", retry, d)
+		}
+	}
+	if d := newWriteRetry(2, time.Hour).delay(1, time.Minute); d > time.Minute {
+		t.Errorf("delay(1) = %s with a base delay above the cap, want at most 1m", d)
+	}
+}
+
+func TestWithRetry_Invalid(t *testing.T) {
+	for _, o := range []Option{WithRetry(0, time.Second), WithRetry(2, -time.Second), WithMaxRetryAfter(0), WithMaxRetryDelay(0)} {
+		if _, err := NewAttestationStorer(o); err == nil {
+			t.Errorf("expected an error for option %+v", o)
+		}
+	}
+}
+
+func optionsAs[T any](opts []Option) []T {
+	out := make([]T, 0, len(opts))
+	for _, o := range opts {
+		out = append(out, o.(T))
+	}
+	return out
+}
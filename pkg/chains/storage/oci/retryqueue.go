@@ -0,0 +1,182 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+)
+
+const (
+	retryKindAttestation = "attestation"
+	retryKindSignature   = "signature"
+
+	retryEntryExt = ".json"
+)
+
+// isRetryable reports whether a failed store is persisted to the durable retry
+// queue: stores the registry failed transiently or did not answer in time.
+// Stores that were rejected, e.g. for missing credentials or an oversized
+// payload, would fail the same way on replay.
+func isRetryable(err error) bool {
+	var transient *TransientError
+	var timeout *TimeoutError
+	return errors.As(err, &transient) || errors.As(err, &timeout)
+}
+
+// retryEntry is a failed store persisted to the durable retry queue.
+type retryEntry struct {
+	// Kind identifies the storer that should replay the entry.
+	Kind string `json:"kind"`
+	// Artifact is the digest reference of the artifact.
+	Artifact string `json:"artifact"`
+	// Payload is the JSON encoded payload of the store request.
+	Payload json.RawMessage `json:"payload,omitempty"`
+	// Bundle is the signing output of the store request.
+	Bundle *signing.Bundle `json:"bundle"`
+}
+
+// retryQueue persists failed stores to a directory so that they survive
+// controller restarts. Entries are named after the hash of their content, so
+// persisting the same failed store twice results in a single entry.
+type retryQueue struct {
+	dir string
+}
+
+func newRetryQueue(dir string) (*retryQueue, error) {
+	if dir == "" {
+		return nil, errors.New("retry queue path must not be empty")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, errors.Wrapf(err, "creating retry queue directory %q", dir)
+	}
+	return &retryQueue{dir: dir}, nil
+}
+
+// persist writes the entry to the queue.
+func (q *retryQueue) persist(e *retryEntry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return errors.Wrap(err, "encoding retry entry")
+	}
+	sum := sha256.Sum256(b)
+	path := filepath.Join(q.dir, hex.EncodeToString(sum[:])+retryEntryExt)
+
+	// Write to a temporary file first so a crash never leaves a partial entry behind.
+	tmp, err := os.CreateTemp(q.dir, ".entry-*")
+	if err != nil {
+		return errors.Wrap(err, "creating retry entry")
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "writing retry entry")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "writing retry entry")
+	}
+	return errors.Wrap(os.Rename(tmp.Name(), path), "writing retry entry")
+}
+
+// entries returns the paths and contents of the queued entries of the given kind.
+func (q *retryQueue) entries(kind string) ([]string, []*retryEntry, error) {
+	files, err := os.ReadDir(q.dir)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "reading retry queue")
+	}
+	var paths []string
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), retryEntryExt) {
+			continue
+		}
+		paths = append(paths, filepath.Join(q.dir, f.Name()))
+	}
+	sort.Strings(paths)
+
+	var (
+		matched []string
+		entries []*retryEntry
+	)
+	for _, p := range paths {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "reading retry entry %q", p)
+		}
+		e := &retryEntry{}
+		if err := json.Unmarshal(b, e); err != nil {
+			return nil, nil, errors.Wrapf(err, "decoding retry entry %q", p)
+		}
+		if e.Kind != kind {
+			continue
+		}
+		matched = append(matched, p)
+		entries = append(entries, e)
+	}
+	return matched, entries, nil
+}
+
+func (q *retryQueue) remove(path string) error {
+	return errors.Wrapf(os.Remove(path), "removing retry entry %q", path)
+}
+
+func (e *retryEntry) artifact() (name.Digest, error) {
+	d, err := parseDigest(e.Artifact)
+	return d, errors.Wrapf(err, "parsing retry entry artifact %q", e.Artifact)
+}
+
+// replayDupeDetector finds an existing signature that is identical to the one
+// being attached, so that replaying a store that did reach the registry before
+// it was reported as failed does not attach a duplicate.
+type replayDupeDetector struct{}
+
+// Find implements mutate.DupeDetector.
+func (replayDupeDetector) Find(sigs oci.Signatures, sig oci.Signature) (oci.Signature, error) {
+	want, err := sig.Digest()
+	if err != nil {
+		return nil, err
+	}
+	wantSig, err := sig.Base64Signature()
+	if err != nil {
+		return nil, err
+	}
+	existing, err := sigs.Get()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range existing {
+		got, err := e.Digest()
+		if err != nil {
+			return nil, err
+		}
+		gotSig, err := e.Base64Signature()
+		if err != nil {
+			return nil, err
+		}
+		if got == want && gotSig == wantSig {
+			return e, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,229 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"net/http"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/pkg/errors"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// newFlakyRegistry returns a registry that is unavailable while the returned
+// flag is set.
+func newFlakyRegistry(t *testing.T) (string, *atomic.Bool) {
+	t.Helper()
+	var down atomic.Bool
+	registryName := newTestRegistry(t, func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if down.Load() {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	})
+	return registryName, &down
+}
+
+func countQueued(t *testing.T, dir string) int {
+	t.Helper()
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read retry queue: %v", err)
+	}
+	return len(files)
+}
+
+func countStored(t *testing.T, ref name.Digest, get func(oci.SignedEntity) (oci.Signatures, error)) int {
+	t.Helper()
+	se, err := ociremote.SignedEntity(ref)
+	if err != nil {
+		t.Fatalf("failed to get signed entity: %v", err)
+	}
+	sigs, err := get(se)
+	if err != nil {
+		t.Fatalf("failed to get signatures: %v", err)
+	}
+	l, err := sigs.Get()
+	if err != nil {
+		t.Fatalf("failed to list signatures: %v", err)
+	}
+	return len(l)
+}
+
+func TestAttestationStorer_DurableRetryQueue(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	registryName, down := newFlakyRegistry(t)
+	ref := pushRandomImage(t, registryName)
+	dir := t.TempDir()
+
+	storer, err := NewAttestationStorer(WithTargetRepository(ref.Repository), WithDurableRetryQueue(dir))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	req := &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload: &intoto.Statement{
+			Subject:       []*intoto.ResourceDescriptor{subjectFromDigest(ref)},
+			PredicateType: "https://slsa.dev/provenance/v1",
+		},
+		Bundle: &signing.Bundle{Signature: []byte("envelope")},
+	}
+
+	down.Store(true)
+	for i := 0; i < 2; i++ {
+		if _, err := storer.Store(ctx, req); err == nil {
+			t.Fatal("expected Store() to fail while the registry is unavailable")
+		}
+	}
+	if got := countQueued(t, dir); got != 1 {
+		t.Fatalf("got %d queued entries, want 1", got)
+	}
+
+	// Simulate a controller restart with a new storer sharing the queue.
+	down.Store(false)
+	restarted, err := NewAttestationStorer(WithTargetRepository(ref.Repository), WithDurableRetryQueue(dir))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	if err := restarted.ReplayRetryQueue(ctx); err != nil {
+		t.Fatalf("error during ReplayRetryQueue(): %v", err)
+	}
+	if got := countQueued(t, dir); got != 0 {
+		t.Errorf("got %d queued entries after replay, want 0", got)
+	}
+	if got := countStored(t, ref, oci.SignedEntity.Attestations); got != 1 {
+		t.Errorf("got %d attestations, want 1", got)
+	}
+
+	// Replaying a store that already reached the registry does not duplicate it.
+	restarted.enqueueRetry(ctx, req)
+	if err := restarted.ReplayRetryQueue(ctx); err != nil {
+		t.Fatalf("error during ReplayRetryQueue(): %v", err)
+	}
+	if got := countStored(t, ref, oci.SignedEntity.Attestations); got != 1 {
+		t.Errorf("got %d attestations after repeated replay, want 1", got)
+	}
+}
+
+func TestSimpleStorer_DurableRetryQueue(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	registryName, down := newFlakyRegistry(t)
+	ref := pushRandomImage(t, registryName)
+	dir := t.TempDir()
+
+	storer, err := NewSimpleStorerFromConfig(WithTargetRepository(ref.Repository), WithDurableRetryQueue(dir))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	req := &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+		Artifact: ref,
+		Payload:  simple.NewSimpleStruct(ref),
+		Bundle:   &signing.Bundle{Content: []byte("payload"), Signature: []byte("signature")},
+	}
+
+	down.Store(true)
+	if _, err := storer.Store(ctx, req); err == nil {
+		t.Fatal("expected Store() to fail while the registry is unavailable")
+	}
+	if got := countQueued(t, dir); got != 1 {
+		t.Fatalf("got %d queued entries, want 1", got)
+	}
+
+	down.Store(false)
+	restarted, err := NewSimpleStorerFromConfig(WithTargetRepository(ref.Repository), WithDurableRetryQueue(dir))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	if err := restarted.ReplayRetryQueue(ctx); err != nil {
+		t.Fatalf("error during ReplayRetryQueue(): %v", err)
+	}
+	if got := countQueued(t, dir); got != 0 {
+		t.Errorf("got %d queued entries after replay, want 0", got)
+	}
+	if got := countStored(t, ref, oci.SignedEntity.Signatures); got != 1 {
+		t.Errorf("got %d signatures, want 1", got)
+	}
+}
+
+func TestDurableRetryQueue_PermanentFailure(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	var readOnly atomic.Bool
+	registryName := newTestRegistry(t, func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if readOnly.Load() && r.Method != http.MethodGet && r.Method != http.MethodHead {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	})
+	ref := pushRandomImage(t, registryName)
+	readOnly.Store(true)
+	dir := t.TempDir()
+
+	storer, err := NewAttestationStorer(WithDurableRetryQueue(dir))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	statement, payload := newTestStatement(t, ref, "https://slsa.dev/provenance/v1")
+	if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  statement,
+		Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+	}); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("Store() error = %v, want %v", err, ErrUnauthorized)
+	}
+	if got := countQueued(t, dir); got != 0 {
+		t.Errorf("got %d queued entries for a rejected store, want 0", got)
+	}
+}
+
+func TestDurableRetryQueue_ReplayFailureKeepsEntry(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	registryName, down := newFlakyRegistry(t)
+	ref := pushRandomImage(t, registryName)
+	dir := t.TempDir()
+
+	storer, err := NewSimpleStorerFromConfig(WithTargetRepository(ref.Repository), WithDurableRetryQueue(dir))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	down.Store(true)
+	if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+		Artifact: ref,
+		Payload:  simple.NewSimpleStruct(ref),
+		Bundle:   &signing.Bundle{Signature: []byte("signature")},
+	}); err == nil {
+		t.Fatal("expected Store() to fail while the registry is unavailable")
+	}
+	if err := storer.ReplayRetryQueue(ctx); err == nil {
+		t.Error("expected ReplayRetryQueue() to fail while the registry is unavailable")
+	}
+	if got := countQueued(t, dir); got != 1 {
+		t.Errorf("got %d queued entries, want 1", got)
+	}
+}
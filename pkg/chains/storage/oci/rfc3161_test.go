@@ -0,0 +1,107 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestWithRFC3161Timestamp(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	ref := pushRandomImage(t, newTestRegistry(t, nil))
+	// Any DER sequence stands in for a timestamp response, which is not parsed.
+	token, err := asn1.Marshal(struct {
+		Status  int
+		Payload []byte
+	}{Status: 0, Payload: []byte("timestamp-token")})
+	if err != nil {
+		t.Fatalf("failed to marshal the token: %v", err)
+	}
+
+	as, err := NewAttestationStorer(WithRFC3161Timestamp(token))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	statement, payload := newTestStatement(t, ref, "https://example.com/predicate")
+	if _, err := as.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  statement,
+		Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+	}); err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+	ss, err := NewSimpleStorerFromConfig(WithRFC3161Timestamp(token))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	if _, err := ss.Store(ctx, &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+		Artifact: ref,
+		Payload:  simple.NewSimpleStruct(ref),
+		Bundle:   &signing.Bundle{Content: []byte("{}"), Signature: []byte("signature")},
+	}); err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+
+	se, err := ociremote.SignedEntity(ref)
+	if err != nil {
+		t.Fatalf("failed to get signed entity: %v", err)
+	}
+	atts, err := se.Attestations()
+	if err != nil {
+		t.Fatalf("failed to get attestations: %v", err)
+	}
+	sigs, err := se.Signatures()
+	if err != nil {
+		t.Fatalf("failed to get signatures: %v", err)
+	}
+	for what, s := range map[string]oci.Signatures{"attestation": atts, "signature": sigs} {
+		layers, err := s.Get()
+		if err != nil || len(layers) != 1 {
+			t.Fatalf("failed to get the %s: %d layers, %v", what, len(layers), err)
+		}
+		got, err := layers[0].RFC3161Timestamp()
+		if err != nil {
+			t.Fatalf("failed to read the %s timestamp: %v", what, err)
+		}
+		if got == nil || !bytes.Equal(got.SignedRFC3161Timestamp, token) {
+			t.Errorf("unexpected %s timestamp: got %v, want %x", what, got, token)
+		}
+	}
+}
+
+func TestWithRFC3161Timestamp_Invalid(t *testing.T) {
+	for name, token := range map[string][]byte{
+		"empty":          nil,
+		"not DER":        []byte("timestamp-token"),
+		"trailing bytes": append([]byte{0x30, 0x00}, 0xff),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewAttestationStorer(WithRFC3161Timestamp(token)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
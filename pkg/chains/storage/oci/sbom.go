@@ -0,0 +1,224 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/pkg/errors"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+)
+
+const (
+	// predicateTypeAnnotation holds the predicate type of an attestation, as
+	// set by cosign attest.
+	predicateTypeAnnotation = "predicateType"
+	// sbomMediaTypeAnnotation holds the media type of the SBOM carried in the
+	// predicate of an attestation.
+	sbomMediaTypeAnnotation = "dev.tekton.chains/sbom-media-type"
+)
+
+// sbomFormat describes an SBOM format carried in attestation predicates.
+type sbomFormat struct {
+	// predicateType is the canonical predicate type of the format.
+	predicateType string
+	// mediaType is the media type of documents in the format.
+	mediaType string
+}
+
+var (
+	spdxFormat = sbomFormat{
+		predicateType: "https://spdx.dev/Document",
+		mediaType:     "application/spdx+json",
+	}
+	cycloneDXFormat = sbomFormat{
+		predicateType: "https://cyclonedx.org/bom",
+		mediaType:     "application/vnd.cyclonedx+json",
+	}
+)
+
+// sbomFormatOf detects the SBOM format of the attestation to store, from its
+// predicate type or, for generic predicate types, from the predicate itself.
+func sbomFormatOf(req *api.StoreRequest[name.Digest, *intoto.Statement]) (sbomFormat, bool) {
+	statement := requestStatement(req)
+	pt := statement.GetPredicateType()
+	for _, f := range []sbomFormat{spdxFormat, cycloneDXFormat} {
+		// Versioned predicate types, e.g. https://spdx.dev/Document/v2.3,
+		// share the prefix of the canonical one.
+		if pt == f.predicateType || strings.HasPrefix(pt, f.predicateType+"/") {
+			return f, true
+		}
+	}
+	fields := statement.GetPredicate().GetFields()
+	if _, ok := fields["spdxVersion"]; ok {
+		return spdxFormat, true
+	}
+	if fields["bomFormat"].GetStringValue() == "CycloneDX" {
+		return cycloneDXFormat, true
+	}
+	return sbomFormat{}, false
+}
+
+// addSBOMAnnotations records the SBOM format of the attestation to store, if
+// it carries one.
+func addSBOMAnnotations(annotations map[string]string, req *api.StoreRequest[name.Digest, *intoto.Statement]) {
+	f, ok := sbomFormatOf(req)
+	if !ok {
+		return
+	}
+	annotations[predicateTypeAnnotation] = f.predicateType
+	annotations[sbomMediaTypeAnnotation] = f.mediaType
+}
+
+// requestStatement returns the statement of the request or, if it carries
+// none, the statement signed in its envelope. Nil is returned if the envelope
+// cannot be decoded.
+func requestStatement(req *api.StoreRequest[name.Digest, *intoto.Statement]) *intoto.Statement {
+	if req.Payload != nil {
+		return req.Payload
+	}
+	statement, _ := statementFromEnvelope(req.Bundle.Signature)
+	return statement
+}
+
+// SBOMInfo describes an SBOM stored as a referrer of an artifact.
+type SBOMInfo struct {
+	// MediaType is the media type of the SBOM, e.g. application/spdx+json.
+	MediaType string
+	// Digest is the digest of the layer holding the SBOM.
+	Digest string
+	// ManifestDigest is the digest of the referrer manifest holding the SBOM.
+	ManifestDigest string
+	// Created is the creation time recorded for the SBOM, or the zero time if
+	// none is recorded.
+	Created time.Time
+	// Annotations are the annotations of the layer holding the SBOM.
+	Annotations map[string]string
+}
+
+// isSBOMMediaType reports whether mediaType is the media type of a supported
+// SBOM format.
+func isSBOMMediaType(mediaType string) bool {
+	return mediaType == spdxFormat.mediaType || mediaType == cycloneDXFormat.mediaType
+}
+
+// StoreSBOM writes the SBOM document sbom, of the given media type, as a
+// referrer of the artifact in the target repository, so that it can be found
+// through the referrers API. The SPDX (application/spdx+json) and CycloneDX
+// (application/vnd.cyclonedx+json) formats are supported. The referrer has the
+// media type of the SBOM as its artifact type, and its layer records the media
+// type in the dev.tekton.chains/sbom-media-type annotation. The SBOM is not
+// signed; sign it as an attestation to vouch for it. SBOMs are listed with
+// ListSBOMs, not with List or Retrieve.
+func (s *AttestationStorer) StoreSBOM(ctx context.Context, artifact name.Digest, sbom []byte, mediaType string) (*api.StoreResponse, error) {
+	if hs := s.hostStorer(artifact); hs != nil {
+		return hs.StoreSBOM(ctx, artifact, sbom, mediaType)
+	}
+	if s.localLayout != "" {
+		return nil, errors.New("SBOMs cannot be stored as referrers in an OCI layout")
+	}
+	if !isSBOMMediaType(mediaType) {
+		return nil, errors.Errorf("unsupported SBOM media type %q", mediaType)
+	}
+	if len(sbom) == 0 {
+		return nil, errors.New("SBOM must not be empty")
+	}
+	if err := s.checkPayloadSize(artifact, len(sbom)); err != nil {
+		return nil, err
+	}
+	repo := s.targetRepository(artifact)
+	subject, err := remote.Head(artifact, s.remoteOptions(ctx)...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetching %s", artifact.String())
+	}
+	l := static.NewLayer(sbom, types.MediaType(mediaType))
+	ann := map[string]string{
+		sbomMediaTypeAnnotation: mediaType,
+	}
+	created := s.now().UTC().Format(time.RFC3339)
+	ref, err := s.putReferrer(ctx, repo.Digest(artifact.DigestStr()), subject, l, ann, mediaType, created)
+	if err != nil {
+		return nil, errors.Wrapf(err, "storing SBOM of %s", artifact.String())
+	}
+	return &api.StoreResponse{
+		Reference: ref.String(),
+		Digest:    ref.DigestStr(),
+	}, nil
+}
+
+// ListSBOMs returns the SBOMs stored as referrers of the given artifact in the
+// target repository, identified by the media type of their layer, without
+// fetching their content. An empty slice is
+// returned if there are none.
+func (s *AttestationStorer) ListSBOMs(ctx context.Context, artifact name.Digest) ([]SBOMInfo, error) {
+	if hs := s.hostStorer(artifact); hs != nil {
+		return hs.ListSBOMs(ctx, artifact)
+	}
+	infos := []SBOMInfo{}
+	if s.localLayout != "" {
+		return infos, nil
+	}
+	d := s.targetRepository(artifact).Digest(artifact.DigestStr())
+	idx, err := ociremote.Referrers(d, "", ociremote.WithRemoteOptions(s.remoteOptions(ctx)...))
+	var terr *transport.Error
+	if errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound {
+		return infos, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "listing referrers")
+	}
+
+	for _, desc := range idx.Manifests {
+		// Registries predating OCI 1.1 report the config media type as the
+		// artifact type, so those referrers are inspected too.
+		if at := desc.ArtifactType; at != "" && at != emptyConfigMediaType && !isSBOMMediaType(at) {
+			continue
+		}
+		img, err := remote.Image(d.Context().Digest(desc.Digest.String()), s.remoteOptions(ctx)...)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fetching referrer %s", desc.Digest)
+		}
+		m, err := img.Manifest()
+		if err != nil {
+			return nil, errors.Wrapf(err, "fetching referrer %s", desc.Digest)
+		}
+		created, err := referrerCreated(desc, img)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fetching referrer %s", desc.Digest)
+		}
+		for _, ld := range m.Layers {
+			if !isSBOMMediaType(string(ld.MediaType)) {
+				continue
+			}
+			infos = append(infos, SBOMInfo{
+				MediaType:      string(ld.MediaType),
+				Digest:         ld.Digest.String(),
+				ManifestDigest: desc.Digest.String(),
+				Created:        created,
+				Annotations:    ld.Annotations,
+			})
+		}
+	}
+	return infos, nil
+}
@@ -0,0 +1,238 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	intoto "github.com/in-toto/attestation/go/v1"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestAttestationStorer_SBOMAnnotations(t *testing.T) {
+	tests := []struct {
+		name              string
+		predicateType     string
+		predicate         map[string]any
+		wantPredicateType string
+		wantMediaType     string
+	}{
+		{
+			name:              "spdx",
+			predicateType:     "https://spdx.dev/Document/v2.3",
+			predicate:         map[string]any{"spdxVersion": "SPDX-2.3"},
+			wantPredicateType: "https://spdx.dev/Document",
+			wantMediaType:     "application/spdx+json",
+		},
+		{
+			name:              "cyclonedx",
+			predicateType:     "https://cyclonedx.org/bom",
+			predicate:         map[string]any{"bomFormat": "CycloneDX", "specVersion": "1.5"},
+			wantPredicateType: "https://cyclonedx.org/bom",
+			wantMediaType:     "application/vnd.cyclonedx+json",
+		},
+		{
+			name:              "cyclonedx in a generic predicate",
+			predicateType:     "https://example.com/sbom",
+			predicate:         map[string]any{"bomFormat": "CycloneDX", "specVersion": "1.5"},
+			wantPredicateType: "https://cyclonedx.org/bom",
+			wantMediaType:     "application/vnd.cyclonedx+json",
+		},
+		{
+			name:              "not an sbom",
+			predicateType:     "https://slsa.dev/provenance/v1",
+			predicate:         map[string]any{"buildDefinition": map[string]any{}},
+			wantPredicateType: "https://slsa.dev/provenance/v1",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := logtesting.TestContextWithLogger(t)
+			ref := pushRandomImage(t, newTestRegistry(t, nil))
+			predicate, err := structpb.NewStruct(tc.predicate)
+			if err != nil {
+				t.Fatalf("failed to build predicate: %v", err)
+			}
+			statement := &intoto.Statement{
+				Type:          intoto.StatementTypeUri,
+				Subject:       []*intoto.ResourceDescriptor{subjectFromDigest(ref)},
+				PredicateType: tc.predicateType,
+				Predicate:     predicate,
+			}
+			payload, err := protojson.Marshal(statement)
+			if err != nil {
+				t.Fatalf("failed to marshal statement: %v", err)
+			}
+
+			storer, err := NewAttestationStorer()
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+			// The statement is read from the envelope when the request
+			// carries none.
+			for _, p := range []*intoto.Statement{statement, nil} {
+				if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+					Artifact: ref,
+					Payload:  p,
+					Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+				}); err != nil {
+					t.Fatalf("error during Store(): %v", err)
+				}
+			}
+
+			se, err := ociremote.SignedEntity(ref)
+			if err != nil {
+				t.Fatalf("failed to get signed entity: %v", err)
+			}
+			atts, err := se.Attestations()
+			if err != nil {
+				t.Fatalf("failed to get attestations: %v", err)
+			}
+			layers, err := atts.Get()
+			if err != nil || len(layers) != 2 {
+				t.Fatalf("failed to get the attestations: %d layers, %v", len(layers), err)
+			}
+			for i, l := range layers {
+				ann, err := l.Annotations()
+				if err != nil {
+					t.Fatalf("failed to read annotations: %v", err)
+				}
+				if got := ann[predicateTypeAnnotation]; got != tc.wantPredicateType {
+					t.Errorf("layer %d annotation %s = %q, want %q", i, predicateTypeAnnotation, got, tc.wantPredicateType)
+				}
+				if got := ann[sbomMediaTypeAnnotation]; got != tc.wantMediaType {
+					t.Errorf("layer %d annotation %s = %q, want %q", i, sbomMediaTypeAnnotation, got, tc.wantMediaType)
+				}
+			}
+		})
+	}
+}
+
+func TestAttestationStorer_StoreSBOM(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	ref := newReferrersTestRegistry(t)
+	storer, err := NewAttestationStorer()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	storeTestAttestations(t, storer, ref, "https://slsa.dev/provenance/v1")
+
+	sboms := map[string][]byte{
+		"application/spdx+json":          []byte(`{"spdxVersion":"SPDX-2.3"}`),
+		"application/vnd.cyclonedx+json": []byte(`{"bomFormat":"CycloneDX"}`),
+	}
+	for mt, sbom := range sboms {
+		resp, err := storer.StoreSBOM(ctx, ref, sbom, mt)
+		if err != nil {
+			t.Fatalf("error during StoreSBOM(%s): %v", mt, err)
+		}
+		d, err := name.NewDigest(resp.Reference)
+		if err != nil {
+			t.Fatalf("invalid reference %q: %v", resp.Reference, err)
+		}
+		img, err := remote.Image(d)
+		if err != nil {
+			t.Fatalf("failed to fetch referrer: %v", err)
+		}
+		layers, err := img.Layers()
+		if err != nil || len(layers) != 1 {
+			t.Fatalf("got %d layers (%v), want 1", len(layers), err)
+		}
+		rc, err := layers[0].Uncompressed()
+		if err != nil {
+			t.Fatalf("failed to read SBOM: %v", err)
+		}
+		got, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("failed to read SBOM: %v", err)
+		}
+		if string(got) != string(sbom) {
+			t.Errorf("got SBOM %s, want %s", got, sbom)
+		}
+		m, err := img.Manifest()
+		if err != nil {
+			t.Fatalf("failed to fetch referrer: %v", err)
+		}
+		if m.Subject == nil || m.Subject.Digest.String() != ref.DigestStr() {
+			t.Errorf("got subject %v, want %s", m.Subject, ref.DigestStr())
+		}
+	}
+
+	infos, err := storer.ListSBOMs(ctx, ref)
+	if err != nil {
+		t.Fatalf("error during ListSBOMs(): %v", err)
+	}
+	got := map[string]string{}
+	for _, info := range infos {
+		got[info.MediaType] = info.Annotations[sbomMediaTypeAnnotation]
+		if info.Created.IsZero() {
+			t.Errorf("no creation time recorded for the %s SBOM", info.MediaType)
+		}
+	}
+	want := map[string]string{
+		"application/spdx+json":          "application/spdx+json",
+		"application/vnd.cyclonedx+json": "application/vnd.cyclonedx+json",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected SBOMs (-want +got):\n%s", diff)
+	}
+
+	// SBOMs are listed apart from the attestations.
+	if diff := cmp.Diff([]string{"https://slsa.dev/provenance/v1"}, listedPredicateTypes(t, storer, ref)); diff != "" {
+		t.Errorf("unexpected attestations (-want +got):\n%s", diff)
+	}
+	statements, err := storer.Retrieve(ctx, ref)
+	if err != nil {
+		t.Fatalf("error during Retrieve(): %v", err)
+	}
+	if len(statements) != 1 {
+		t.Errorf("got %d statements, want 1", len(statements))
+	}
+}
+
+func TestAttestationStorer_StoreSBOM_Invalid(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	ref := newReferrersTestRegistry(t)
+	storer, err := NewAttestationStorer()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	for desc, tc := range map[string]struct {
+		sbom      []byte
+		mediaType string
+	}{
+		"unsupported media type": {sbom: []byte("{}"), mediaType: "application/json"},
+		"empty":                  {mediaType: "application/spdx+json"},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			if _, err := storer.StoreSBOM(ctx, ref, tc.sbom, tc.mediaType); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+	if infos, err := storer.ListSBOMs(ctx, ref); err != nil || len(infos) != 0 {
+		t.Errorf("ListSBOMs() = %v, %v, want no SBOMs", infos, err)
+	}
+}
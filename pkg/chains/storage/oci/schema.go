@@ -0,0 +1,35 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+)
+
+// predicateSchemaAnnotation holds the URL of the JSON schema the predicate of
+// an attestation conforms to, configured with WithPredicateSchemaURL.
+const predicateSchemaAnnotation = "dev.tekton.chains/predicate-schema"
+
+// predicateTypeOf returns the predicate type of the attestation to store,
+// read from the signed envelope if the request carries no statement.
+func predicateTypeOf(req *api.StoreRequest[name.Digest, *intoto.Statement]) string {
+	if pt := req.Payload.GetPredicateType(); pt != "" {
+		return pt
+	}
+	id, _ := identifyEnvelope(req.Bundle.Signature)
+	return id.predicateType
+}
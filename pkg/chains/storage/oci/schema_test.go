@@ -0,0 +1,96 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestWithPredicateSchemaURL(t *testing.T) {
+	const (
+		provenance   = "https://slsa.dev/provenance/v1"
+		vsa          = "https://slsa.dev/verification_summary/v1"
+		unknown      = "https://example.com/unknown"
+		provenanceJS = "https://example.com/schemas/provenance.json"
+		vsaJS        = "https://example.com/schemas/vsa.json"
+	)
+	ctx := logtesting.TestContextWithLogger(t)
+	ref := pushRandomImage(t, newTestRegistry(t, nil))
+
+	storer, err := NewAttestationStorer(WithTargetRepository(ref.Repository), WithPredicateSchemaURL(map[string]string{
+		provenance: provenanceJS,
+		vsa:        vsaJS,
+	}))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+
+	want := map[string]string{provenance: provenanceJS, vsa: vsaJS, unknown: ""}
+	for predicateType := range want {
+		statement, payload := newTestStatement(t, ref, predicateType)
+		if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+			Artifact: ref,
+			Payload:  statement,
+			Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+		}); err != nil {
+			t.Fatalf("error during Store(): %v", err)
+		}
+	}
+
+	se, err := ociremote.SignedEntity(ref)
+	if err != nil {
+		t.Fatalf("failed to get signed entity: %v", err)
+	}
+	atts, err := se.Attestations()
+	if err != nil {
+		t.Fatalf("failed to get attestations: %v", err)
+	}
+	layers, err := atts.Get()
+	if err != nil {
+		t.Fatalf("failed to get attestation layers: %v", err)
+	}
+	if len(layers) != len(want) {
+		t.Fatalf("got %d attestations, want %d", len(layers), len(want))
+	}
+	for _, layer := range layers {
+		payload, err := layer.Payload()
+		if err != nil {
+			t.Fatalf("failed to read attestation: %v", err)
+		}
+		id, _ := identifyEnvelope(payload)
+		ann, err := layer.Annotations()
+		if err != nil {
+			t.Fatalf("failed to read attestation annotations: %v", err)
+		}
+		if got := ann[predicateSchemaAnnotation]; got != want[id.predicateType] {
+			t.Errorf("schema of %s = %q, want %q", id.predicateType, got, want[id.predicateType])
+		}
+	}
+}
+
+func TestWithPredicateSchemaURL_Invalid(t *testing.T) {
+	for _, schema := range []string{"schemas/provenance.json", "://bad"} {
+		if _, err := NewAttestationStorer(WithPredicateSchemaURL(map[string]string{"https://slsa.dev/provenance/v1": schema})); err == nil {
+			t.Errorf("expected an error for schema URL %q", schema)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"bytes"
+	"encoding/base64"
+
+	"github.com/pkg/errors"
+)
+
+// ErrMalformedSignature is matched by the errors of stores rejected by
+// WithSignatureValidation.
+var ErrMalformedSignature = errors.New("malformed signature")
+
+// validateSignature checks that sig holds the raw signature bytes, which the
+// storer base64 encodes itself. Signatures that are empty or already base64
+// encoded text, possibly with surrounding whitespace, are rejected as they
+// would be stored encoded twice and fail verification.
+func validateSignature(sig []byte) error {
+	if len(sig) == 0 {
+		return errors.Wrap(ErrMalformedSignature, "signature is empty")
+	}
+	text := bytes.TrimSpace(sig)
+	if len(text) == 0 {
+		return errors.Wrap(ErrMalformedSignature, "signature only holds whitespace")
+	}
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+		if _, err := enc.DecodeString(string(text)); err == nil {
+			return errors.Wrap(ErrMalformedSignature, "signature is base64 encoded text rather than raw bytes")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestWithSignatureValidation(t *testing.T) {
+	raw := []byte{0x30, 0x45, 0x02, 0x20, 0xff, 0x9a, 0x00, 0x7f, 0x81}
+	encoded := base64.StdEncoding.EncodeToString(raw)
+	for _, tc := range []struct {
+		name      string
+		signature []byte
+		wantErr   bool
+	}{
+		{name: "raw bytes", signature: raw},
+		{name: "empty", signature: nil, wantErr: true},
+		{name: "whitespace", signature: []byte(" \n"), wantErr: true},
+		{name: "base64", signature: []byte(encoded), wantErr: true},
+		{name: "base64 with newline", signature: []byte(encoded + "\n"), wantErr: true},
+		{name: "unpadded url base64", signature: []byte(base64.RawURLEncoding.EncodeToString(raw)), wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := logtesting.TestContextWithLogger(t)
+			ref := pushRandomImage(t, newTestRegistry(t, nil))
+			storer, err := NewSimpleStorerFromConfig(WithSignatureValidation())
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+			_, err = storer.Store(ctx, &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+				Artifact: ref,
+				Payload:  simple.NewSimpleStruct(ref),
+				Bundle:   &signing.Bundle{Content: []byte("{}"), Signature: tc.signature},
+			})
+			if !tc.wantErr {
+				if err != nil {
+					t.Fatalf("error during Store(): %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrMalformedSignature) {
+				t.Fatalf("Store() = %v, want %v", err, ErrMalformedSignature)
+			}
+			se, err := ociremote.SignedEntity(ref)
+			if err != nil {
+				t.Fatalf("failed to get signed entity: %v", err)
+			}
+			sigs, err := se.Signatures()
+			if err != nil {
+				t.Fatalf("failed to get signatures: %v", err)
+			}
+			if layers, err := sigs.Get(); err != nil || len(layers) != 0 {
+				t.Errorf("found %d signatures (%v), want none to be stored", len(layers), err)
+			}
+		})
+	}
+}
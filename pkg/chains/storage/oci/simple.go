@@ -16,11 +16,14 @@ package oci
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/json"
+	"maps"
 
 	"github.com/google/go-containerregistry/pkg/name"
-	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/pkg/errors"
+	"github.com/sigstore/cosign/v2/pkg/oci"
 	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
 	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
 	"github.com/sigstore/cosign/v2/pkg/oci/static"
@@ -31,11 +34,13 @@ import (
 
 // SimpleStorer stores SimpleSigning payloads in OCI registries.
 type SimpleStorer struct {
-	// repo configures the repo where data should be stored.
-	// If empty, the repo is inferred from the Artifact.
-	repo *name.Repository
-	// remoteOpts are additional remote options (i.e. auth) to use for client operations.
-	remoteOpts []remote.Option
+	baseStorer
+	// hostStorers store the subjects on the registry hosts with a host policy.
+	hostStorers map[string]*SimpleStorer
+	// mirrorStorers store in the mirror repositories, in order.
+	mirrorStorers []*SimpleStorer
+	// validateSignature, if set, rejects signatures that are not raw bytes.
+	validateSignature bool
 }
 
 var (
@@ -44,29 +49,144 @@ var (
 
 func NewSimpleStorerFromConfig(opts ...SimpleStorerOption) (*SimpleStorer, error) {
 	s := &SimpleStorer{}
-	for _, o := range opts {
+	for i, o := range opts {
 		if err := o.applySimpleStorer(s); err != nil {
-			return nil, err
+			return nil, errors.Wrapf(err, "applying option %d (%T)", i, o)
+		}
+	}
+	if err := s.checkLocalLayout(); err != nil {
+		return nil, err
+	}
+	for host, p := range s.hostPolicies {
+		hostOpts := withoutHostPolicies(opts)
+		if s.storeLimiter != nil {
+			// Share the limit of the storer, unless the policy sets its own.
+			hostOpts = append(hostOpts, WithStoreLimiter(s.storeLimiter))
+		}
+		for _, o := range p.Options {
+			hostOpts = append(hostOpts, o)
+		}
+		hs, err := NewSimpleStorerFromConfig(hostOpts...)
+		if err != nil {
+			return nil, errors.Wrapf(err, "applying host policy for %s", host)
+		}
+		if s.hostStorers == nil {
+			s.hostStorers = map[string]*SimpleStorer{}
+		}
+		s.hostStorers[host] = hs
+	}
+	for _, m := range s.mirrors {
+		mirrorOpts := mirrorOptions(opts, m)
+		if s.storeLimiter != nil {
+			mirrorOpts = append(mirrorOpts, WithStoreLimiter(s.storeLimiter))
+		}
+		ms, err := NewSimpleStorerFromConfig(mirrorOpts...)
+		if err != nil {
+			return nil, errors.Wrapf(err, "applying mirror %s", m.Repository.String())
 		}
+		s.mirrorStorers = append(s.mirrorStorers, ms)
 	}
 	return s, nil
 }
 
 func (s *SimpleStorer) Store(ctx context.Context, req *api.StoreRequest[name.Digest, simple.SimpleContainerImage]) (*api.StoreResponse, error) {
+	if hs := s.hostStorer(req.Artifact); hs != nil {
+		return hs.Store(ctx, req)
+	}
+	if err := s.checkRequest(req); err != nil {
+		return nil, err
+	}
+	return s.runStore(ctx, req, func(ctx context.Context) (*api.StoreResponse, error) {
+		return s.store(ctx, req)
+	})
+}
+
+// checkRequest validates req before anything is written.
+func (s *SimpleStorer) checkRequest(req *api.StoreRequest[name.Digest, simple.SimpleContainerImage]) error {
+	if req.Bundle == nil {
+		return ErrMissingBundle
+	}
+	if err := checkArtifact(req.Artifact); err != nil {
+		return err
+	}
+	return s.checkPayloadSize(req.Artifact, len(req.Bundle.Content))
+}
+
+// runStore runs store, which writes the signature of req, in a span, through
+// the in-process dedup, the store timeout and the mirrors, recording its
+// outcome and queueing it for a retry if it failed.
+func (s *SimpleStorer) runStore(ctx context.Context, req *api.StoreRequest[name.Digest, simple.SimpleContainerImage], store func(context.Context) (*api.StoreResponse, error)) (*api.StoreResponse, error) {
+	ctx, span := startSpan(ctx, "oci.SimpleStorer.Store", append(artifactAttributes(req.Artifact, s.targetRepository(req.Artifact)), formatAttribute.String(signatureFormat))...)
+	start := s.now()
+	resp, err := s.dedupStore(ctx, func() dedupKey {
+		return newDedupKey(req.Artifact, req.Bundle.Content, req.Bundle.Signature)
+	}, func() (*api.StoreResponse, error) {
+		return s.withStoreTimeout(ctx, func(ctx context.Context) (*api.StoreResponse, error) {
+			return s.storeWithMirrors(ctx, req, store)
+		})
+	})
+	s.metrics.observeStore(s.since(start), err)
+	endSpan(span, err)
+	if s.retryQueue != nil && isRetryable(err) {
+		s.enqueueRetry(ctx, req)
+	}
+	return resp, err
+}
+
+func (s *SimpleStorer) store(ctx context.Context, req *api.StoreRequest[name.Digest, simple.SimpleContainerImage], signOpts ...mutate.SignOption) (*api.StoreResponse, error) {
+	repo := s.targetRepository(req.Artifact)
+	unlock, err := s.lockEntity(ctx, req.Artifact, repo)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	se, err := s.lookupEntity(ctx, req.Artifact, repo)
+	if err != nil {
+		return nil, err
+	}
+	return s.storeTo(ctx, se, req, signOpts...)
+}
+
+// storeTo attaches the signature to se, the signed entity of the artifact
+// fetched from the target repository, and writes it.
+func (s *SimpleStorer) storeTo(ctx context.Context, se oci.SignedEntity, req *api.StoreRequest[name.Digest, simple.SimpleContainerImage], signOpts ...mutate.SignOption) (*api.StoreResponse, error) {
 	logger := logging.FromContext(ctx).With("image", req.Artifact.String())
 	logger.Info("Uploading signature")
 
-	se, err := ociremote.SignedEntity(req.Artifact, ociremote.WithRemoteOptions(s.remoteOpts...))
-	var entityNotFoundError *ociremote.EntityNotFoundError
-	if errors.As(err, &entityNotFoundError) {
-		se = ociremote.SignedUnknown(req.Artifact)
-	} else if err != nil {
-		return nil, errors.Wrap(err, "getting signed image")
-	}
+	repo := s.targetRepository(req.Artifact)
 
+	if s.validateSignature {
+		if err := validateSignature(req.Bundle.Signature); err != nil {
+			return nil, errors.Wrapf(err, "storing signature of %s", req.Artifact.String())
+		}
+	}
 	sigOpts := []static.Option{}
 	if req.Bundle.Cert != nil {
-		sigOpts = append(sigOpts, static.WithCertChain(req.Bundle.Cert, req.Bundle.Chain))
+		cert, chain, err := normalizeCertChain(req.Bundle.Cert, req.Bundle.Chain)
+		if err != nil {
+			return nil, err
+		}
+		sigOpts = append(sigOpts, static.WithCertChain(cert, chain))
+	}
+	if s.rekorBundle != nil {
+		sigOpts = append(sigOpts, static.WithBundle(s.rekorBundle))
+	}
+	if s.rfc3161Timestamp != nil {
+		sigOpts = append(sigOpts, static.WithRFC3161Timestamp(s.rfc3161Timestamp))
+	}
+	annotations := maps.Clone(s.annotations)
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if s.recordPayloadDigests {
+		unsigned, err := json.Marshal(req.Payload)
+		if err != nil {
+			return nil, errors.Wrap(err, "marshaling the payload")
+		}
+		addPayloadDigestAnnotations(annotations, unsigned, sha256.Sum256(req.Bundle.Content))
+	}
+	if len(annotations) > 0 {
+		sigOpts = append(sigOpts, static.WithAnnotations(annotations))
 	}
 	// Create the new signature for this entity.
 	b64sig := base64.StdEncoding.EncodeToString(req.Bundle.Signature)
@@ -75,19 +195,137 @@ func (s *SimpleStorer) Store(ctx context.Context, req *api.StoreRequest[name.Dig
 		return nil, err
 	}
 	// Attach the signature to the entity.
-	newSE, err := mutate.AttachSignatureToEntity(se, sig)
+	newSE, err := mutate.AttachSignatureToEntity(se, sig, signOpts...)
 	if err != nil {
 		return nil, err
 	}
+	newSE = &memoizedEntity{SignedEntity: s.withCreationTime(newSE)}
+	if s.registryLimits != nil {
+		sigs, err := newSE.Signatures()
+		if err != nil {
+			return nil, err
+		}
+		if err := s.checkRegistryLimits(ctx, repo, sigs); err != nil {
+			return nil, errors.Wrapf(err, "validating signatures of %s", req.Artifact.String())
+		}
+	}
 
-	repo := req.Artifact.Repository
-	if s.repo != nil {
-		repo = *s.repo
+	tag, err := ociremote.SignatureTag(req.Artifact, s.tagOptions(repo)...)
+	if err != nil {
+		return nil, err
+	}
+	sigs, err := newSE.Signatures()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := newStoreResponse(tag, sigs)
+	if err != nil {
+		return nil, err
+	}
+	if s.dryRun {
+		logger.Infof("Dry run: would upload signature to %s (%s)", resp.Reference, resp.Digest)
+		return resp, nil
 	}
+
 	// Publish the signatures associated with this entity
-	if err := ociremote.WriteSignatures(repo, newSE, ociremote.WithRemoteOptions(s.remoteOpts...)); err != nil {
+	if err := s.retryWrite(ctx, "signatures of "+req.Artifact.String(), func() error {
+		if s.localLayout != "" {
+			return writeLayout(s.localLayout, tag, sigs)
+		}
+		return ociremote.WriteSignatures(repo, newSE, ociremote.WithRemoteOptions(s.remoteOptions(ctx)...), ociremote.WithPrefix(s.tagPrefix))
+	}); err != nil {
 		return nil, err
 	}
-	logger.Info("Successfully uploaded signature")
-	return &api.StoreResponse{}, nil
+	if s.readBack != nil {
+		if err := s.verifyWritten(ctx, tag, []oci.Signature{sig}); err != nil {
+			return nil, errors.Wrapf(err, "verifying signatures of %s", req.Artifact.String())
+		}
+	}
+	if s.verifyAnnotations {
+		if err := verifyAnnotations(tag, sig, s.remoteOptions(ctx)...); err != nil {
+			return nil, err
+		}
+	}
+	if s.sampleEvent() {
+		logger.Info("Successfully uploaded signature")
+	}
+	return resp, nil
+}
+
+// enqueueRetry persists a failed store to the durable retry queue.
+func (s *SimpleStorer) enqueueRetry(ctx context.Context, req *api.StoreRequest[name.Digest, simple.SimpleContainerImage]) {
+	logger := logging.FromContext(ctx)
+	b, err := json.Marshal(req.Payload)
+	if err != nil {
+		logger.Errorf("Failed to encode signature for %s for retry: %v", req.Artifact.String(), err)
+		return
+	}
+	e := &retryEntry{
+		Kind:     retryKindSignature,
+		Artifact: req.Artifact.String(),
+		Payload:  b,
+		Bundle:   req.Bundle,
+	}
+	if err := s.retryQueue.persist(e); err != nil {
+		logger.Errorf("Failed to persist signature for %s for retry: %v", req.Artifact.String(), err)
+	}
+}
+
+// ReplayRetryQueue re-attempts the signature stores persisted to the durable
+// retry queue, typically on controller startup. Entries are removed once they
+// have been stored successfully and kept for a later replay otherwise.
+func (s *SimpleStorer) ReplayRetryQueue(ctx context.Context) error {
+	if s.retryQueue == nil {
+		return nil
+	}
+	paths, entries, err := s.retryQueue.entries(retryKindSignature)
+	if err != nil {
+		return err
+	}
+	var failed int
+	for i, e := range entries {
+		if err := s.replay(ctx, e); err != nil {
+			logging.FromContext(ctx).Errorf("Failed to replay signature for %s: %v", e.Artifact, err)
+			failed++
+			continue
+		}
+		if err := s.retryQueue.remove(paths[i]); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return errors.Errorf("replaying %d of %d queued signatures failed", failed, len(entries))
+	}
+	return nil
+}
+
+func (s *SimpleStorer) replay(ctx context.Context, e *retryEntry) error {
+	artifact, err := e.artifact()
+	if err != nil {
+		return err
+	}
+	req := &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+		Artifact: artifact,
+		Bundle:   e.Bundle,
+	}
+	if err := json.Unmarshal(e.Payload, &req.Payload); err != nil {
+		return errors.Wrap(err, "decoding queued signature")
+	}
+	if req.Bundle == nil {
+		return ErrMissingBundle
+	}
+	storer := s
+	if hs := s.hostStorer(artifact); hs != nil {
+		storer = hs
+	}
+	_, err = storer.withStoreTimeout(ctx, func(ctx context.Context) (*api.StoreResponse, error) {
+		return storer.store(ctx, req, mutate.WithDupeDetector(replayDupeDetector{}))
+	})
+	return err
+}
+
+// hostStorer returns the storer derived from the host policy for the
+// artifact's registry, or nil if there is none.
+func (s *SimpleStorer) hostStorer(artifact name.Digest) *SimpleStorer {
+	return s.hostStorers[artifact.RegistryStr()]
 }
@@ -15,6 +15,13 @@
 package oci
 
 import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net/http/httptest"
 	"strings"
@@ -25,6 +32,7 @@ import (
 	"github.com/google/go-containerregistry/pkg/v1/random"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/google/go-containerregistry/pkg/v1/types"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
 	"github.com/tektoncd/chains/pkg/chains/formats/simple"
 	"github.com/tektoncd/chains/pkg/chains/signing"
 	"github.com/tektoncd/chains/pkg/chains/storage/api"
@@ -108,3 +116,69 @@ func TestSimpleStorer_Store(t *testing.T) {
 		})
 	}
 }
+
+func TestSimpleStorer_TrailingData(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	ref := pushRandomImage(t, newTestRegistry(t, nil))
+	storer, err := NewSimpleStorerFromConfig()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+
+	// Some signers sign the payload with a trailing newline, which is lost if
+	// the payload is marshaled again.
+	format := simple.NewSimpleStruct(ref)
+	content, err := json.Marshal(format)
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
+	}
+	content = append(content, '\n')
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	digest := sha256.Sum256(content)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatalf("failed to sign payload: %v", err)
+	}
+	if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+		Artifact: ref,
+		Payload:  format,
+		Bundle:   &signing.Bundle{Content: content, Signature: signature},
+	}); err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+
+	se, err := ociremote.SignedEntity(ref)
+	if err != nil {
+		t.Fatalf("failed to get signed entity: %v", err)
+	}
+	sigs, err := se.Signatures()
+	if err != nil {
+		t.Fatalf("failed to get signatures: %v", err)
+	}
+	layers, err := sigs.Get()
+	if err != nil || len(layers) != 1 {
+		t.Fatalf("failed to get the signature: %d signatures, %v", len(layers), err)
+	}
+	stored, err := layers[0].Payload()
+	if err != nil {
+		t.Fatalf("failed to read payload: %v", err)
+	}
+	if !bytes.Equal(stored, content) {
+		t.Errorf("stored payload = %q, want %q", stored, content)
+	}
+	b64sig, err := layers[0].Base64Signature()
+	if err != nil {
+		t.Fatalf("failed to read signature: %v", err)
+	}
+	storedSig, err := base64.StdEncoding.DecodeString(b64sig)
+	if err != nil {
+		t.Fatalf("failed to decode signature: %v", err)
+	}
+	storedDigest := sha256.Sum256(stored)
+	if !ecdsa.VerifyASN1(&key.PublicKey, storedDigest[:], storedSig) {
+		t.Error("stored signature does not verify the stored payload")
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"bytes"
+	"maps"
+	"sort"
+	"strconv"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/pkg/errors"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+)
+
+const (
+	// splitGroupAnnotation holds the digest of the payload a chunk belongs to.
+	splitGroupAnnotation = "dev.tekton.chains/split-group"
+	// splitIndexAnnotation holds the position of a chunk within its payload.
+	splitIndexAnnotation = "dev.tekton.chains/split-index"
+	// splitCountAnnotation holds the number of chunks the payload was split into.
+	splitCountAnnotation = "dev.tekton.chains/split-count"
+
+	// splitChunkMediaType is the media type of a layer holding part of a payload.
+	// Chunks are not valid DSSE envelopes on their own, so they are not
+	// advertised as such.
+	splitChunkMediaType types.MediaType = "application/vnd.dev.tekton.chains.payload-chunk.v1"
+)
+
+// newAttestationLayers returns the layers holding the payload, with the given
+// annotations. If splitSize is positive and the payload is larger, it is split
+// into chunks of at most splitSize bytes that are annotated so that they can
+// be reassembled.
+func newAttestationLayers(payload []byte, splitSize int64, annotations map[string]string, opts ...static.Option) ([]oci.Signature, error) {
+	if splitSize <= 0 || int64(len(payload)) <= splitSize {
+		if len(annotations) > 0 {
+			opts = append(append([]static.Option{}, opts...), static.WithAnnotations(annotations))
+		}
+		att, err := static.NewAttestation(payload, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return []oci.Signature{att}, nil
+	}
+
+	group, _, err := v1.SHA256(bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	count := (int64(len(payload)) + splitSize - 1) / splitSize
+	chunks := make([]oci.Signature, 0, count)
+	for i := int64(0); i < count; i++ {
+		end := min((i+1)*splitSize, int64(len(payload)))
+		chunkAnnotations := maps.Clone(annotations)
+		if chunkAnnotations == nil {
+			chunkAnnotations = map[string]string{}
+		}
+		chunkAnnotations[splitGroupAnnotation] = group.String()
+		chunkAnnotations[splitIndexAnnotation] = strconv.FormatInt(i, 10)
+		chunkAnnotations[splitCountAnnotation] = strconv.FormatInt(count, 10)
+		chunkOpts := append(append([]static.Option{}, opts...),
+			static.WithLayerMediaType(splitChunkMediaType),
+			static.WithAnnotations(chunkAnnotations),
+		)
+		chunk, err := static.NewAttestation(payload[i*splitSize:end], chunkOpts...)
+		if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks, nil
+}
+
+type splitChunk struct {
+	index   int
+	payload []byte
+}
+
+// reassemblePayloads returns the payloads of the given layers, joining the
+// chunks of split payloads back together. Payloads are returned in the order
+// their first layer appears.
+func reassemblePayloads(sigs []oci.Signature) ([][]byte, error) {
+	var (
+		payloads [][]byte
+		// groups maps split payloads to their position in payloads.
+		groups = map[string]int{}
+		chunks = map[string][]splitChunk{}
+		counts = map[string]int{}
+	)
+	for _, sig := range sigs {
+		payload, err := sig.Payload()
+		if err != nil {
+			return nil, errors.Wrap(err, "reading attestation")
+		}
+		ann, err := sig.Annotations()
+		if err != nil {
+			return nil, errors.Wrap(err, "reading attestation annotations")
+		}
+		group, ok := ann[splitGroupAnnotation]
+		if !ok {
+			payloads = append(payloads, payload)
+			continue
+		}
+		index, err := strconv.Atoi(ann[splitIndexAnnotation])
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing chunk index of %s", group)
+		}
+		count, err := strconv.Atoi(ann[splitCountAnnotation])
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing chunk count of %s", group)
+		}
+		if _, ok := groups[group]; !ok {
+			groups[group] = len(payloads)
+			payloads = append(payloads, nil)
+		}
+		chunks[group] = append(chunks[group], splitChunk{index: index, payload: payload})
+		counts[group] = count
+	}
+
+	for group, parts := range chunks {
+		payload, err := joinChunks(group, parts, counts[group])
+		if err != nil {
+			return nil, err
+		}
+		payloads[groups[group]] = payload
+	}
+	return payloads, nil
+}
+
+func joinChunks(group string, parts []splitChunk, count int) ([]byte, error) {
+	sort.Slice(parts, func(i, j int) bool { return parts[i].index < parts[j].index })
+	if len(parts) != count {
+		return nil, errors.Errorf("split payload %s has %d of %d chunks", group, len(parts), count)
+	}
+	var buf bytes.Buffer
+	for i, p := range parts {
+		if p.index != i {
+			return nil, errors.Errorf("split payload %s is missing chunk %d", group, i)
+		}
+		buf.Write(p.payload)
+	}
+	got, _, err := v1.SHA256(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+	if got.String() != group {
+		return nil, errors.Errorf("reassembled payload digest %s does not match %s", got, group)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/structpb"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestWithPayloadSplitSize(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	registryName := newTestRegistry(t, nil)
+	ref := pushRandomImage(t, registryName)
+
+	storer, err := NewAttestationStorer(WithTargetRepository(ref.Repository), WithPayloadSplitSize(256))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+
+	// A statement below the split size, stored as a single layer.
+	small, smallPayload := newTestStatement(t, ref, "https://example.com/small")
+	// A statement large enough to be split across several layers.
+	predicate, err := structpb.NewStruct(map[string]any{"padding": strings.Repeat("x", 2048)})
+	if err != nil {
+		t.Fatalf("failed to create predicate: %v", err)
+	}
+	large, _ := newTestStatement(t, ref, "https://example.com/large")
+	large.Predicate = predicate
+	largePayload, err := protojson.Marshal(large)
+	if err != nil {
+		t.Fatalf("failed to marshal statement: %v", err)
+	}
+
+	for _, tc := range []struct {
+		statement *intoto.Statement
+		payload   []byte
+	}{{small, smallPayload}, {large, largePayload}} {
+		if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+			Artifact: ref,
+			Payload:  tc.statement,
+			Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, tc.payload)},
+		}); err != nil {
+			t.Fatalf("error during Store(): %v", err)
+		}
+	}
+
+	if got := countAttestationLayers(t, ref.Repository, ref); got <= 2 {
+		t.Errorf("expected the large payload to be split across layers, got %d layers", got)
+	}
+
+	got, err := storer.Retrieve(ctx, ref)
+	if err != nil {
+		t.Fatalf("error during Retrieve(): %v", err)
+	}
+	want := []*intoto.Statement{small, large}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("unexpected statements (-want +got):\n%s", diff)
+	}
+}
+
+func TestReassemblePayloadsMissingChunk(t *testing.T) {
+	chunks, err := newAttestationLayers([]byte(strings.Repeat("payload", 10)), 16, nil)
+	if err != nil {
+		t.Fatalf("failed to split payload: %v", err)
+	}
+	if _, err := reassemblePayloads(chunks[1:]); err == nil {
+		t.Error("expected an error reassembling a payload with a missing chunk")
+	}
+}
+
+func TestWithPayloadSplitSizeInvalid(t *testing.T) {
+	if _, err := NewAttestationStorer(WithPayloadSplitSize(0)); err == nil {
+		t.Error("expected an error for a non-positive split size")
+	}
+}
@@ -0,0 +1,423 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/pkg/errors"
+	"github.com/sigstore/cosign/v2/pkg/cosign/bundle"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	"golang.org/x/sync/semaphore"
+	"k8s.io/utils/clock"
+	"knative.dev/pkg/logging"
+)
+
+// ErrMissingBundle is returned when a store request does not carry a signing bundle.
+var ErrMissingBundle = errors.New("store request has no signing bundle")
+
+// ErrInvalidArtifact is matched by errors of store requests whose artifact is
+// not a digest reference, e.g. a name.Digest that was not obtained from
+// name.NewDigest.
+var ErrInvalidArtifact = errors.New("artifact is not a digest reference")
+
+// checkArtifact rejects artifacts that do not name a digest. Signatures and
+// attestations are stored against the digest, so that they cannot drift to
+// another image like a tag can.
+func checkArtifact(artifact name.Digest) error {
+	if _, err := v1.NewHash(artifact.DigestStr()); err != nil {
+		return &classifiedError{sentinel: ErrInvalidArtifact, err: errors.Wrapf(err, "invalid artifact %q", artifact.String())}
+	}
+	return nil
+}
+
+// errStoreTimeout is the cause of contexts that expire after the timeout
+// configured with WithStoreTimeout.
+var errStoreTimeout = errors.New("store timeout")
+
+// TimeoutError is returned when a store does not complete within the timeout
+// configured with WithStoreTimeout. Unlike a cancellation or a deadline set by
+// the caller, it indicates that the registry did not respond in time.
+type TimeoutError struct {
+	// Timeout is the configured store timeout.
+	Timeout time.Duration
+	// Err is the error returned by the interrupted operation.
+	Err error
+}
+
+// Error implements error.
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("store timed out after %s: %v", e.Timeout, e.Err)
+}
+
+// Unwrap returns the error returned by the interrupted operation.
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// baseStorer holds the registry configuration shared by all OCI storers.
+type baseStorer struct {
+	// repo configures the repo where data should be stored.
+	// If empty, the repo is inferred from the Artifact.
+	repo *name.Repository
+	// remoteOpts are additional remote options (i.e. auth) to use for client operations.
+	remoteOpts []remote.Option
+	// auth, if set, is the auth option configured with WithKeychain or
+	// WithAuthenticator.
+	auth remote.Option
+	// rateLimiter, if set, throttles requests based on the rate limit headers
+	// advertised by the registry.
+	rateLimiter *rateLimiter
+	// eventSampleRate is the fraction of successful stores that emit events.
+	// If nil, all successful stores emit events.
+	eventSampleRate *float64
+	// sampleRand returns a pseudo-random number in [0.0, 1.0).
+	sampleRand func() float64
+	// retryQueue, if set, persists failed stores so they can be replayed later.
+	retryQueue *retryQueue
+	// entityCache, if set, memoizes the lookups of the signed entities stored to.
+	entityCache *entityCache
+	// entityLocks serializes the stores to the same signed entity.
+	entityLocks keyedMutex
+	// entityFetchRetry, if set, configures retries of the signed entity fetch.
+	entityFetchRetry *entityFetchRetry
+	// writeRetry, if set, configures retries of the registry writes.
+	writeRetry *writeRetry
+	// maxRetryAfter caps the wait requested through Retry-After headers.
+	// If zero, defaultMaxRetryAfter applies.
+	maxRetryAfter time.Duration
+	// maxRetryDelay caps the exponential delay between write attempts.
+	// If zero, defaultMaxRetryDelay applies.
+	maxRetryDelay time.Duration
+	// storeTimeout, if positive, bounds the duration of each store.
+	storeTimeout time.Duration
+	// registryLimits, if set, validates stores against the registry limits.
+	registryLimits *registryLimits
+	// storeLimiter, if set, bounds the number of stores writing to registries
+	// at once.
+	storeLimiter *semaphore.Weighted
+	// metrics, if set, records the store operations.
+	metrics *formatMetrics
+	// transport is the transport to use for client operations, configured
+	// with WithTransport. If nil, remote.DefaultTransport is used.
+	transport http.RoundTripper
+	// creationTime, if set, is recorded as the created timestamp of the
+	// signature and attestation manifests.
+	creationTime *time.Time
+	// reproducible, if set, records fixed timestamps in the config and
+	// history of the signature and attestation manifests.
+	reproducible bool
+	// annotations are added to the stored signatures and attestations,
+	// configured with WithAnnotations.
+	annotations map[string]string
+	// recordPayloadDigests, if set, records the digests of the unsigned and
+	// signed payloads when they differ.
+	recordPayloadDigests bool
+	// verifyAnnotations, if set, checks that written annotations were kept by the registry.
+	verifyAnnotations bool
+	// hostPolicies holds the policies for subjects on specific registry hosts.
+	hostPolicies map[string]HostPolicy
+	// insecureRegistries holds the registries configured with WithInsecure,
+	// which are accessed over plain HTTP.
+	insecureRegistries map[string]bool
+	// mirrors are the repositories written to besides the target repository.
+	mirrors []MirrorRepository
+	// mirrorMode controls when the mirrors are written to.
+	mirrorMode MirrorMode
+	// dryRun, if set, computes where objects would be stored without writing them.
+	dryRun bool
+	// localLayout, if set, is the path of the OCI layout the signatures and
+	// attestations are read from and written to instead of the registry.
+	localLayout string
+	// rekorBundle, if set, is the transparency log entry attached to the
+	// stored signatures and attestations.
+	rekorBundle *bundle.RekorBundle
+	// rfc3161Timestamp, if set, is the timestamp token attached to the stored
+	// signatures and attestations.
+	rfc3161Timestamp *bundle.RFC3161Timestamp
+	// maxPayloadBytes, if positive, is the maximum size of the payload of a
+	// store request.
+	maxPayloadBytes int64
+	// readBack, if set, configures the read-back of the written signatures and
+	// attestations.
+	readBack *readBack
+	// tagPrefix is prepended to the .sig and .att tags.
+	tagPrefix string
+	// dedup, if set, suppresses the stores of payloads recently stored by the
+	// storer.
+	dedup *dedupCache
+	// capabilityCache caches the capabilities probed with Capabilities.
+	capabilityCache capabilityCache
+	// userAgent, if set, is the user agent configured with WithUserAgent.
+	userAgent string
+	// clock, if set, is the clock configured with WithClock.
+	clock clock.PassiveClock
+	// debugTransport, if set, logs the registry requests and responses.
+	debugTransport bool
+}
+
+// now returns the current time of the clock configured with WithClock, or of
+// the real clock.
+func (b *baseStorer) now() time.Time {
+	if b.clock != nil {
+		return b.clock.Now()
+	}
+	return time.Now()
+}
+
+// since returns the time elapsed since t on the clock configured with
+// WithClock, or on the real clock.
+func (b *baseStorer) since(t time.Time) time.Duration {
+	if b.clock != nil {
+		return b.clock.Since(t)
+	}
+	return time.Since(t)
+}
+
+// entityOptions returns the cosign options of the signed entities looked up
+// with ctx, followed by extra.
+func (b *baseStorer) entityOptions(ctx context.Context, extra ...ociremote.Option) []ociremote.Option {
+	return append([]ociremote.Option{ociremote.WithRemoteOptions(b.remoteOptions(ctx)...)}, extra...)
+}
+
+// tagOptions returns the cosign options naming the .sig and .att tags of the
+// artifacts stored in repo.
+func (b *baseStorer) tagOptions(repo name.Repository) []ociremote.Option {
+	return []ociremote.Option{ociremote.WithTargetRepository(repo), ociremote.WithPrefix(b.tagPrefix)}
+}
+
+// checkLocalLayout rejects the options that need a registry when the storer
+// writes to an OCI layout.
+func (b *baseStorer) checkLocalLayout() error {
+	if b.localLayout != "" && b.verifyAnnotations {
+		return errors.New("annotation verification is not supported with a local OCI layout")
+	}
+	if b.localLayout != "" && b.readBack != nil {
+		return errors.New("verification after write is not supported with a local OCI layout")
+	}
+	return nil
+}
+
+// entityFetchRetry configures retries of the signed entity fetch.
+type entityFetchRetry struct {
+	// attempts is the total number of fetch attempts.
+	attempts int
+	// backoff is the delay before the first retry, doubled after each retry.
+	backoff time.Duration
+}
+
+// remoteOptions returns the remote options to use for client operations
+// bound to ctx.
+func (b *baseStorer) remoteOptions(ctx context.Context) []remote.Option {
+	opts := make([]remote.Option, 0, len(b.remoteOpts)+5)
+	// The default user agent gives way to one passed with WithRemoteOptions.
+	opts = append(opts, remote.WithUserAgent(defaultUserAgent()))
+	opts = append(opts, b.remoteOpts...)
+	if b.userAgent != "" {
+		opts = append(opts, remote.WithUserAgent(b.userAgent))
+	}
+	if b.auth != nil {
+		opts = append(opts, b.auth)
+	}
+	opts = append(opts, remote.WithContext(ctx))
+	if b.transport == nil && b.rateLimiter == nil && b.writeRetry == nil && !b.debugTransport {
+		return opts
+	}
+	return append(opts, remote.WithTransport(b.roundTripper()))
+}
+
+// roundTripper returns the transport for client operations, wrapped to
+// honor the configured retries and rate limiting, and to log the requests if
+// configured with WithDebugTransport.
+func (b *baseStorer) roundTripper() http.RoundTripper {
+	rt := b.transport
+	if rt == nil {
+		rt = remote.DefaultTransport
+	}
+	// The requests are logged as sent, once per attempt.
+	if b.debugTransport {
+		rt = &debugTransport{inner: rt}
+	}
+	if b.writeRetry != nil {
+		rt = b.writeRetry.retryAfter.wrap(rt)
+	}
+	if b.rateLimiter != nil {
+		rt = b.rateLimiter.wrap(rt)
+	}
+	return rt
+}
+
+// withStoreTimeout calls store with a context bounded by the timeout
+// configured with WithStoreTimeout, covering both the lookup of the signed
+// entity and the writes. Errors caused by the timeout are returned as a
+// *TimeoutError.
+func (b *baseStorer) withStoreTimeout(ctx context.Context, store func(context.Context) (*api.StoreResponse, error)) (*api.StoreResponse, error) {
+	if b.storeTimeout <= 0 {
+		return store(ctx)
+	}
+	ctx, cancel := context.WithTimeoutCause(ctx, b.storeTimeout, errStoreTimeout)
+	defer cancel()
+	resp, err := store(ctx)
+	if err != nil && errors.Is(context.Cause(ctx), errStoreTimeout) {
+		return nil, &TimeoutError{Timeout: b.storeTimeout, Err: err}
+	}
+	return resp, err
+}
+
+// sampleEvent reports whether a successful store should emit its events.
+// Failures are always reported and are not subject to sampling.
+func (b *baseStorer) sampleEvent() bool {
+	if b.eventSampleRate == nil {
+		return true
+	}
+	rnd := b.sampleRand
+	if rnd == nil {
+		rnd = rand.Float64
+	}
+	return rnd() < *b.eventSampleRate
+}
+
+// parseDigest parses a digest reference. The encoded digest is lowercased so
+// that the signature and attestation tags derived from it match the ones
+// cosign looks up, even for clients passing uppercase hex.
+func parseDigest(ref string) (name.Digest, error) {
+	if i := strings.LastIndex(ref, "@"); i >= 0 {
+		ref = ref[:i] + strings.ToLower(ref[i:])
+	}
+	return name.NewDigest(ref)
+}
+
+// targetRepository returns the repository where data for the artifact is stored.
+func (b *baseStorer) targetRepository(artifact name.Digest) name.Repository {
+	repo := artifact.Repository
+	if b.repo != nil {
+		repo = *b.repo
+	}
+	if !b.insecureRegistries[repo.RegistryStr()] {
+		return repo
+	}
+	insecure, err := name.NewRepository(repo.Name(), name.Insecure)
+	if err != nil {
+		return repo
+	}
+	return insecure
+}
+
+// artifactReference returns ref, resolved as insecure if its registry was
+// configured with WithInsecure.
+func (b *baseStorer) artifactReference(ref name.Digest) name.Digest {
+	if !b.insecureRegistries[ref.RegistryStr()] {
+		return ref
+	}
+	insecure, err := name.NewDigest(ref.String(), name.Insecure)
+	if err != nil {
+		return ref
+	}
+	return insecure
+}
+
+// signedEntity fetches the artifact along with the signatures and attestations
+// attached to it. Artifacts that do not exist, or whose manifest is neither an
+// image nor an index (e.g. an OCI artifact manifest holding another
+// attestation), are treated as unknown entities so that they can still be
+// signed and attested. Only the manifest of the artifact is fetched, never its
+// layers, so subjects referencing foreign or otherwise unreachable layers are
+// supported. Transient fetch errors are retried as configured by
+// WithEntityFetchRetry.
+func (b *baseStorer) signedEntity(ctx context.Context, ref name.Digest, extra ...ociremote.Option) (oci.SignedEntity, error) {
+	ref = b.artifactReference(ref)
+	opts := b.entityOptions(ctx, extra...)
+	attempts, backoff := 1, time.Duration(0)
+	if b.entityFetchRetry != nil {
+		attempts, backoff = b.entityFetchRetry.attempts, b.entityFetchRetry.backoff
+	}
+	for attempt := 1; ; attempt++ {
+		se, err := ociremote.SignedEntity(ref, opts...)
+		var entityNotFoundError *ociremote.EntityNotFoundError
+		if errors.As(err, &entityNotFoundError) || isUnknownMediaTypeError(err) {
+			return ociremote.SignedUnknown(ref, opts...), nil
+		} else if err == nil {
+			return se, nil
+		}
+		if attempt >= attempts || !isTransientError(err) {
+			return nil, errors.Wrap(classifyError(err), "getting signed image")
+		}
+		logging.FromContext(ctx).Warnf("Fetching %s failed on attempt %d of %d, retrying: %v", ref.String(), attempt, attempts, err)
+		b.metrics.observeRetry()
+		if err := sleepContext(ctx, backoff); err != nil {
+			return nil, errors.Wrap(err, "getting signed image")
+		}
+		backoff *= 2
+	}
+}
+
+// isTransientError reports whether a failed registry request may succeed if
+// retried. Client errors such as 400, 401 and 403 are not transient.
+func isTransientError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var terr *transport.Error
+	if errors.As(err, &terr) {
+		return terr.StatusCode == http.StatusRequestTimeout ||
+			terr.StatusCode == http.StatusTooManyRequests ||
+			terr.StatusCode >= http.StatusInternalServerError
+	}
+	// Errors that did not come from the registry, e.g. connection resets.
+	return true
+}
+
+// isUnknownMediaTypeError reports whether err is the error returned by
+// ociremote.SignedEntity for manifests that are neither images nor indexes.
+func isUnknownMediaTypeError(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "unknown mime type")
+}
+
+// withCreationTime applies the configured creation time to the signature and
+// attestation manifests of the entity.
+func (b *baseStorer) withCreationTime(se oci.SignedEntity) oci.SignedEntity {
+	switch {
+	case b.reproducible && b.creationTime != nil:
+		return &createdAtEntity{SignedEntity: se, created: *b.creationTime, history: true}
+	case b.reproducible:
+		return &createdAtEntity{SignedEntity: se, created: time.Unix(0, 0).UTC(), history: true}
+	case b.creationTime != nil:
+		return &createdAtEntity{SignedEntity: se, created: *b.creationTime}
+	}
+	return se
+}
+
+// newStoreResponse describes the signatures or attestations manifest written to tag.
+func newStoreResponse(tag name.Tag, sigs oci.Signatures) (*api.StoreResponse, error) {
+	d, err := sigs.Digest()
+	if err != nil {
+		return nil, err
+	}
+	return &api.StoreResponse{
+		Reference: tag.String(),
+		Digest:    d.String(),
+	}, nil
+}
@@ -0,0 +1,675 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/pkg/errors"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"knative.dev/pkg/logging"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// newTestRegistry starts an in-memory registry, optionally wrapped by the
+// given middleware, and returns its host.
+func newTestRegistry(t *testing.T, middleware func(http.Handler) http.Handler) string {
+	t.Helper()
+	var h http.Handler = registry.New()
+	if middleware != nil {
+		h = middleware(h)
+	}
+	s := httptest.NewServer(h)
+	t.Cleanup(s.Close)
+	return strings.TrimPrefix(s.URL, "http://")
+}
+
+// pushRandomImage writes a random image to the registry and returns its digest.
+func pushRandomImage(t *testing.T, registryName string) name.Digest {
+	t.Helper()
+	img, err := random.Image(1024, 2)
+	if err != nil {
+		t.Fatalf("failed to create random image: %v", err)
+	}
+	imgDigest, err := img.Digest()
+	if err != nil {
+		t.Fatalf("failed to get image digest: %v", err)
+	}
+	ref, err := name.NewDigest(fmt.Sprintf("%s/test/img@%s", registryName, imgDigest))
+	if err != nil {
+		t.Fatalf("failed to parse digest: %v", err)
+	}
+	if err := remote.Write(ref, img); err != nil {
+		t.Fatalf("failed to write image to mock registry: %v", err)
+	}
+	return ref
+}
+
+// newBufferedLoggerContext returns a context whose logger writes to the returned buffer.
+func newBufferedLoggerContext(t *testing.T) (context.Context, *bytes.Buffer) {
+	t.Helper()
+	var buf bytes.Buffer
+	core := zapcore.NewCore(zapcore.NewConsoleEncoder(zap.NewDevelopmentEncoderConfig()), zapcore.AddSync(&buf), zapcore.DebugLevel)
+	return logging.WithLogger(context.Background(), zap.New(core).Sugar()), &buf
+}
+
+func TestEventSampling(t *testing.T) {
+	const stores = 200
+	tests := []struct {
+		name     string
+		opts     []Option
+		min, max int
+	}{
+		{
+			name: "no sampling",
+			min:  stores,
+			max:  stores,
+		},
+		{
+			name: "half",
+			opts: []Option{WithEventSampling(0.5)},
+			min:  stores * 35 / 100,
+			max:  stores * 65 / 100,
+		},
+		{
+			name: "none",
+			opts: []Option{WithEventSampling(0)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registryName := newTestRegistry(t, nil)
+			ref := pushRandomImage(t, registryName)
+			opts := []SimpleStorerOption{WithTargetRepository(ref.Repository)}
+			for _, o := range tt.opts {
+				opts = append(opts, o)
+			}
+			storer, err := NewSimpleStorerFromConfig(opts...)
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+			storer.sampleRand = rand.New(rand.NewPCG(1, 2)).Float64
+
+			ctx, buf := newBufferedLoggerContext(t)
+			for i := 0; i < stores; i++ {
+				if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+					Artifact: ref,
+					Payload:  simple.NewSimpleStruct(ref),
+					Bundle:   &signing.Bundle{Signature: []byte{byte(i)}},
+				}); err != nil {
+					t.Fatalf("error during Store(): %v", err)
+				}
+			}
+
+			got := strings.Count(buf.String(), "Successfully uploaded signature")
+			if got < tt.min || got > tt.max {
+				t.Errorf("got %d success events out of %d stores, want between %d and %d", got, stores, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestEventSampling_InvalidRate(t *testing.T) {
+	for _, rate := range []float64{-0.1, 1.5} {
+		if _, err := NewAttestationStorer(WithEventSampling(rate)); err == nil {
+			t.Errorf("expected an error for sampling rate %v", rate)
+		}
+	}
+}
+
+func TestStore_MissingBundle(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	ref, err := name.NewDigest("example.com/test/img@sha256:" + strings.Repeat("a", 64))
+	if err != nil {
+		t.Fatalf("failed to parse digest: %v", err)
+	}
+
+	t.Run("attestation", func(t *testing.T) {
+		storer, err := NewAttestationStorer()
+		if err != nil {
+			t.Fatalf("failed to create storer: %v", err)
+		}
+		_, err = storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+			Artifact: ref,
+			Payload:  &intoto.Statement{},
+		})
+		if !errors.Is(err, ErrMissingBundle) {
+			t.Errorf("Store() error = %v, want %v", err, ErrMissingBundle)
+		}
+	})
+
+	t.Run("simple", func(t *testing.T) {
+		storer, err := NewSimpleStorerFromConfig()
+		if err != nil {
+			t.Fatalf("failed to create storer: %v", err)
+		}
+		_, err = storer.Store(ctx, &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+			Artifact: ref,
+			Payload:  simple.NewSimpleStruct(ref),
+		})
+		if !errors.Is(err, ErrMissingBundle) {
+			t.Errorf("Store() error = %v, want %v", err, ErrMissingBundle)
+		}
+	})
+}
+
+func TestNewStorer_OptionErrorIdentifiesOption(t *testing.T) {
+	_, err := NewAttestationStorer(WithProactiveRateLimiting(true), WithEventSampling(2))
+	if err == nil {
+		t.Fatal("expected an error for an invalid option")
+	}
+	if want := "applying option 1 (*oci.eventSamplingOption)"; !strings.Contains(err.Error(), want) {
+		t.Errorf("NewAttestationStorer() error = %q, want it to contain %q", err, want)
+	}
+
+	_, err = NewSimpleStorerFromConfig(WithEventSampling(-1))
+	if err == nil {
+		t.Fatal("expected an error for an invalid option")
+	}
+	if want := "applying option 0 (*oci.eventSamplingOption)"; !strings.Contains(err.Error(), want) {
+		t.Errorf("NewSimpleStorerFromConfig() error = %q, want it to contain %q", err, want)
+	}
+}
+
+func TestEntityFetchRetry(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int32
+		opts     []AttestationStorerOption
+		wantErr  bool
+	}{
+		{
+			name:     "no retry",
+			failures: 1,
+			wantErr:  true,
+		},
+		{
+			name:     "retry succeeds",
+			failures: 1,
+			opts:     []AttestationStorerOption{WithEntityFetchRetry(3, time.Millisecond)},
+		},
+		{
+			name:     "retries exhausted",
+			failures: 3,
+			opts:     []AttestationStorerOption{WithEntityFetchRetry(3, time.Millisecond)},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var failed atomic.Int32
+			registryName := newTestRegistry(t, func(h http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					// Fail the fetch of the artifact manifest.
+					if r.Method == http.MethodGet && strings.Contains(r.URL.Path, "/manifests/sha256:") && failed.Load() < tt.failures {
+						failed.Add(1)
+						w.WriteHeader(http.StatusServiceUnavailable)
+						return
+					}
+					h.ServeHTTP(w, r)
+				})
+			})
+			ref := pushRandomImage(t, registryName)
+
+			storer, err := NewAttestationStorer(append([]AttestationStorerOption{WithTargetRepository(ref.Repository)}, tt.opts...)...)
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+			// Disable the retries of the registry client so only the entity fetch retry applies.
+			storer.remoteOpts = []remote.Option{remote.WithRetryBackoff(remote.Backoff{Steps: 1}), remote.WithRetryStatusCodes()}
+
+			_, err = storer.Store(logtesting.TestContextWithLogger(t), &api.StoreRequest[name.Digest, *intoto.Statement]{
+				Artifact: ref,
+				Payload:  &intoto.Statement{},
+				Bundle:   &signing.Bundle{},
+			})
+			if gotErr := err != nil; gotErr != tt.wantErr {
+				t.Errorf("Store() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEntityFetchRetry_ContextCanceled(t *testing.T) {
+	registryName := newTestRegistry(t, func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet && strings.Contains(r.URL.Path, "/manifests/sha256:") {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	})
+	ref := pushRandomImage(t, registryName)
+
+	storer, err := NewSimpleStorerFromConfig(WithTargetRepository(ref.Repository), WithEntityFetchRetry(5, time.Hour))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	storer.remoteOpts = []remote.Option{remote.WithRetryBackoff(remote.Backoff{Steps: 1}), remote.WithRetryStatusCodes()}
+
+	ctx, cancel := context.WithTimeout(logtesting.TestContextWithLogger(t), 50*time.Millisecond)
+	defer cancel()
+	_, err = storer.Store(ctx, &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+		Artifact: ref,
+		Payload:  simple.NewSimpleStruct(ref),
+		Bundle:   &signing.Bundle{},
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Store() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestEntityFetchRetry_Invalid(t *testing.T) {
+	for _, o := range []Option{WithEntityFetchRetry(0, time.Second), WithEntityFetchRetry(2, -time.Second)} {
+		if _, err := NewAttestationStorer(o); err == nil {
+			t.Errorf("expected an error for option %+v", o)
+		}
+	}
+}
+
+func TestStore_ResponseDescribesManifest(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	registryName := newTestRegistry(t, nil)
+	ref := pushRandomImage(t, registryName)
+	tagPrefix := fmt.Sprintf("%s/test/img:%s", registryName, strings.ReplaceAll(ref.DigestStr(), ":", "-"))
+
+	attStorer, err := NewAttestationStorer()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	attResp, err := attStorer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  &intoto.Statement{},
+		Bundle:   &signing.Bundle{},
+	})
+	if err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+
+	sigStorer, err := NewSimpleStorerFromConfig()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	sigResp, err := sigStorer.Store(ctx, &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+		Artifact: ref,
+		Payload:  simple.NewSimpleStruct(ref),
+		Bundle:   &signing.Bundle{},
+	})
+	if err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+
+	for _, tt := range []struct {
+		resp    *api.StoreResponse
+		wantRef string
+	}{
+		{resp: attResp, wantRef: tagPrefix + ".att"},
+		{resp: sigResp, wantRef: tagPrefix + ".sig"},
+	} {
+		if tt.resp.Reference != tt.wantRef {
+			t.Errorf("got reference %q, want %q", tt.resp.Reference, tt.wantRef)
+		}
+		tag, err := name.NewTag(tt.wantRef)
+		if err != nil {
+			t.Fatalf("failed to parse tag: %v", err)
+		}
+		desc, err := remote.Head(tag)
+		if err != nil {
+			t.Fatalf("failed to get manifest: %v", err)
+		}
+		if tt.resp.Digest != desc.Digest.String() {
+			t.Errorf("got digest %q for %s, want %q", tt.resp.Digest, tt.wantRef, desc.Digest)
+		}
+	}
+}
+
+func TestWithStoreTimeout(t *testing.T) {
+	tests := []struct {
+		name  string
+		stall func(r *http.Request) bool
+	}{
+		{
+			name: "entity lookup",
+			stall: func(r *http.Request) bool {
+				return r.Method == http.MethodGet && strings.Contains(r.URL.Path, "/manifests/sha256:")
+			},
+		},
+		{
+			name: "write",
+			stall: func(r *http.Request) bool {
+				return r.Method == http.MethodPut && strings.Contains(r.URL.Path, "/manifests/")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stalling atomic.Bool
+			done := make(chan struct{})
+			registryName := newTestRegistry(t, func(h http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if stalling.Load() && tt.stall(r) {
+						<-done
+						return
+					}
+					h.ServeHTTP(w, r)
+				})
+			})
+			// Release the stalled requests before the registry is closed.
+			t.Cleanup(func() { close(done) })
+			ref := pushRandomImage(t, registryName)
+			stalling.Store(true)
+
+			storer, err := NewSimpleStorerFromConfig(WithTargetRepository(ref.Repository), WithStoreTimeout(50*time.Millisecond))
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+			_, err = storer.Store(logtesting.TestContextWithLogger(t), &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+				Artifact: ref,
+				Payload:  simple.NewSimpleStruct(ref),
+				Bundle:   &signing.Bundle{},
+			})
+			var terr *TimeoutError
+			if !errors.As(err, &terr) {
+				t.Fatalf("Store() error = %v, want a *TimeoutError", err)
+			}
+			if terr.Timeout != 50*time.Millisecond {
+				t.Errorf("got timeout %s, want %s", terr.Timeout, 50*time.Millisecond)
+			}
+		})
+	}
+}
+
+func TestWithStoreTimeout_CancellationIsNotTimeout(t *testing.T) {
+	var stalling atomic.Bool
+	done := make(chan struct{})
+	registryName := newTestRegistry(t, func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if stalling.Load() && r.Method == http.MethodPut && strings.Contains(r.URL.Path, "/manifests/") {
+				<-done
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	})
+	t.Cleanup(func() { close(done) })
+	ref := pushRandomImage(t, registryName)
+	stalling.Store(true)
+
+	storer, err := NewAttestationStorer(WithTargetRepository(ref.Repository), WithStoreTimeout(time.Hour))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	ctx, cancel := context.WithCancel(logtesting.TestContextWithLogger(t))
+	time.AfterFunc(50*time.Millisecond, cancel)
+	_, err = storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  &intoto.Statement{},
+		Bundle:   &signing.Bundle{},
+	})
+	var terr *TimeoutError
+	if errors.As(err, &terr) {
+		t.Errorf("Store() error = %v, want a cancellation rather than a timeout", err)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Store() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestWithStoreTimeout_Invalid(t *testing.T) {
+	if _, err := NewAttestationStorer(WithStoreTimeout(0)); err == nil {
+		t.Error("expected an error for a non-positive timeout")
+	}
+}
+
+func TestParseDigest(t *testing.T) {
+	const hex = "bc4f7468f87486e3835b09098c74cd7f54db2cf697cbb9b824271b95a2d0871e"
+	for _, in := range []string{
+		"example.com/foo@sha256:" + hex,
+		"example.com/foo@sha256:" + strings.ToUpper(hex),
+		"example.com/foo@SHA256:" + strings.ToUpper(hex),
+	} {
+		d, err := parseDigest(in)
+		if err != nil {
+			t.Errorf("parseDigest(%q) unexpected error: %v", in, err)
+			continue
+		}
+		if got, want := d.DigestStr(), "sha256:"+hex; got != want {
+			t.Errorf("parseDigest(%q) digest = %s, want %s", in, got, want)
+		}
+	}
+}
+
+type staticKeychain struct {
+	auth authn.Authenticator
+}
+
+func (k staticKeychain) Resolve(authn.Resource) (authn.Authenticator, error) {
+	return k.auth, nil
+}
+
+func TestWithKeychain(t *testing.T) {
+	good := &authn.Basic{Username: "user", Password: "pass"}
+	bad := &authn.Basic{Username: "user", Password: "wrong"}
+	tests := []struct {
+		name    string
+		opts    []AttestationStorerOption
+		wantErr bool
+	}{
+		{
+			name: "keychain",
+			opts: []AttestationStorerOption{WithKeychain(staticKeychain{good})},
+		},
+		{
+			name: "remote options",
+			opts: []AttestationStorerOption{WithRemoteOptions(remote.WithAuth(good))},
+		},
+		{
+			name: "authenticator",
+			opts: []AttestationStorerOption{WithAuthenticator(good)},
+		},
+		{
+			name: "later authenticator wins",
+			opts: []AttestationStorerOption{WithKeychain(staticKeychain{bad}), WithAuthenticator(good)},
+		},
+		{
+			name:    "later keychain wins",
+			opts:    []AttestationStorerOption{WithAuthenticator(good), WithKeychain(staticKeychain{bad})},
+			wantErr: true,
+		},
+		{
+			name:    "no auth",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var authenticating atomic.Bool
+			registryName := newTestRegistry(t, func(h http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if user, pass, ok := r.BasicAuth(); authenticating.Load() && (!ok || user != good.Username || pass != good.Password) {
+						w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+						w.WriteHeader(http.StatusUnauthorized)
+						return
+					}
+					h.ServeHTTP(w, r)
+				})
+			})
+			ref := pushRandomImage(t, registryName)
+			authenticating.Store(true)
+
+			storer, err := NewAttestationStorer(append([]AttestationStorerOption{WithTargetRepository(ref.Repository)}, tt.opts...)...)
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+			_, err = storer.Store(logtesting.TestContextWithLogger(t), &api.StoreRequest[name.Digest, *intoto.Statement]{
+				Artifact: ref,
+				Payload:  &intoto.Statement{},
+				Bundle:   &signing.Bundle{},
+			})
+			if gotErr := err != nil; gotErr != tt.wantErr {
+				t.Errorf("Store() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWithECRKeychain(t *testing.T) {
+	storer, err := NewSimpleStorerFromConfig(WithRemoteOptions(remote.WithUserAgent("test")), WithECRKeychain())
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	if storer.auth == nil || len(storer.remoteOpts) != 1 {
+		t.Errorf("expected the ECR keychain to be configured alongside the remote options")
+	}
+	for _, o := range []Option{WithKeychain(nil), WithAuthenticator(nil)} {
+		if _, err := NewSimpleStorerFromConfig(o); err == nil {
+			t.Errorf("expected an error for option %+v", o)
+		}
+	}
+}
+
+func TestWithGoogleKeychain(t *testing.T) {
+	good := &authn.Basic{Username: "oauth2accesstoken", Password: "token"}
+	// Only writes are authenticated, reads are anonymous.
+	var authenticating atomic.Bool
+	registryName := newTestRegistry(t, func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if authenticating.Load() && r.Method == http.MethodPut {
+				if user, pass, ok := r.BasicAuth(); !ok || user != good.Username || pass != good.Password {
+					w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+			}
+			h.ServeHTTP(w, r)
+		})
+	})
+	ref := pushRandomImage(t, registryName)
+	authenticating.Store(true)
+
+	orig := googleKeychain
+	t.Cleanup(func() { googleKeychain = orig })
+	googleKeychain = staticKeychain{good}
+
+	storer, err := NewSimpleStorerFromConfig(WithTargetRepository(ref.Repository), WithGoogleKeychain())
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	if _, err := storer.Store(logtesting.TestContextWithLogger(t), &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+		Artifact: ref,
+		Payload:  simple.NewSimpleStruct(ref),
+		Bundle:   &signing.Bundle{},
+	}); err != nil {
+		t.Errorf("error during Store(): %v", err)
+	}
+}
+
+// countingTransport counts the requests sent through it.
+type countingTransport struct {
+	inner    http.RoundTripper
+	requests atomic.Int32
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests.Add(1)
+	return t.inner.RoundTrip(req)
+}
+
+func TestWithTransport(t *testing.T) {
+	good := &authn.Basic{Username: "user", Password: "pass"}
+	var authenticating atomic.Bool
+	registryName := newTestRegistry(t, func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if user, pass, ok := r.BasicAuth(); authenticating.Load() && (!ok || user != good.Username || pass != good.Password) {
+				w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	})
+	ref := pushRandomImage(t, registryName)
+	authenticating.Store(true)
+
+	rt := &countingTransport{inner: http.DefaultTransport}
+	storer, err := NewAttestationStorer(WithTargetRepository(ref.Repository), WithTransport(rt), WithAuthenticator(good), WithProactiveRateLimiting(true))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	if _, err := storer.Store(logtesting.TestContextWithLogger(t), &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  &intoto.Statement{},
+		Bundle:   &signing.Bundle{},
+	}); err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+	if rt.requests.Load() == 0 {
+		t.Error("expected the requests to go through the transport")
+	}
+	if _, err := NewAttestationStorer(WithTransport(nil)); err == nil {
+		t.Error("expected an error for a nil transport")
+	}
+}
+
+func TestStore_InvalidArtifact(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	transport := &countingTransport{inner: http.DefaultTransport}
+	as, err := NewAttestationStorer(WithTransport(transport))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	ss, err := NewSimpleStorerFromConfig(WithTransport(transport))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	// The zero name.Digest names no digest.
+	var artifact name.Digest
+	bundle := &signing.Bundle{Content: []byte("content"), Signature: []byte("signature")}
+	attReq := &api.StoreRequest[name.Digest, *intoto.Statement]{Artifact: artifact, Bundle: bundle}
+	sigReq := &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{Artifact: artifact, Bundle: bundle}
+
+	if _, err := as.Store(ctx, attReq); !errors.Is(err, ErrInvalidArtifact) {
+		t.Errorf("AttestationStorer.Store() = %v, want ErrInvalidArtifact", err)
+	}
+	if _, err := ss.Store(ctx, sigReq); !errors.Is(err, ErrInvalidArtifact) {
+		t.Errorf("SimpleStorer.Store() = %v, want ErrInvalidArtifact", err)
+	}
+	if _, _, err := StoreBoth(ctx, ss, as, sigReq, attReq); !errors.Is(err, ErrInvalidArtifact) {
+		t.Errorf("StoreBoth() = %v, want ErrInvalidArtifact", err)
+	}
+	if n := transport.requests.Load(); n != 0 {
+		t.Errorf("got %d requests, want none", n)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"maps"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/pkg/errors"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	"google.golang.org/protobuf/proto"
+)
+
+// SubjectMatchMode controls when two subjects are considered the same.
+type SubjectMatchMode int
+
+const (
+	// SubjectMatchDigest treats subjects with a common digest as the same,
+	// regardless of their names.
+	SubjectMatchDigest SubjectMatchMode = iota
+	// SubjectMatchNameAndDigest treats subjects as the same only if both their
+	// names and digests match.
+	SubjectMatchNameAndDigest
+)
+
+// subjectFromDigest converts a digest reference into an in-toto subject.
+func subjectFromDigest(d name.Digest) *intoto.ResourceDescriptor {
+	algorithm, hex, _ := strings.Cut(d.DigestStr(), ":")
+	return &intoto.ResourceDescriptor{
+		Name: d.Repository.Name(),
+		Digest: map[string]string{
+			algorithm: hex,
+		},
+	}
+}
+
+// MergeEquivalentSubjects returns a copy of statement with the subjects
+// configured by WithEquivalentSubjects added to it. The subjects are part of
+// the signed payload, so statements must be merged before they are signed;
+// statement itself is not modified.
+func (s *AttestationStorer) MergeEquivalentSubjects(statement *intoto.Statement) *intoto.Statement {
+	if statement == nil {
+		return nil
+	}
+	merged := proto.Clone(statement).(*intoto.Statement)
+	mergeSubjects(merged, s.equivalentSubjects, s.subjectMatch)
+	return merged
+}
+
+// checkEquivalentSubjects checks that the statement to store records the
+// subjects configured by WithEquivalentSubjects. The signed statement is
+// checked, so envelopes that cannot be decoded are rejected rather than
+// trusting the statement of the request.
+func (s *AttestationStorer) checkEquivalentSubjects(req *api.StoreRequest[name.Digest, *intoto.Statement]) error {
+	statement, err := statementFromEnvelope(req.Bundle.Signature)
+	if err != nil {
+		return errors.Wrapf(err, "checking equivalent subjects of the attestation for %s", req.Artifact.String())
+	}
+	for _, subj := range s.equivalentSubjects {
+		if !containsSubject(statement.GetSubject(), subj, s.subjectMatch) {
+			return errors.Errorf("the statement for %s does not record the equivalent subject %s, merge the subjects with MergeEquivalentSubjects before signing it", req.Artifact.String(), subj.GetName())
+		}
+	}
+	return nil
+}
+
+// mergeSubjects adds the given subjects to the statement, skipping any that
+// the statement already records.
+func mergeSubjects(statement *intoto.Statement, subjects []*intoto.ResourceDescriptor, mode SubjectMatchMode) {
+	for _, subj := range subjects {
+		if !containsSubject(statement.Subject, subj, mode) {
+			statement.Subject = append(statement.Subject, subj)
+		}
+	}
+}
+
+func containsSubject(subjects []*intoto.ResourceDescriptor, subj *intoto.ResourceDescriptor, mode SubjectMatchMode) bool {
+	for _, s := range subjects {
+		if subjectsMatch(s, subj, mode) {
+			return true
+		}
+	}
+	return false
+}
+
+func subjectsMatch(a, b *intoto.ResourceDescriptor, mode SubjectMatchMode) bool {
+	switch mode {
+	case SubjectMatchNameAndDigest:
+		return a.GetName() == b.GetName() && maps.Equal(a.GetDigest(), b.GetDigest())
+	default:
+		for alg, hex := range a.GetDigest() {
+			if v, ok := b.GetDigest()[alg]; ok && v == hex {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// validateSubject checks that a subject of the statement to store has the
+// digest of the artifact. The signed statement is checked, or the statement of
+// the request if the envelope cannot be decoded. Digest algorithms and values
+// are compared case-insensitively.
+func validateSubject(req *api.StoreRequest[name.Digest, *intoto.Statement]) error {
+	statement, err := statementFromEnvelope(req.Bundle.Signature)
+	if err != nil {
+		statement = req.Payload
+	}
+	if statement == nil {
+		return errors.Errorf("validating subjects: no statement to validate for %s", req.Artifact.String())
+	}
+	want := normalizedSubject(subjectFromDigest(req.Artifact))
+	var got []string
+	for _, subj := range statement.GetSubject() {
+		if subjectsMatch(normalizedSubject(subj), want, SubjectMatchDigest) {
+			return nil
+		}
+		for alg, hex := range subj.GetDigest() {
+			got = append(got, alg+":"+hex)
+		}
+	}
+	return errors.Errorf("no subject of the statement matches %s, got subject digests %v", req.Artifact.DigestStr(), got)
+}
+
+// normalizedSubject returns a copy of the subject with lower-case digest
+// algorithms and values.
+func normalizedSubject(subj *intoto.ResourceDescriptor) *intoto.ResourceDescriptor {
+	digest := make(map[string]string, len(subj.GetDigest()))
+	for alg, hex := range subj.GetDigest() {
+		digest[strings.ToLower(alg)] = strings.ToLower(hex)
+	}
+	return &intoto.ResourceDescriptor{Name: subj.GetName(), Digest: digest}
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	"google.golang.org/protobuf/encoding/protojson"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestWithSubjectValidation(t *testing.T) {
+	registryName := newTestRegistry(t, nil)
+	ref := pushRandomImage(t, registryName)
+	other := pushRandomImage(t, registryName)
+	_, hex, _ := strings.Cut(ref.DigestStr(), ":")
+	_, otherHex, _ := strings.Cut(other.DigestStr(), ":")
+
+	tests := []struct {
+		name     string
+		subjects []*intoto.ResourceDescriptor
+		opts     []AttestationStorerOption
+		wantErr  bool
+	}{
+		{
+			name:     "matching subject",
+			subjects: []*intoto.ResourceDescriptor{{Name: "other", Digest: map[string]string{"sha256": otherHex}}, {Name: "img", Digest: map[string]string{"sha256": hex}}},
+			opts:     []AttestationStorerOption{WithSubjectValidation()},
+		},
+		{
+			name:     "matching subject in upper case",
+			subjects: []*intoto.ResourceDescriptor{{Name: "img", Digest: map[string]string{"SHA256": strings.ToUpper(hex)}}},
+			opts:     []AttestationStorerOption{WithSubjectValidation()},
+		},
+		{
+			name:     "mismatched subject",
+			subjects: []*intoto.ResourceDescriptor{{Name: "other", Digest: map[string]string{"sha256": otherHex}}},
+			opts:     []AttestationStorerOption{WithSubjectValidation()},
+			wantErr:  true,
+		},
+		{
+			name:     "other digest algorithm",
+			subjects: []*intoto.ResourceDescriptor{{Name: "img", Digest: map[string]string{"sha512": hex}}},
+			opts:     []AttestationStorerOption{WithSubjectValidation()},
+			wantErr:  true,
+		},
+		{
+			name:     "mismatched subject without validation",
+			subjects: []*intoto.ResourceDescriptor{{Name: "other", Digest: map[string]string{"sha256": otherHex}}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := logtesting.TestContextWithLogger(t)
+			statement := &intoto.Statement{
+				Type:          intoto.StatementTypeUri,
+				Subject:       tc.subjects,
+				PredicateType: "https://slsa.dev/provenance/v1",
+			}
+			payload, err := protojson.Marshal(statement)
+			if err != nil {
+				t.Fatalf("failed to marshal statement: %v", err)
+			}
+			storer, err := NewAttestationStorer(tc.opts...)
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+			_, err = storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+				Artifact: ref,
+				Payload:  statement,
+				Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+			})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Store() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr && !strings.Contains(err.Error(), ref.DigestStr()) {
+				t.Errorf("Store() error = %v, want it to name %s", err, ref.DigestStr())
+			}
+		})
+	}
+}
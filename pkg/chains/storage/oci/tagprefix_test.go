@@ -0,0 +1,106 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestWithTagPrefix(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	ref := pushRandomImage(t, newTestRegistry(t, nil))
+	as, err := NewAttestationStorer(WithTagPrefix("chains-"))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	storeTestAttestations(t, as, ref, "https://example.com/a", "https://example.com/b")
+	ss, err := NewSimpleStorerFromConfig(WithTagPrefix("chains-"))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	resp, err := ss.Store(ctx, &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+		Artifact: ref,
+		Payload:  simple.NewSimpleStruct(ref),
+		Bundle:   &signing.Bundle{Content: []byte("{}"), Signature: []byte("signature")},
+	})
+	if err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+	if want := ref.Context().Tag("chains-sha256-" + strings.TrimPrefix(ref.DigestStr(), "sha256:") + ".sig").String(); resp.Reference != want {
+		t.Errorf("Store() reference = %s, want %s", resp.Reference, want)
+	}
+
+	for _, tagFor := range []func(name.Reference, ...ociremote.Option) (name.Tag, error){ociremote.SignatureTag, ociremote.AttestationTag} {
+		prefixed, err := tagFor(ref, ociremote.WithPrefix("chains-"))
+		if err != nil {
+			t.Fatalf("failed to name the tag: %v", err)
+		}
+		if _, err := remote.Head(prefixed); err != nil {
+			t.Errorf("%s was not written: %v", prefixed, err)
+		}
+		unprefixed, err := tagFor(ref)
+		if err != nil {
+			t.Fatalf("failed to name the tag: %v", err)
+		}
+		if _, err := remote.Head(unprefixed); err == nil {
+			t.Errorf("%s was written", unprefixed)
+		}
+	}
+
+	// The prefixed tags are read back, and appended to.
+	statements, err := as.Retrieve(ctx, ref)
+	if err != nil {
+		t.Fatalf("error during Retrieve(): %v", err)
+	}
+	if len(statements) != 2 {
+		t.Errorf("Retrieve() returned %d statements, want 2", len(statements))
+	}
+	unprefixed, err := NewAttestationStorer()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	if statements, err := unprefixed.Retrieve(ctx, ref); err == nil && len(statements) > 0 {
+		t.Errorf("Retrieve() without the prefix returned %d statements", len(statements))
+	}
+}
+
+func TestWithTagPrefix_Invalid(t *testing.T) {
+	for _, prefix := range []string{
+		"",
+		"-chains",
+		".chains",
+		"chains/",
+		"chains:",
+		strings.Repeat("p", 54),
+	} {
+		t.Run(prefix, func(t *testing.T) {
+			if _, err := NewAttestationStorer(WithTagPrefix(prefix)); err == nil {
+				t.Errorf("expected an error for prefix %q", prefix)
+			}
+		})
+	}
+	if _, err := NewAttestationStorer(WithTagPrefix(strings.Repeat("p", 53))); err != nil {
+		t.Errorf("unexpected error for the longest prefix: %v", err)
+	}
+}
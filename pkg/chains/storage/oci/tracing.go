@@ -0,0 +1,61 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName names the tracer of the spans of the storers.
+const tracerName = "github.com/tektoncd/chains/pkg/chains/storage/oci"
+
+// Span attributes. Digests are already bounded in size and are recorded as
+// is, so that spans can be matched with the stored artifacts.
+const (
+	formatAttribute     = attribute.Key("chains.oci.format")
+	repositoryAttribute = attribute.Key("chains.oci.repository")
+	digestAttribute     = attribute.Key("chains.oci.artifact_digest")
+	writeAttribute      = attribute.Key("chains.oci.write")
+)
+
+// startSpan starts a span with the tracer provider of the span of ctx. Spans
+// are only recorded when the caller traces the context, so the storers do
+// not depend on a globally configured provider.
+func startSpan(ctx context.Context, spanName string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	tracer := trace.SpanFromContext(ctx).TracerProvider().Tracer(tracerName)
+	return tracer.Start(ctx, spanName, trace.WithAttributes(attrs...))
+}
+
+// endSpan ends span, recording err on it if set.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// artifactAttributes describe the artifact stored in repo.
+func artifactAttributes(artifact name.Digest, repo name.Repository) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		repositoryAttribute.String(repo.String()),
+		digestAttribute.String(artifact.DigestStr()),
+	}
+}
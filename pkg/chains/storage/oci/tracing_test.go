@@ -0,0 +1,200 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// recordingProvider records the spans started by its tracers.
+type recordingProvider struct {
+	noop.TracerProvider
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+func (p *recordingProvider) Tracer(string, ...trace.TracerOption) trace.Tracer {
+	return &recordingTracer{provider: p}
+}
+
+// ended returns the ended spans by name.
+func (p *recordingProvider) ended() map[string]*recordedSpan {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	spans := map[string]*recordedSpan{}
+	for _, s := range p.spans {
+		if s.ended {
+			spans[s.name] = s
+		}
+	}
+	return spans
+}
+
+type recordingTracer struct {
+	noop.Tracer
+	provider *recordingProvider
+}
+
+func (t *recordingTracer) Start(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	s := &recordedSpan{
+		name:     spanName,
+		attrs:    map[string]string{},
+		provider: t.provider,
+	}
+	cfg := trace.NewSpanStartConfig(opts...)
+	for _, kv := range cfg.Attributes() {
+		s.attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	t.provider.mu.Lock()
+	t.provider.spans = append(t.provider.spans, s)
+	t.provider.mu.Unlock()
+	return trace.ContextWithSpan(ctx, s), s
+}
+
+type recordedSpan struct {
+	noop.Span
+	provider *recordingProvider
+	name     string
+	attrs    map[string]string
+	status   codes.Code
+	errs     []error
+	ended    bool
+}
+
+func (s *recordedSpan) TracerProvider() trace.TracerProvider { return s.provider }
+
+func (s *recordedSpan) SetAttributes(kvs ...attribute.KeyValue) {
+	for _, kv := range kvs {
+		s.attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+}
+
+func (s *recordedSpan) RecordError(err error, _ ...trace.EventOption) { s.errs = append(s.errs, err) }
+
+func (s *recordedSpan) SetStatus(code codes.Code, _ string) { s.status = code }
+
+func (s *recordedSpan) End(...trace.SpanEndOption) {
+	s.provider.mu.Lock()
+	defer s.provider.mu.Unlock()
+	s.ended = true
+}
+
+func TestStoreSpans(t *testing.T) {
+	ref := pushRandomImage(t, newTestRegistry(t, nil))
+	tp := &recordingProvider{}
+	ctx, parent := tp.Tracer("").Start(logtesting.TestContextWithLogger(t), "reconcile")
+	defer parent.End()
+
+	storer, err := NewAttestationStorer()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	statement, payload := newTestStatement(t, ref, "https://example.com/predicate")
+	if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  statement,
+		Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+	}); err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+
+	spans := tp.ended()
+	store, ok := spans["oci.AttestationStorer.Store"]
+	if !ok {
+		t.Fatalf("no store span in %v", spans)
+	}
+	want := map[string]string{
+		"chains.oci.format":          attestationFormat,
+		"chains.oci.repository":      ref.Context().String(),
+		"chains.oci.artifact_digest": ref.DigestStr(),
+	}
+	if diff := cmp.Diff(want, store.attrs); diff != "" {
+		t.Errorf("unexpected store span attributes (-want +got):\n%s", diff)
+	}
+	if store.status == codes.Error || len(store.errs) > 0 {
+		t.Errorf("unexpected error on the store span: %v", store.errs)
+	}
+	if _, ok := spans["oci.lookupEntity"]; !ok {
+		t.Errorf("no lookup span in %v", spans)
+	}
+	write, ok := spans["oci.write"]
+	if !ok {
+		t.Fatalf("no write span in %v", spans)
+	}
+	if got := write.attrs["chains.oci.write"]; got == "" {
+		t.Error("the write span does not describe the write")
+	}
+}
+
+func TestStoreSpans_Error(t *testing.T) {
+	var rejecting atomic.Bool
+	ref := pushRandomImage(t, newTestRegistry(t, func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if rejecting.Load() && r.Method == http.MethodPut && strings.Contains(r.URL.Path, "/manifests/") {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	}))
+	rejecting.Store(true)
+	tp := &recordingProvider{}
+	ctx, parent := tp.Tracer("").Start(logtesting.TestContextWithLogger(t), "reconcile")
+	defer parent.End()
+
+	storer, err := NewAttestationStorer()
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	statement, payload := newTestStatement(t, ref, "https://example.com/predicate")
+	if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  statement,
+		Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+	}); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	spans := tp.ended()
+	for _, name := range []string{"oci.AttestationStorer.Store", "oci.write"} {
+		span, ok := spans[name]
+		if !ok {
+			t.Errorf("no %s span in %v", name, spans)
+			continue
+		}
+		if span.status != codes.Error || len(span.errs) != 1 {
+			t.Errorf("the %s span did not record the error: %v", name, span.errs)
+		}
+	}
+	if lookup, ok := spans["oci.lookupEntity"]; !ok || lookup.status == codes.Error {
+		t.Errorf("unexpected lookup span: %+v", lookup)
+	}
+}
@@ -0,0 +1,292 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/pkg/errors"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/transparency-dev/merkle/proof"
+	"github.com/transparency-dev/merkle/rfc6962"
+)
+
+const (
+	// TransparencyIndexTag is the tag under which the transparency index of a
+	// repository is stored.
+	TransparencyIndexTag = "transparency-index"
+
+	transparencyIndexMediaType types.MediaType = "application/vnd.dev.tekton.chains.transparency-index.v1+json"
+
+	// transparencyIndexAttempts bounds the updates of the transparency index
+	// attempted when other writers keep changing it.
+	transparencyIndexAttempts = 5
+)
+
+// TransparencyIndex is an append-only record of the attestations stored in a
+// repository. The leaves form an RFC 6962 Merkle tree whose tree head is
+// signed, so consumers can check that an attestation they have seen is still
+// part of the index.
+type TransparencyIndex struct {
+	// Leaves are the digests of the stored attestations, in the order they were stored.
+	Leaves []string `json:"leaves"`
+	// TreeSize is the number of leaves in the tree.
+	TreeSize uint64 `json:"treeSize"`
+	// RootHash is the hex encoded Merkle tree root hash over the leaves.
+	RootHash string `json:"rootHash"`
+	// Signature is the signature of the tree head, as returned by TreeHead,
+	// by the signer set with WithTransparencyIndexSigner.
+	Signature []byte `json:"signature,omitempty"`
+}
+
+// InclusionProof proves that a leaf is included in a TransparencyIndex.
+type InclusionProof struct {
+	LeafIndex uint64
+	TreeSize  uint64
+	RootHash  []byte
+	Hashes    [][]byte
+}
+
+// FetchTransparencyIndex reads the transparency index of the given repository
+// and checks that its root hash matches its leaves and that its tree head is
+// signed by verifier. An empty index is returned if the repository has none
+// yet.
+func FetchTransparencyIndex(repo name.Repository, verifier signature.Verifier, opts ...remote.Option) (*TransparencyIndex, error) {
+	idx, _, err := fetchTransparencyIndex(repo, verifier, opts...)
+	return idx, err
+}
+
+// fetchTransparencyIndex reads and checks the transparency index of repo
+// like FetchTransparencyIndex, also returning the digest of its manifest, or
+// an empty digest if repo has no index yet.
+func fetchTransparencyIndex(repo name.Repository, verifier signature.Verifier, opts ...remote.Option) (*TransparencyIndex, string, error) {
+	img, err := remote.Image(repo.Tag(TransparencyIndexTag), opts...)
+	var terr *transport.Error
+	if errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound {
+		return newTransparencyIndex(nil), "", nil
+	} else if err != nil {
+		return nil, "", errors.Wrap(err, "fetching transparency index")
+	}
+	digest, err := img.Digest()
+	if err != nil {
+		return nil, "", errors.Wrap(err, "fetching transparency index")
+	}
+	layers, err := img.Layers()
+	if err != nil {
+		return nil, "", errors.Wrap(err, "fetching transparency index")
+	}
+	if len(layers) != 1 {
+		return nil, "", errors.Errorf("transparency index has %d layers, expected 1", len(layers))
+	}
+	rc, err := layers[0].Uncompressed()
+	if err != nil {
+		return nil, "", errors.Wrap(err, "fetching transparency index")
+	}
+	defer rc.Close()
+	b, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "fetching transparency index")
+	}
+	idx := &TransparencyIndex{}
+	if err := json.Unmarshal(b, idx); err != nil {
+		return nil, "", errors.Wrap(err, "decoding transparency index")
+	}
+	if err := idx.verify(); err != nil {
+		return nil, "", err
+	}
+	if err := verifier.VerifySignature(bytes.NewReader(idx.Signature), bytes.NewReader(idx.TreeHead(repo))); err != nil {
+		return nil, "", errors.Wrap(err, "verifying the signature of the transparency index")
+	}
+	return idx, digest.String(), nil
+}
+
+// TreeHead returns the tree head of the index stored in repo, which is what
+// the signature of the index covers: the repository, the tree size and the
+// root hash, one per line.
+func (idx *TransparencyIndex) TreeHead(repo name.Repository) []byte {
+	return fmt.Appendf(nil, "%s\n%d\n%s\n", repo.Name(), idx.TreeSize, idx.RootHash)
+}
+
+func newTransparencyIndex(leaves []string) *TransparencyIndex {
+	return &TransparencyIndex{
+		Leaves:   leaves,
+		TreeSize: uint64(len(leaves)),
+		RootHash: hex.EncodeToString(merkleRoot(leafHashes(leaves))),
+	}
+}
+
+// verify checks that the recorded tree size and root hash match the leaves.
+func (idx *TransparencyIndex) verify() error {
+	want := newTransparencyIndex(idx.Leaves)
+	if idx.TreeSize != want.TreeSize || idx.RootHash != want.RootHash {
+		return errors.New("transparency index root hash does not match its leaves")
+	}
+	return nil
+}
+
+// InclusionProof returns a proof that the given attestation digest is part of the index.
+func (idx *TransparencyIndex) InclusionProof(leaf string) (*InclusionProof, error) {
+	i := slices.Index(idx.Leaves, leaf)
+	if i < 0 {
+		return nil, errors.Errorf("%s is not in the transparency index", leaf)
+	}
+	root, err := hex.DecodeString(idx.RootHash)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding transparency index root hash")
+	}
+	return &InclusionProof{
+		LeafIndex: uint64(i),
+		TreeSize:  idx.TreeSize,
+		RootHash:  root,
+		Hashes:    merklePath(i, leafHashes(idx.Leaves)),
+	}, nil
+}
+
+// VerifyInclusionProof checks that the proof shows the given attestation digest
+// is included in the tree with the proof's root hash.
+func VerifyInclusionProof(leaf string, p *InclusionProof) error {
+	return proof.VerifyInclusion(rfc6962.DefaultHasher, p.LeafIndex, p.TreeSize, rfc6962.DefaultHasher.HashLeaf([]byte(leaf)), p.Hashes, p.RootHash)
+}
+
+// transparencyIndexer appends stored attestations to the transparency index.
+// The index is updated with a read-modify-write, which is serialized within
+// the process. Registries cannot swap a tag atomically, so the write is
+// conditional on the index still being the one read, and the index is read
+// back to retry the update if another writer replaced it.
+type transparencyIndexer struct {
+	mu sync.Mutex
+	// signer signs the tree heads and verifies those of the indexes read.
+	signer signature.SignerVerifier
+}
+
+// append adds the given attestation digest to the transparency index of repo.
+func (t *transparencyIndexer) append(repo name.Repository, leaf string, opts ...remote.Option) (*TransparencyIndex, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for attempt := 0; ; attempt++ {
+		idx, digest, err := fetchTransparencyIndex(repo, t.signer, opts...)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(idx.Leaves, leaf) {
+			return idx, nil
+		}
+		if attempt == transparencyIndexAttempts {
+			return nil, errors.Errorf("updating the transparency index of %s: it was replaced by other writers %d times", repo.String(), attempt)
+		}
+		current, err := headDigest(repo.Tag(TransparencyIndexTag), opts...)
+		if err != nil {
+			return nil, errors.Wrap(err, "fetching transparency index")
+		}
+		if current != digest {
+			continue
+		}
+		if err := t.write(repo, append(slices.Clone(idx.Leaves), leaf), opts...); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// write replaces the transparency index of repo with the signed index of the
+// given leaves.
+func (t *transparencyIndexer) write(repo name.Repository, leaves []string, opts ...remote.Option) error {
+	idx := newTransparencyIndex(leaves)
+	sig, err := t.signer.SignMessage(bytes.NewReader(idx.TreeHead(repo)))
+	if err != nil {
+		return errors.Wrap(err, "signing transparency index")
+	}
+	idx.Signature = sig
+	b, err := json.Marshal(idx)
+	if err != nil {
+		return errors.Wrap(err, "encoding transparency index")
+	}
+	img, err := mutate.AppendLayers(mutate.MediaType(empty.Image, types.OCIManifestSchema1), static.NewLayer(b, transparencyIndexMediaType))
+	if err != nil {
+		return errors.Wrap(err, "building transparency index")
+	}
+	if err := remote.Write(repo.Tag(TransparencyIndexTag), img, opts...); err != nil {
+		return errors.Wrap(err, "writing transparency index")
+	}
+	return nil
+}
+
+// headDigest returns the digest of the manifest tagged tag, or an empty digest
+// if there is none.
+func headDigest(tag name.Tag, opts ...remote.Option) (string, error) {
+	desc, err := remote.Head(tag, opts...)
+	var terr *transport.Error
+	if errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	return desc.Digest.String(), nil
+}
+
+func leafHashes(leaves []string) [][]byte {
+	hashes := make([][]byte, 0, len(leaves))
+	for _, l := range leaves {
+		hashes = append(hashes, rfc6962.DefaultHasher.HashLeaf([]byte(l)))
+	}
+	return hashes
+}
+
+// merkleRoot computes the RFC 6962 Merkle tree hash of the given leaf hashes.
+func merkleRoot(hashes [][]byte) []byte {
+	switch len(hashes) {
+	case 0:
+		return rfc6962.DefaultHasher.EmptyRoot()
+	case 1:
+		return hashes[0]
+	}
+	k := splitPoint(len(hashes))
+	return rfc6962.DefaultHasher.HashChildren(merkleRoot(hashes[:k]), merkleRoot(hashes[k:]))
+}
+
+// merklePath computes the RFC 6962 audit path for the m-th leaf hash.
+func merklePath(m int, hashes [][]byte) [][]byte {
+	if len(hashes) <= 1 {
+		return nil
+	}
+	k := splitPoint(len(hashes))
+	if m < k {
+		return append(merklePath(m, hashes[:k]), merkleRoot(hashes[k:]))
+	}
+	return append(merklePath(m-k, hashes[k:]), merkleRoot(hashes[:k]))
+}
+
+// splitPoint returns the largest power of two smaller than n.
+func splitPoint(n int) int {
+	k := 1
+	for k<<1 < n {
+		k <<= 1
+	}
+	return k
+}
@@ -0,0 +1,247 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func newTestTransparencySigner(t *testing.T) signature.SignerVerifier {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	sv, err := signature.LoadECDSASignerVerifier(priv, crypto.SHA256)
+	if err != nil {
+		t.Fatalf("failed to load signer: %v", err)
+	}
+	return sv
+}
+
+func TestAttestationStorer_TransparencyIndex(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	registryName := newTestRegistry(t, nil)
+	ref := pushRandomImage(t, registryName)
+	signer := newTestTransparencySigner(t)
+
+	storer, err := NewAttestationStorer(WithTargetRepository(ref.Repository), WithTransparencyIndex(true), WithTransparencyIndexSigner(signer))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+
+	const stores = 5
+	for i := 0; i < stores; i++ {
+		if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+			Artifact: ref,
+			Payload:  &intoto.Statement{},
+			Bundle:   &signing.Bundle{Signature: []byte(fmt.Sprintf("envelope-%d", i))},
+		}); err != nil {
+			t.Fatalf("error during Store(): %v", err)
+		}
+		idx, err := FetchTransparencyIndex(ref.Repository, signer)
+		if err != nil {
+			t.Fatalf("failed to fetch transparency index: %v", err)
+		}
+		if want := uint64(i + 1); idx.TreeSize != want {
+			t.Errorf("got tree size %d after %d stores, want %d", idx.TreeSize, i+1, want)
+		}
+	}
+
+	idx, err := FetchTransparencyIndex(ref.Repository, signer)
+	if err != nil {
+		t.Fatalf("failed to fetch transparency index: %v", err)
+	}
+	for _, leaf := range idx.Leaves {
+		p, err := idx.InclusionProof(leaf)
+		if err != nil {
+			t.Fatalf("failed to get inclusion proof for %s: %v", leaf, err)
+		}
+		if err := VerifyInclusionProof(leaf, p); err != nil {
+			t.Errorf("inclusion proof for %s did not verify: %v", leaf, err)
+		}
+		if err := VerifyInclusionProof("sha256:removed", p); err == nil {
+			t.Errorf("inclusion proof for %s verified a different leaf", leaf)
+		}
+	}
+}
+
+func TestAttestationStorer_TransparencyIndexDisabled(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	registryName := newTestRegistry(t, nil)
+	ref := pushRandomImage(t, registryName)
+
+	storer, err := NewAttestationStorer(WithTargetRepository(ref.Repository))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  &intoto.Statement{},
+		Bundle:   &signing.Bundle{},
+	}); err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+	idx, err := FetchTransparencyIndex(ref.Repository, newTestTransparencySigner(t))
+	if err != nil {
+		t.Fatalf("failed to fetch transparency index: %v", err)
+	}
+	if idx.TreeSize != 0 {
+		t.Errorf("got tree size %d, want an empty index", idx.TreeSize)
+	}
+}
+
+func TestTransparencyIndex_InclusionProofs(t *testing.T) {
+	for size := 1; size <= 17; size++ {
+		var leaves []string
+		for i := 0; i < size; i++ {
+			leaves = append(leaves, fmt.Sprintf("sha256:%064d", i))
+		}
+		idx := newTransparencyIndex(leaves)
+		for _, leaf := range leaves {
+			p, err := idx.InclusionProof(leaf)
+			if err != nil {
+				t.Fatalf("size %d: failed to get inclusion proof for %s: %v", size, leaf, err)
+			}
+			if err := VerifyInclusionProof(leaf, p); err != nil {
+				t.Errorf("size %d: inclusion proof for %s did not verify: %v", size, leaf, err)
+			}
+		}
+	}
+}
+
+func TestTransparencyIndex_Tampered(t *testing.T) {
+	idx := newTransparencyIndex([]string{"sha256:a", "sha256:b", "sha256:c"})
+	idx.Leaves = idx.Leaves[1:]
+	if err := idx.verify(); err == nil {
+		t.Error("expected an error for an index with a removed leaf")
+	}
+	if _, err := idx.InclusionProof("sha256:a"); err == nil {
+		t.Error("expected an error for a leaf that is not in the index")
+	}
+}
+
+func TestTransparencyIndex_Signature(t *testing.T) {
+	repo := pushRandomImage(t, newTestRegistry(t, nil)).Repository
+	signer, other := newTestTransparencySigner(t), newTestTransparencySigner(t)
+	indexer := &transparencyIndexer{signer: signer}
+	if _, err := indexer.append(repo, "sha256:a"); err != nil {
+		t.Fatalf("failed to append to the transparency index: %v", err)
+	}
+	if _, err := FetchTransparencyIndex(repo, signer); err != nil {
+		t.Errorf("failed to fetch transparency index: %v", err)
+	}
+	if _, err := FetchTransparencyIndex(repo, other); err == nil {
+		t.Error("expected an error for an index signed by another signer")
+	}
+
+	// An index rewritten without the key, even with a consistent root hash,
+	// is rejected, and is not appended to.
+	forger := &transparencyIndexer{signer: other}
+	if err := forger.write(repo, []string{"sha256:b"}); err != nil {
+		t.Fatalf("failed to write transparency index: %v", err)
+	}
+	if _, err := FetchTransparencyIndex(repo, signer); err == nil {
+		t.Error("expected an error for a forged index")
+	}
+	if _, err := indexer.append(repo, "sha256:c"); err == nil {
+		t.Error("expected an error appending to a forged index")
+	}
+}
+
+func TestTransparencyIndex_ConcurrentWriters(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		// method is the request of the storer before which another writer
+		// replaces the index.
+		method string
+		// stale is whether the other writer replaces the index with one that
+		// drops the leaf of the storer, as a writer that read it earlier does.
+		stale bool
+	}{
+		{name: "replaced after the read", method: http.MethodHead},
+		{name: "replaced after the write", method: http.MethodGet, stale: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			signer := newTestTransparencySigner(t)
+			other := &transparencyIndexer{signer: signer}
+			var repo name.Repository
+			var armed atomic.Bool
+			var writes atomic.Int32
+			registryName := newTestRegistry(t, func(h http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if strings.HasSuffix(r.URL.Path, "/manifests/"+TransparencyIndexTag) {
+						if r.Method == http.MethodPut {
+							writes.Add(1)
+						}
+						// For a stale write, wait for the storer to have written.
+						if r.Method == tt.method && (!tt.stale || writes.Load() > 0) && armed.CompareAndSwap(true, false) {
+							leaves := []string{"sha256:first", "sha256:other"}
+							if err := other.write(repo, leaves); err != nil {
+								t.Errorf("failed to write transparency index: %v", err)
+							}
+						}
+					}
+					h.ServeHTTP(w, r)
+				})
+			})
+			repo = pushRandomImage(t, registryName).Repository
+
+			indexer := &transparencyIndexer{signer: signer}
+			if _, err := indexer.append(repo, "sha256:first"); err != nil {
+				t.Fatalf("failed to append to the transparency index: %v", err)
+			}
+			writes.Store(0)
+			armed.Store(true)
+			idx, err := indexer.append(repo, "sha256:second")
+			if err != nil {
+				t.Fatalf("failed to append to the transparency index: %v", err)
+			}
+			if armed.Load() {
+				t.Fatal("the index was not replaced by the other writer")
+			}
+			for _, leaf := range []string{"sha256:first", "sha256:other", "sha256:second"} {
+				if !slices.Contains(idx.Leaves, leaf) {
+					t.Errorf("the transparency index lost %s, got %v", leaf, idx.Leaves)
+				}
+			}
+		})
+	}
+}
+
+func TestWithTransparencyIndex_RequiresSigner(t *testing.T) {
+	if _, err := NewAttestationStorer(WithTransparencyIndex(true)); err == nil {
+		t.Error("expected an error for a transparency index without a signer")
+	}
+	if _, err := NewAttestationStorer(WithTransparencyIndexSigner(nil)); err == nil {
+		t.Error("expected an error for a nil signer")
+	}
+}
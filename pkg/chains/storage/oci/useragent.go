@@ -0,0 +1,54 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"runtime/debug"
+	"sync"
+)
+
+// chainsModule is the module path of Tekton Chains, whose version is
+// reported in the default user agent.
+const chainsModule = "github.com/tektoncd/chains"
+
+// defaultUserAgent returns the user agent identifying Tekton Chains, e.g.
+// "tekton-chains/v0.25.0", or "tekton-chains/devel" if the version is not
+// recorded in the build information.
+var defaultUserAgent = sync.OnceValue(func() string {
+	version := ""
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if info.Main.Path == chainsModule {
+			version = info.Main.Version
+		}
+		for _, dep := range info.Deps {
+			if dep.Path == chainsModule {
+				version = dep.Version
+			}
+		}
+	}
+	if version == "" || version == "(devel)" {
+		version = "devel"
+	}
+	return "tekton-chains/" + version
+})
+
+// userAgentString returns the user agent configured with WithUserAgent, or the
+// default one.
+func (b *baseStorer) userAgentString() string {
+	if b.userAgent != "" {
+		return b.userAgent
+	}
+	return defaultUserAgent()
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestWithUserAgent(t *testing.T) {
+	for desc, tc := range map[string]struct {
+		opts []Option
+		want string
+	}{
+		"default": {want: "tekton-chains/"},
+		"custom":  {opts: []Option{WithUserAgent("my-controller/1.2")}, want: "my-controller/1.2 "},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			var mu sync.Mutex
+			var userAgents []string
+			registryName := newTestRegistry(t, func(h http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					mu.Lock()
+					userAgents = append(userAgents, r.UserAgent())
+					mu.Unlock()
+					h.ServeHTTP(w, r)
+				})
+			})
+			ref := pushRandomImage(t, registryName)
+			mu.Lock()
+			userAgents = nil
+			mu.Unlock()
+
+			var opts []AttestationStorerOption
+			for _, o := range tc.opts {
+				opts = append(opts, o)
+			}
+			storer, err := NewAttestationStorer(opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			statement, payload := newTestStatement(t, ref, "https://example.com/predicate")
+			if _, err := storer.Store(logtesting.TestContextWithLogger(t), &api.StoreRequest[name.Digest, *intoto.Statement]{
+				Artifact: ref,
+				Payload:  statement,
+				Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if len(userAgents) == 0 {
+				t.Fatal("expected requests to the registry")
+			}
+			for _, ua := range userAgents {
+				if !strings.HasPrefix(ua, tc.want) {
+					t.Errorf("got user agent %q, want prefix %q", ua, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestWithUserAgent_Invalid(t *testing.T) {
+	for _, ua := range []string{"", " ", "agent\r\nX-Injected: true"} {
+		if _, err := NewSimpleStorerFromConfig(WithUserAgent(ua)); err == nil {
+			t.Errorf("expected an error for user agent %q", ua)
+		}
+	}
+}
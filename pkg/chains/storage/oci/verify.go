@@ -0,0 +1,142 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"knative.dev/pkg/logging"
+)
+
+// readBack configures the read-back of written signatures and attestations.
+type readBack struct {
+	// attempts is the total number of reads.
+	attempts int
+	// backoff is the delay before the first retry, doubled after each retry.
+	backoff time.Duration
+}
+
+// defaultReadBack tolerates up to a few seconds of read-after-write lag.
+var defaultReadBack = readBack{attempts: 4, backoff: 500 * time.Millisecond}
+
+// verifyWritten re-reads the manifest at tag and the blobs of layers from the
+// registry and checks that the layers were written with their digests,
+// retrying as configured with WithVerifyAfterWrite while the registry does
+// not serve them yet.
+func (b *baseStorer) verifyWritten(ctx context.Context, tag name.Tag, layers []oci.Signature) error {
+	backoff := b.readBack.backoff
+	for attempt := 1; ; attempt++ {
+		err := readBackLayers(tag, layers, b.remoteOptions(ctx)...)
+		if err == nil || attempt >= b.readBack.attempts {
+			return err
+		}
+		logging.FromContext(ctx).Warnf("Reading back %s failed on attempt %d of %d, retrying in %s: %v", tag.String(), attempt, b.readBack.attempts, backoff, err)
+		if err := sleepContext(ctx, backoff); err != nil {
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+// readBackLayers checks that the manifest at tag holds each of layers and
+// that the registry serves their blobs with the expected digests.
+func readBackLayers(tag name.Tag, layers []oci.Signature, opts ...remote.Option) error {
+	img, err := remote.Image(tag, opts...)
+	if err != nil {
+		return errors.Wrapf(err, "reading back %s", tag)
+	}
+	m, err := img.Manifest()
+	if err != nil {
+		return errors.Wrapf(err, "reading back %s", tag)
+	}
+	written := map[v1.Hash]bool{}
+	for _, desc := range m.Layers {
+		written[desc.Digest] = true
+	}
+	for _, l := range layers {
+		want, err := l.Digest()
+		if err != nil {
+			return err
+		}
+		if !written[want] {
+			return errors.Errorf("layer %s is missing from %s", want, tag)
+		}
+		remoteLayer, err := remote.Layer(tag.Context().Digest(want.String()), opts...)
+		if err != nil {
+			return errors.Wrapf(err, "reading back layer %s of %s", want, tag)
+		}
+		rc, err := remoteLayer.Compressed()
+		if err != nil {
+			return errors.Wrapf(err, "reading back layer %s of %s", want, tag)
+		}
+		got, _, err := v1.SHA256(rc)
+		rc.Close()
+		if err != nil {
+			return errors.Wrapf(err, "reading back layer %s of %s", want, tag)
+		}
+		if got != want {
+			return errors.Errorf("layer %s of %s was read back with digest %s", want, tag, got)
+		}
+	}
+	return nil
+}
+
+// verifyAnnotations re-reads the manifest at tag and checks that the layer
+// written for sig kept all of its annotations. Some registries silently drop
+// annotations they do not recognize.
+func verifyAnnotations(tag name.Tag, sig oci.Signature, opts ...remote.Option) error {
+	want, err := sig.Annotations()
+	if err != nil {
+		return err
+	}
+	d, err := sig.Digest()
+	if err != nil {
+		return err
+	}
+	img, err := remote.Image(tag, opts...)
+	if err != nil {
+		return errors.Wrapf(err, "re-reading %s", tag)
+	}
+	m, err := img.Manifest()
+	if err != nil {
+		return errors.Wrapf(err, "re-reading %s", tag)
+	}
+
+	// The layer was appended, so look for the last layer with its digest.
+	for i := len(m.Layers) - 1; i >= 0; i-- {
+		if m.Layers[i].Digest != d {
+			continue
+		}
+		var missing []string
+		for k, v := range want {
+			if got, ok := m.Layers[i].Annotations[k]; !ok || got != v {
+				missing = append(missing, k)
+			}
+		}
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			return errors.Errorf("registry did not keep annotations %v of layer %s in %s", missing, d, tag)
+		}
+		return nil
+	}
+	return errors.Errorf("layer %s is missing from %s", d, tag)
+}
@@ -0,0 +1,200 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/tektoncd/chains/pkg/chains/formats/simple"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// stripAnnotations emulates a registry that drops the layer annotations of
+// manifests uploaded by tag.
+func stripAnnotations(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut && strings.Contains(r.URL.Path, "/manifests/") && !strings.Contains(r.URL.Path, "/manifests/sha256:") {
+			b, err := io.ReadAll(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			m := map[string]any{}
+			if err := json.Unmarshal(b, &m); err == nil {
+				if layers, ok := m["layers"].([]any); ok {
+					for _, l := range layers {
+						delete(l.(map[string]any), "annotations")
+					}
+				}
+				b, _ = json.Marshal(m)
+			}
+			r.Body = io.NopCloser(bytes.NewReader(b))
+			r.ContentLength = int64(len(b))
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+func TestWithVerifyAnnotations(t *testing.T) {
+	tests := []struct {
+		name       string
+		middleware func(http.Handler) http.Handler
+		opts       []Option
+		wantErr    bool
+	}{
+		{
+			name: "annotations kept",
+			opts: []Option{WithVerifyAnnotations(true)},
+		},
+		{
+			name:       "annotations stripped",
+			middleware: stripAnnotations,
+			opts:       []Option{WithVerifyAnnotations(true)},
+			wantErr:    true,
+		},
+		{
+			name:       "stripped without verification",
+			middleware: stripAnnotations,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := logtesting.TestContextWithLogger(t)
+			registryName := newTestRegistry(t, tt.middleware)
+			ref := pushRandomImage(t, registryName)
+
+			attOpts := []AttestationStorerOption{WithTargetRepository(ref.Repository)}
+			sigOpts := []SimpleStorerOption{WithTargetRepository(ref.Repository)}
+			for _, o := range tt.opts {
+				attOpts = append(attOpts, o)
+				sigOpts = append(sigOpts, o)
+			}
+
+			attStorer, err := NewAttestationStorer(attOpts...)
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+			_, err = attStorer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+				Artifact: ref,
+				Payload:  &intoto.Statement{},
+				Bundle:   &signing.Bundle{Signature: []byte("envelope")},
+			})
+			if gotErr := err != nil; gotErr != tt.wantErr {
+				t.Errorf("AttestationStorer.Store() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			sigStorer, err := NewSimpleStorerFromConfig(sigOpts...)
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+			_, err = sigStorer.Store(ctx, &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+				Artifact: ref,
+				Payload:  simple.NewSimpleStruct(ref),
+				Bundle:   &signing.Bundle{Content: []byte("payload"), Signature: []byte("signature")},
+			})
+			if gotErr := err != nil; gotErr != tt.wantErr {
+				t.Errorf("SimpleStorer.Store() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// lagReads emulates a registry that serves a manifest uploaded by tag only
+// after it was requested lag times.
+func lagReads(lag int) func(http.Handler) http.Handler {
+	var mu sync.Mutex
+	pending := map[string]int{}
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.Contains(r.URL.Path, "/manifests/") {
+				mu.Lock()
+				if r.Method == http.MethodPut {
+					pending[r.URL.Path] = lag
+				} else if pending[r.URL.Path] > 0 {
+					pending[r.URL.Path]--
+					mu.Unlock()
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				mu.Unlock()
+			}
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestWithVerifyAfterWrite(t *testing.T) {
+	tests := []struct {
+		name    string
+		lag     int
+		wantErr bool
+	}{
+		{name: "consistent"},
+		{name: "lagging", lag: 2},
+		{name: "dropped", lag: 100, wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := logtesting.TestContextWithLogger(t)
+			ref := pushRandomImage(t, newTestRegistry(t, lagReads(tc.lag)))
+
+			as, err := NewAttestationStorer(WithVerifyAfterWrite())
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+			as.readBack.backoff = time.Millisecond
+			statement, payload := newTestStatement(t, ref, "https://example.com/predicate")
+			_, err = as.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+				Artifact: ref,
+				Payload:  statement,
+				Bundle:   &signing.Bundle{Signature: newTestEnvelope(t, payload)},
+			})
+			if (err != nil) != tc.wantErr {
+				t.Errorf("attestation Store() error = %v, wantErr %v", err, tc.wantErr)
+			}
+
+			ss, err := NewSimpleStorerFromConfig(WithVerifyAfterWrite())
+			if err != nil {
+				t.Fatalf("failed to create storer: %v", err)
+			}
+			ss.readBack.backoff = time.Millisecond
+			_, err = ss.Store(ctx, &api.StoreRequest[name.Digest, simple.SimpleContainerImage]{
+				Artifact: ref,
+				Payload:  simple.NewSimpleStruct(ref),
+				Bundle:   &signing.Bundle{Content: []byte("{}"), Signature: []byte("signature")},
+			})
+			if (err != nil) != tc.wantErr {
+				t.Errorf("signature Store() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestWithVerifyAfterWrite_LocalLayout(t *testing.T) {
+	if _, err := NewAttestationStorer(WithLocalLayout(t.TempDir()), WithVerifyAfterWrite()); err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webdav
+
+import (
+	"encoding/base64"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// WithHeader adds a header to every request, e.g. an Authorization header
+// carrying a token. Headers given several times are sent with all values.
+func WithHeader(key, value string) AttestationStorerOption {
+	return &headerOption{
+		key:   key,
+		value: value,
+	}
+}
+
+type headerOption struct {
+	key, value string
+}
+
+func (o *headerOption) applyAttestationStorer(s *AttestationStorer) error {
+	if o.key == "" {
+		return errors.New("header name must not be empty")
+	}
+	s.header.Add(o.key, o.value)
+	return nil
+}
+
+// WithBasicAuth authenticates every request with HTTP basic auth.
+func WithBasicAuth(username, password string) AttestationStorerOption {
+	return &basicAuthOption{
+		username: username,
+		password: password,
+	}
+}
+
+type basicAuthOption struct {
+	username, password string
+}
+
+func (o *basicAuthOption) applyAttestationStorer(s *AttestationStorer) error {
+	if o.username == "" {
+		return errors.New("basic auth username must not be empty")
+	}
+	s.header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(o.username+":"+o.password)))
+	return nil
+}
+
+// WithHTTPClient sets the client used to send the requests, e.g. to configure
+// timeouts, proxies or TLS. By default, http.DefaultClient is used.
+func WithHTTPClient(client *http.Client) AttestationStorerOption {
+	return &httpClientOption{
+		client: client,
+	}
+}
+
+type httpClientOption struct {
+	client *http.Client
+}
+
+func (o *httpClientOption) applyAttestationStorer(s *AttestationStorer) error {
+	if o.client == nil {
+		return errors.New("http client must not be nil")
+	}
+	s.client = o.client
+	return nil
+}
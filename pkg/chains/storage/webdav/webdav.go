@@ -0,0 +1,203 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webdav stores attestations on WebDAV servers, or any HTTP server
+// accepting PUT requests, for environments without an OCI registry.
+package webdav
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/pkg/errors"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	"github.com/tektoncd/chains/pkg/chains/storage/oci"
+	"knative.dev/pkg/logging"
+)
+
+// envelopeMediaType is the content type of the stored DSSE envelopes.
+const envelopeMediaType = "application/vnd.dsse.envelope.v1+json"
+
+var (
+	_ api.Storer[name.Digest, *intoto.Statement] = &AttestationStorer{}
+)
+
+// AttestationStorer stores the DSSE envelopes of in-toto attestations with
+// HTTP PUT requests. The envelope of an attestation for the subject
+// sha256:<hex> is stored at <endpoint>/sha256/<hex>/<envelope digest>.json,
+// so attestations of the same subject do not overwrite each other. The
+// endpoint must exist; the collections below it are created with MKCOL when
+// the server rejects an upload because they are missing.
+type AttestationStorer struct {
+	// endpoint is the base URL under which envelopes are stored.
+	endpoint *url.URL
+	// client sends the requests.
+	client *http.Client
+	// header holds the headers, e.g. for auth, added to every request.
+	header http.Header
+}
+
+// AttestationStorerOption provides a config option compatible with AttestationStorer.
+type AttestationStorerOption interface {
+	applyAttestationStorer(s *AttestationStorer) error
+}
+
+// NewAttestationStorer returns a storer that stores attestations under the
+// endpoint URL.
+func NewAttestationStorer(endpoint string, opts ...AttestationStorerOption) (*AttestationStorer, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing endpoint %q", endpoint)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errors.Errorf("endpoint must be an http or https URL, got %q", endpoint)
+	}
+	s := &AttestationStorer{
+		endpoint: u,
+		client:   http.DefaultClient,
+		header:   http.Header{},
+	}
+	for i, o := range opts {
+		if err := o.applyAttestationStorer(s); err != nil {
+			return nil, errors.Wrapf(err, "applying option %d (%T)", i, o)
+		}
+	}
+	return s, nil
+}
+
+// Store uploads the DSSE envelope of the attestation. Like the OCI storers, it
+// rejects requests without a bundle or with an invalid artifact with errors
+// matching oci.ErrMissingBundle and oci.ErrInvalidArtifact.
+func (s *AttestationStorer) Store(ctx context.Context, req *api.StoreRequest[name.Digest, *intoto.Statement]) (*api.StoreResponse, error) {
+	if req.Bundle == nil {
+		return nil, oci.ErrMissingBundle
+	}
+	// The digest names the collections, so it must be valid before uploading.
+	if _, err := v1.NewHash(req.Artifact.DigestStr()); err != nil {
+		return nil, errors.Wrapf(oci.ErrInvalidArtifact, "invalid artifact %q: %v", req.Artifact.String(), err)
+	}
+	envelope := req.Bundle.Signature
+	sum := sha256.Sum256(envelope)
+	target := s.envelopeURL(req.Artifact, hex.EncodeToString(sum[:]))
+
+	err := s.put(ctx, target, envelope)
+	var serr *statusError
+	if errors.As(err, &serr) && serr.code == http.StatusConflict {
+		// WebDAV servers reject uploads to collections that do not exist.
+		if err := s.createCollections(ctx, req.Artifact); err != nil {
+			return nil, errors.Wrapf(err, "creating the collections for the attestations of %s", req.Artifact.String())
+		}
+		err = s.put(ctx, target, envelope)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "uploading attestation of %s", req.Artifact.String())
+	}
+
+	// The reference is recorded and logged, so it must not carry credentials.
+	reference := *target
+	reference.User = nil
+	logging.FromContext(ctx).Infof("Successfully uploaded attestation for %s to %s", req.Artifact.String(), reference.String())
+	return &api.StoreResponse{
+		Reference: reference.String(),
+		Digest:    "sha256:" + hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+// statusError is the error of a request answered with an unexpected status.
+type statusError struct {
+	code   int
+	status string
+	body   string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status %s: %s", e.status, e.body)
+}
+
+// put uploads the envelope to target.
+func (s *AttestationStorer) put(ctx context.Context, target *url.URL, envelope []byte) error {
+	resp, err := s.do(ctx, http.MethodPut, target, bytes.NewReader(envelope), envelopeMediaType)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkStatus(resp)
+}
+
+// createCollections creates the collections, below the endpoint, holding the
+// attestations of the artifact. Collections that already exist are kept.
+func (s *AttestationStorer) createCollections(ctx context.Context, artifact name.Digest) error {
+	algorithm, encoded, _ := strings.Cut(artifact.DigestStr(), ":")
+	for _, u := range []*url.URL{s.endpoint.JoinPath(algorithm), s.endpoint.JoinPath(algorithm, encoded)} {
+		if err := s.mkcol(ctx, u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mkcol creates the collection at u, unless it exists.
+func (s *AttestationStorer) mkcol(ctx context.Context, u *url.URL) error {
+	resp, err := s.do(ctx, "MKCOL", u, nil, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Servers answer that the method is not allowed on existing resources.
+	if resp.StatusCode == http.StatusMethodNotAllowed {
+		return nil
+	}
+	return checkStatus(resp)
+}
+
+// do sends a request with the configured headers.
+func (s *AttestationStorer) do(ctx context.Context, method string, u *url.URL, body io.Reader, contentType string) (*http.Response, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range s.header {
+		httpReq.Header[k] = v
+	}
+	if contentType != "" {
+		httpReq.Header.Set("Content-Type", contentType)
+	}
+	return s.client.Do(httpReq)
+}
+
+// checkStatus returns a statusError unless resp reports a success.
+func checkStatus(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return &statusError{code: resp.StatusCode, status: resp.Status, body: strings.TrimSpace(string(body))}
+}
+
+// envelopeURL returns the URL to store the envelope with the given digest for
+// the artifact at.
+func (s *AttestationStorer) envelopeURL(artifact name.Digest, envelopeDigest string) *url.URL {
+	algorithm, encoded, _ := strings.Cut(artifact.DigestStr(), ":")
+	return s.endpoint.JoinPath(algorithm, encoded, fmt.Sprintf("%s.json", envelopeDigest))
+}
@@ -0,0 +1,244 @@
+// Copyright 2026 The Tekton Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webdav
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/attestation/go/v1"
+	"github.com/tektoncd/chains/pkg/chains/signing"
+	"github.com/tektoncd/chains/pkg/chains/storage/api"
+	"github.com/tektoncd/chains/pkg/chains/storage/oci"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+const testDigest = "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+func TestAttestationStorer_Store(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	envelope := []byte(`{"payloadType":"application/vnd.in-toto+json","payload":"e30=","signatures":[{"sig":"c2ln"}]}`)
+	sum := sha256.Sum256(envelope)
+	envelopeDigest := hex.EncodeToString(sum[:])
+
+	var got *http.Request
+	var gotBody []byte
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		var err error
+		if gotBody, err = io.ReadAll(r.Body); err != nil {
+			t.Errorf("failed to read body: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(s.Close)
+
+	storer, err := NewAttestationStorer(s.URL+"/attestations", WithBasicAuth("user", "pass"), WithHeader("X-Tenant", "example"))
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	ref, err := name.NewDigest("registry.example.com/test/img@" + testDigest)
+	if err != nil {
+		t.Fatalf("failed to parse digest: %v", err)
+	}
+	resp, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Payload:  &intoto.Statement{},
+		Bundle:   &signing.Bundle{Signature: envelope},
+	})
+	if err != nil {
+		t.Fatalf("error during Store(): %v", err)
+	}
+
+	if got == nil {
+		t.Fatal("no request received")
+	}
+	if got.Method != http.MethodPut {
+		t.Errorf("method = %s, want %s", got.Method, http.MethodPut)
+	}
+	wantPath := "/attestations/sha256/e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855/" + envelopeDigest + ".json"
+	if got.URL.Path != wantPath {
+		t.Errorf("path = %s, want %s", got.URL.Path, wantPath)
+	}
+	if string(gotBody) != string(envelope) {
+		t.Errorf("body = %s, want %s", gotBody, envelope)
+	}
+	if user, pass, ok := got.BasicAuth(); !ok || user != "user" || pass != "pass" {
+		t.Errorf("basic auth = %q, %q, %v, want user, pass", user, pass, ok)
+	}
+	if v := got.Header.Get("X-Tenant"); v != "example" {
+		t.Errorf("X-Tenant header = %q, want %q", v, "example")
+	}
+	if v := got.Header.Get("Content-Type"); v != envelopeMediaType {
+		t.Errorf("Content-Type header = %q, want %q", v, envelopeMediaType)
+	}
+	if want := s.URL + wantPath; resp.Reference != want {
+		t.Errorf("StoreResponse.Reference = %s, want %s", resp.Reference, want)
+	}
+	if want := "sha256:" + envelopeDigest; resp.Digest != want {
+		t.Errorf("StoreResponse.Digest = %s, want %s", resp.Digest, want)
+	}
+}
+
+func TestAttestationStorer_StoreErrors(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	var mu sync.Mutex
+	var requests int
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		http.Error(w, "denied", http.StatusForbidden)
+	}))
+	t.Cleanup(s.Close)
+
+	storer, err := NewAttestationStorer(s.URL)
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	ref, err := name.NewDigest("registry.example.com/test/img@" + testDigest)
+	if err != nil {
+		t.Fatalf("failed to parse digest: %v", err)
+	}
+	if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: ref,
+		Bundle:   &signing.Bundle{Signature: []byte("{}")},
+	}); err == nil {
+		t.Error("expected an error for a rejected upload")
+	}
+
+	mu.Lock()
+	requests = 0
+	mu.Unlock()
+	if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{Artifact: ref}); !errors.Is(err, oci.ErrMissingBundle) {
+		t.Errorf("Store() error = %v, want %v", err, oci.ErrMissingBundle)
+	}
+	if _, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+		Artifact: name.Digest{},
+		Bundle:   &signing.Bundle{Signature: []byte("{}")},
+	}); !errors.Is(err, oci.ErrInvalidArtifact) {
+		t.Errorf("Store() error = %v, want %v", err, oci.ErrInvalidArtifact)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if requests != 0 {
+		t.Errorf("invalid requests sent %d requests, want none", requests)
+	}
+}
+
+func TestAttestationStorer_StoreCreatesCollections(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	// The server behaves as a WebDAV server does, answering 409 Conflict for
+	// resources whose parent collection does not exist.
+	var mu sync.Mutex
+	collections := map[string]bool{"/attestations": true}
+	var mkcols []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !collections[path.Dir(r.URL.Path)] {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		switch r.Method {
+		case "MKCOL":
+			if collections[r.URL.Path] {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			mkcols = append(mkcols, r.URL.Path)
+			collections[r.URL.Path] = true
+		case http.MethodPut:
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(s.Close)
+
+	u, err := url.Parse(s.URL + "/attestations")
+	if err != nil {
+		t.Fatalf("failed to parse URL: %v", err)
+	}
+	u.User = url.UserPassword("user", "secret")
+	storer, err := NewAttestationStorer(u.String())
+	if err != nil {
+		t.Fatalf("failed to create storer: %v", err)
+	}
+	ref, err := name.NewDigest("registry.example.com/test/img@" + testDigest)
+	if err != nil {
+		t.Fatalf("failed to parse digest: %v", err)
+	}
+	for _, envelope := range []string{"{}", `{"payload":""}`} {
+		resp, err := storer.Store(ctx, &api.StoreRequest[name.Digest, *intoto.Statement]{
+			Artifact: ref,
+			Bundle:   &signing.Bundle{Signature: []byte(envelope)},
+		})
+		if err != nil {
+			t.Fatalf("error during Store(): %v", err)
+		}
+		if strings.Contains(resp.Reference, "secret") || strings.Contains(resp.Reference, "user@") {
+			t.Errorf("StoreResponse.Reference = %s, want it without credentials", resp.Reference)
+		}
+	}
+	want := []string{"/attestations/sha256", "/attestations/sha256/e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"}
+	if diff := cmp.Diff(want, mkcols); diff != "" {
+		t.Errorf("unexpected collections created (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewAttestationStorer_Invalid(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		endpoint string
+		opts     []AttestationStorerOption
+	}{{
+		name:     "relative endpoint",
+		endpoint: "attestations",
+	}, {
+		name:     "unsupported scheme",
+		endpoint: "ftp://example.com/attestations",
+	}, {
+		name:     "empty header name",
+		endpoint: "https://example.com",
+		opts:     []AttestationStorerOption{WithHeader("", "value")},
+	}, {
+		name:     "empty username",
+		endpoint: "https://example.com",
+		opts:     []AttestationStorerOption{WithBasicAuth("", "pass")},
+	}, {
+		name:     "nil client",
+		endpoint: "https://example.com",
+		opts:     []AttestationStorerOption{WithHTTPClient(nil)},
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewAttestationStorer(tc.endpoint, tc.opts...); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}